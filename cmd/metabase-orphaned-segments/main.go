@@ -155,7 +155,7 @@ func Delete(ctx context.Context, log *zap.Logger, config Config) (err error) {
 func findOrphanedSegments(ctx context.Context, log *zap.Logger, config Config) (_ []uuid.UUID, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	metabaseDB, err := metabase.Open(ctx, log.Named("metabase"), config.MetabaseDB)
+	metabaseDB, err := metabase.Open(ctx, log.Named("metabase"), config.MetabaseDB, metabase.Config{})
 	if err != nil {
 		return nil, errs.New("unable to connect %q: %w", config.MetabaseDB, err)
 	}
@@ -168,7 +168,7 @@ func findOrphanedSegments(ctx context.Context, log *zap.Logger, config Config) (
 
 	streamIDs := make(map[uuid.UUID]struct{})
 	numberOfElements := 0
-	err = metabaseDB.IterateLoopSegments(ctx, metabase.IterateLoopSegments{
+	_, err = metabaseDB.IterateLoopSegments(ctx, metabase.IterateLoopSegments{
 		BatchSize:      config.LoopBatchSize,
 		AsOfSystemTime: startingTime,
 	}, func(ctx context.Context, it metabase.LoopSegmentsIterator) error {
@@ -194,9 +194,10 @@ func findOrphanedSegments(ctx context.Context, log *zap.Logger, config Config) (
 	}
 
 	numberOfElements = 0
-	err = metabaseDB.IterateLoopObjects(ctx, metabase.IterateLoopObjects{
+	_, err = metabaseDB.IterateLoopObjects(ctx, metabase.IterateLoopObjects{
 		BatchSize:      config.LoopBatchSize,
 		AsOfSystemTime: startingTime,
+		Fields:         metabase.LoopObjectCreatedAt,
 	}, func(ctx context.Context, it metabase.LoopObjectsIterator) error {
 		var entry metabase.LoopObjectEntry
 		for it.Next(ctx, &entry) {
@@ -0,0 +1,83 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/peertls/tlsopts"
+	"storj.io/common/rpc"
+	"storj.io/private/process"
+	"storj.io/private/version"
+	"storj.io/storj/private/revocation"
+	"storj.io/storj/storagenode/preflight"
+	"storj.io/storj/storagenode/trust"
+)
+
+func cmdSelfTest(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+	log := zap.L()
+
+	identity, err := diagCfg.Identity.Load()
+	if err != nil {
+		return errs.New("Failed to load identity: %+v", err)
+	}
+
+	revocationDB, err := revocation.OpenDBFromCfg(ctx, diagCfg.Server.Config)
+	if err != nil {
+		return errs.New("Error creating revocation database: %+v", err)
+	}
+	defer func() {
+		err = errs.Combine(err, revocationDB.Close())
+	}()
+
+	tlsOptions, err := tlsopts.NewOptions(identity, diagCfg.Server.Config, revocationDB)
+	if err != nil {
+		return err
+	}
+	dialer := rpc.NewDefaultDialer(tlsOptions)
+
+	pool, err := trust.NewPool(log.Named("trust"), trust.Dialer(dialer), diagCfg.Storage2.Trust, nil)
+	if err != nil {
+		return err
+	}
+	if err := pool.Refresh(ctx); err != nil {
+		return errs.New("Failed to fetch trusted satellites: %+v", err)
+	}
+
+	report, err := preflight.NewSelfTest(log.Named("selftest"), version.Build, pool, dialer).Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Version: %s\n", report.Version.Version.String())
+	fmt.Printf("Commit:  %s\n", report.Version.CommitHash)
+	fmt.Printf("Release: %t\n\n", report.Version.Release)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer func() { err = errs.Combine(err, w.Flush()) }()
+
+	fmt.Fprint(w, "Satellite\tAddress\tStatus\n")
+
+	unreachable := 0
+	for _, satellite := range report.Satellites {
+		status := "reachable"
+		if !satellite.Reachable() {
+			unreachable++
+			status = fmt.Sprintf("unreachable: %v", satellite.Err)
+		}
+		fmt.Fprintf(w, "%v\t%s\t%s\n", satellite.ID, satellite.Address, status)
+	}
+
+	if unreachable > 0 {
+		return errs.New("%d of %d trusted satellites are unreachable", unreachable, len(report.Satellites))
+	}
+	return nil
+}
@@ -230,7 +230,7 @@ func getAccessFromArgZeroOrConfig(config AccessConfig, args []string) (access *u
 		}
 		return uplink.ParseAccess(args[0])
 	}
-	return config.GetAccess()
+	return config.withAccessEnv().GetAccess()
 }
 
 // DisplayGatewayCredentials formats and writes credentials to stdout.
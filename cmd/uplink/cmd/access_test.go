@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"os/exec"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,6 +18,7 @@ import (
 
 	"storj.io/common/testcontext"
 	"storj.io/storj/cmd/uplink/cmd"
+	"storj.io/storj/private/testplanet"
 	"storj.io/uplink"
 )
 
@@ -73,3 +76,39 @@ func TestAccessImport(t *testing.T) {
 	t.Log(string(output))
 	require.NoError(t, err)
 }
+
+func TestAccessFromEnvironment(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		uplinkExe := ctx.Compile("storj.io/storj/cmd/uplink")
+
+		access, err := planet.Uplinks[0].Access[planet.Satellites[0].ID()].Serialize()
+		require.NoError(t, err)
+
+		run := func(env string, args ...string) (string, error) {
+			command := exec.Command(uplinkExe, append([]string{"--config-dir", ctx.Dir("uplink")}, args...)...)
+			command.Env = append(os.Environ(), "UPLINK_ACCESS="+env)
+			output, err := command.CombinedOutput()
+			t.Log(string(output))
+			return string(output), err
+		}
+
+		// the access grant is read from the environment when there's no config.
+		output, err := run(access, "ls")
+		require.NoError(t, err)
+		require.False(t, strings.Contains(output, access))
+
+		// the commands with their own access configuration read it as well.
+		_, err = run(access, "share", "--not-after", "+1h")
+		require.NoError(t, err)
+		_, err = run(access, "access", "inspect")
+		require.NoError(t, err)
+
+		// the --access flag takes precedence over the environment.
+		_, err = run("invalid", "ls")
+		require.Error(t, err)
+		_, err = run("invalid", "--access", access, "ls")
+		require.NoError(t, err)
+	})
+}
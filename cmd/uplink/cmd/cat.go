@@ -42,5 +42,5 @@ func catMain(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
-	return download(ctx, src, dst, false)
+	return download(ctx, src, dst, false, nil)
 }
@@ -0,0 +1,60 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+)
+
+// contentTypeKey is the custom metadata key of the content type of an object,
+// the same key that the gateway stores it with and linksharing serves it from.
+const contentTypeKey = "Content-Type"
+
+// sniffLength is the number of bytes http.DetectContentType considers.
+const sniffLength = 512
+
+// detectContentType detects the content type from the extension of the name,
+// or else from the first bytes of the content. It returns an empty string if
+// neither gives a specific content type.
+func detectContentType(name string, head []byte) string {
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		return contentType
+	}
+	if len(head) > 0 {
+		// application/octet-stream is what any content is detected as.
+		if contentType := http.DetectContentType(head); contentType != "application/octet-stream" {
+			return contentType
+		}
+	}
+	return ""
+}
+
+// readHead reads the first bytes of the file for detecting the content type.
+// Regular files are read without moving their offset, while the bytes read
+// from anything else must be read again from head before the rest.
+func readHead(file *os.File, regular bool) (head []byte, err error) {
+	head = make([]byte, sniffLength)
+
+	var n int
+	if regular {
+		n, err = file.ReadAt(head, 0)
+	} else {
+		n, err = io.ReadFull(file, head)
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = nil
+	}
+	return head[:n], err
+}
+
+// validateContentType checks that the content type is a valid media type.
+func validateContentType(contentType string) error {
+	_, _, err := mime.ParseMediaType(contentType)
+	return err
+}
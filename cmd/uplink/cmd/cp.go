@@ -4,6 +4,7 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -11,6 +12,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	progressbar "github.com/cheggaaa/pb/v3"
@@ -18,6 +20,7 @@ import (
 	"github.com/zeebo/errs"
 
 	"storj.io/common/fpath"
+	"storj.io/common/memory"
 	"storj.io/uplink"
 	"storj.io/uplink/private/object"
 )
@@ -26,27 +29,61 @@ var (
 	progress    *bool
 	expires     *string
 	metadata    *string
+	cpTags      *[]string
+	cpTagArgs   []string
+	contentType *string
+	showTags    *bool
 	parallelism *int
+	limitRate   *string
+	ifNotExists *bool
+
+	maxMetadataSize *string
+	resume          *bool
+	abortStale      *bool
+	partSize        *string
+	progressFormat  *string
 )
 
 func init() {
 	cpCmd := addCmd(&cobra.Command{
-		Use:   "cp SOURCE DESTINATION",
-		Short: "Copies a local file or Storj object to another location locally or in Storj",
-		RunE:  copyMain,
-		Args:  cobra.ExactArgs(2),
+		Use:     "cp SOURCE DESTINATION",
+		Short:   "Copies a local file or Storj object to another location locally or in Storj",
+		PreRunE: checkTagFlag,
+		RunE:    copyMain,
+		Args:    cobra.ExactArgs(2),
 	}, RootCmd)
 
 	progress = cpCmd.Flags().Bool("progress", true, "if true, show progress")
+	progressFormat = cpCmd.Flags().String("progress-format", "bar", "format of the progress, either bar or json. The json format prints lines of {\"transferred\",\"total\",\"percent\"} to stderr")
 	expires = cpCmd.Flags().String("expires", "", "optional expiration date of an object. Please use format (yyyy-mm-ddThh:mm:ssZhh:mm)")
 	metadata = cpCmd.Flags().String("metadata", "", "optional metadata for the object. Please use a single level JSON object of string to string only")
+	cpTags = cpCmd.Flags().StringArray("tag", nil, "optional tag for the object as key=value, can be repeated. Tags are stored in the metadata with the \""+tagPrefix+"\" key prefix")
+	contentType = cpCmd.Flags().String("content-type", "", "optional content type of the object. When not set, it's detected from the file extension or the content. It's stored in the metadata as \""+contentTypeKey+"\"")
+	showTags = cpCmd.Flags().Bool("show-tags", false, "if true, show the tags of the downloaded object")
 	parallelism = cpCmd.Flags().Int("parallelism", 1, "controls how many parallel downloads of a single object will be performed")
-
-	setBasicFlags(cpCmd.Flags(), "progress", "expires", "metadata")
+	limitRate = cpCmd.Flags().String("limit-rate", "", "optional maximum transfer rate per second, e.g. 2MiB. Unlimited if not set")
+	ifNotExists = cpCmd.Flags().Bool("if-not-exists", false, "if true, skip the copy when the destination already exists")
+	resume = cpCmd.Flags().Bool("resume", false, "if true, upload as a multipart upload which continues an uncommitted upload of a previous attempt to the same destination. The source file must not change between the attempts")
+	partSize = cpCmd.Flags().String("part-size", "64MiB", "size of the parts of uploads with --resume")
+	abortStale = cpCmd.Flags().Bool("abort-stale", false, "if true, with --resume, abort an uncommitted upload to the destination which doesn't match the source file and start a new one, instead of failing")
+	maxMetadataSize = cpCmd.Flags().String("max-metadata-size", "2KiB", "maximum total size of the keys and values of the metadata, checked before uploading. Unlimited if set to 0")
+
+	setBasicFlags(cpCmd.Flags(), "progress", "expires", "metadata", "tag", "content-type", "limit-rate", "if-not-exists")
 }
 
 // upload transfers src from local machine to s3 compatible object dst.
-func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, expiration time.Time, metadata []byte, showProgress bool) (err error) {
+//
+// With a positive resumePartSize, the object is uploaded with resumableUpload,
+// which aborts a mismatching uncommitted upload only with abortStale.
+//
+// The tags are added to the metadata with the tag prefix, which the keys of
+// metadata must not use.
+//
+// The content type is stored in the metadata, unless the metadata already has
+// one. When contentType is empty, it's detected from the file.
+func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, expiration time.Time, metadata []byte, tags map[string]string, contentType string, metadataLimit memory.Size, showProgress bool, limiter *rateLimiter, resumePartSize memory.Size, abortStale bool) (err error) {
+	start := time.Now()
+
 	if !src.IsLocal() {
 		return fmt.Errorf("source must be local path: %s", src)
 	}
@@ -55,6 +92,23 @@ func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, expiration ti
 		return fmt.Errorf("destination must be Storj URL: %s", dst)
 	}
 
+	var customMetadata uplink.CustomMetadata
+	if len(metadata) > 0 {
+		err := json.Unmarshal(metadata, &customMetadata)
+		if err != nil {
+			return err
+		}
+		for key := range customMetadata {
+			if strings.HasPrefix(key, tagPrefix) {
+				return fmt.Errorf("metadata key %q uses the prefix %q reserved for tags, use --tag instead", key, tagPrefix)
+			}
+		}
+	}
+	if customMetadata == nil {
+		customMetadata = make(uplink.CustomMetadata, len(tags)+1)
+	}
+	addTags(customMetadata, tags)
+
 	// if object name not specified, default to filename
 	if strings.HasSuffix(dst.String(), "/") || dst.Path() == "" {
 		dst = dst.Join(src.Base())
@@ -62,6 +116,9 @@ func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, expiration ti
 
 	var file *os.File
 	if src.Base() == "-" {
+		if resumePartSize > 0 {
+			return fmt.Errorf("uploads from standard input can't be resumed")
+		}
 		file = os.Stdin
 	} else {
 		file, err = os.Open(src.Path())
@@ -80,30 +137,90 @@ func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, expiration ti
 		return fmt.Errorf("source cannot be a directory: %s", src)
 	}
 
+	var source io.Reader = file
+	if contentType != "" {
+		customMetadata[contentTypeKey] = contentType
+	} else if _, ok := customMetadata[contentTypeKey]; !ok {
+		// the destination name is the only name of standard input.
+		name := src.Base()
+		if file == os.Stdin {
+			name = dst.Path()
+		}
+
+		detected := detectContentType(name, nil)
+		if detected == "" {
+			head, err := readHead(file, fileInfo.Mode().IsRegular())
+			if err != nil {
+				return err
+			}
+			if !fileInfo.Mode().IsRegular() {
+				source = io.MultiReader(bytes.NewReader(head), file)
+			}
+			detected = detectContentType(name, head)
+		}
+		if detected != "" {
+			customMetadata[contentTypeKey] = detected
+		}
+	}
+
+	// the metadata is checked before anything is transferred, since the
+	// satellite only rejects metadata that is too large on commit.
+	if err := customMetadata.Verify(); err != nil {
+		return err
+	}
+	var size memory.Size
+	for key, value := range customMetadata {
+		size += memory.Size(len(key) + len(value))
+	}
+	if metadataLimit > 0 && size > metadataLimit {
+		return fmt.Errorf("metadata is too large, got %v, maximum allowed is %v", size, metadataLimit)
+	}
+
 	project, err := cfg.getProject(ctx, false)
 	if err != nil {
 		return err
 	}
 	defer closeProject(project)
 
-	reader := io.Reader(file)
-	var bar *progressbar.ProgressBar
+	if *ifNotExists {
+		exists, err := objectExists(ctx, project, dst)
+		if err != nil {
+			return err
+		}
+		if exists {
+			printCopyResult("skip", src, dst, 0, start, fmt.Sprintf("Skipped %s, %s already exists", src.String(), dst.String()), nil)
+			return nil
+		}
+	}
+
+	var bar *progressBar
 	if showProgress {
-		bar = progressbar.New64(fileInfo.Size())
-		reader = bar.NewProxyReader(reader)
+		bar = newProgressBar(fileInfo.Size())
 		bar.Start()
 	}
 
-	var customMetadata uplink.CustomMetadata
-	if len(metadata) > 0 {
-		err := json.Unmarshal(metadata, &customMetadata)
+	if resumePartSize > 0 {
+		written, skipped, err := resumableUpload(ctx, project, file, fileInfo.Size(), dst, expiration, customMetadata, resumePartSize, abortStale, bar, limiter)
 		if err != nil {
 			return err
 		}
 
-		if err := customMetadata.Verify(); err != nil {
-			return err
+		if bar != nil {
+			bar.Finish()
+		}
+
+		text := fmt.Sprintf("Created %s", dst.String())
+		if skipped > 0 {
+			text = fmt.Sprintf("Created %s, resumed after %d already uploaded bytes", dst.String(), skipped)
 		}
+		printCopyResult("upload", src, dst, written, start, text, nil)
+
+		return nil
+	}
+
+	reader := limiter.Reader(source)
+	if bar != nil {
+		reader = bar.NewProxyReader(reader)
 	}
 
 	upload, err := project.UploadObject(ctx, dst.Bucket(), dst.Path(), &uplink.UploadOptions{
@@ -120,7 +237,7 @@ func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, expiration ti
 		return err
 	}
 
-	_, err = io.Copy(upload, reader)
+	written, err := io.Copy(upload, reader)
 	if err != nil {
 		abortErr := upload.Abort()
 		err = errs.Combine(err, abortErr)
@@ -135,7 +252,7 @@ func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, expiration ti
 		bar.Finish()
 	}
 
-	fmt.Printf("Created %s\n", dst.String())
+	printCopyResult("upload", src, dst, written, start, fmt.Sprintf("Created %s", dst.String()), nil)
 
 	return nil
 }
@@ -143,7 +260,7 @@ func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, expiration ti
 // WriterAt wraps writer and progress bar to display progress correctly.
 type WriterAt struct {
 	object.WriterAt
-	bar *progressbar.ProgressBar
+	bar *progressBar
 }
 
 // WriteAt writes bytes to wrapped writer and add amount of bytes to progress bar.
@@ -160,7 +277,9 @@ func (w *WriterAt) Truncate(size int64) error {
 }
 
 // download transfers s3 compatible object src to dst on local machine.
-func download(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgress bool) (err error) {
+func download(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgress bool, limiter *rateLimiter) (err error) {
+	start := time.Now()
+
 	if src.IsLocal() {
 		return fmt.Errorf("source must be Storj URL: %s", src)
 	}
@@ -173,6 +292,10 @@ func download(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgres
 		return fmt.Errorf("parallelism must be at least 1")
 	}
 
+	if *showTags && dst.Base() == "-" {
+		return fmt.Errorf("tags can't be shown when downloading to standard output")
+	}
+
 	project, err := cfg.getProject(ctx, false)
 	if err != nil {
 		return err
@@ -183,6 +306,15 @@ func download(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgres
 		dst = dst.Join(src.Base())
 	}
 
+	if *ifNotExists && dst.Base() != "-" {
+		if _, err := os.Stat(dst.Path()); err == nil {
+			printCopyResult("skip", src, dst, 0, start, fmt.Sprintf("Skipped %s, %s already exists", src.String(), dst.String()), nil)
+			return nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
 	var file *os.File
 	if dst.Base() == "-" {
 		file = os.Stdout
@@ -198,41 +330,43 @@ func download(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgres
 		}()
 	}
 
-	var bar *progressbar.ProgressBar
+	var bar *progressBar
+	var written int64
+	var info *uplink.Object
 	if *parallelism <= 1 {
 		download, err := project.DownloadObject(ctx, src.Bucket(), src.Path(), nil)
 		if err != nil {
 			return err
 		}
 		defer func() { err = errs.Combine(err, download.Close()) }()
+		info = download.Info()
 
-		var reader io.ReadCloser
+		reader := limiter.Reader(download)
 		if showProgress {
-			info := download.Info()
-			bar = progressbar.New64(info.System.ContentLength)
-			reader = bar.NewProxyReader(download)
+			bar = newProgressBar(info.System.ContentLength)
+			reader = bar.NewProxyReader(reader)
 			bar.Start()
-		} else {
-			reader = download
 		}
 
-		_, err = io.Copy(file, reader)
+		written, err = io.Copy(file, reader)
 	} else {
 		var writer object.WriterAt
 		if showProgress {
-			bar = progressbar.New64(0)
+			bar = newProgressBar(0)
 			bar.Set(progressbar.Bytes, true)
 			writer = &WriterAt{file, bar}
 			bar.Start()
 		} else {
 			writer = file
 		}
+		counter := &countingWriterAt{WriterAt: limiter.WriterAt(writer)}
 
 		// final DownloadObjectAt method signature is under design so we can still have some
 		// inconsistency between naming e.g. concurrency - parallelism.
-		err = object.DownloadObjectAt(ctx, project, src.Bucket(), src.Path(), writer, &object.DownloadObjectAtOptions{
+		err = object.DownloadObjectAt(ctx, project, src.Bucket(), src.Path(), counter, &object.DownloadObjectAtOptions{
 			Concurrency: *parallelism,
 		})
+		written = atomic.LoadInt64(&counter.written)
 	}
 
 	if bar != nil {
@@ -243,7 +377,24 @@ func download(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgres
 	}
 
 	if dst.Base() != "-" {
-		fmt.Printf("Downloaded %s to %s\n", src.String(), dst.String())
+		text := fmt.Sprintf("Downloaded %s to %s", src.String(), dst.String())
+
+		var tags map[string]string
+		if *showTags {
+			// parallel downloads don't return the object, so it's looked up.
+			if info == nil {
+				info, err = project.StatObject(ctx, src.Bucket(), src.Path())
+				if err != nil {
+					return err
+				}
+			}
+			tags = objectTags(info.Custom)
+			if len(tags) > 0 {
+				text += "\n" + formatTags(tags)
+			}
+		}
+
+		printCopyResult("download", src, dst, written, start, text, tags)
 	}
 
 	return nil
@@ -251,6 +402,8 @@ func download(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgres
 
 // copy copies s3 compatible object src to s3 compatible object dst.
 func copyObject(ctx context.Context, src fpath.FPath, dst fpath.FPath) (err error) {
+	start := time.Now()
+
 	if src.IsLocal() {
 		return fmt.Errorf("source must be Storj URL: %s", src)
 	}
@@ -265,64 +418,158 @@ func copyObject(ctx context.Context, src fpath.FPath, dst fpath.FPath) (err erro
 	}
 	defer closeProject(project)
 
-	download, err := project.DownloadObject(ctx, src.Bucket(), src.Path(), nil)
+	// if destination object name not specified, default to source object name
+	if strings.HasSuffix(dst.Path(), "/") {
+		dst = dst.Join(src.Base())
+	}
+
+	if *ifNotExists {
+		exists, err := objectExists(ctx, project, dst)
+		if err != nil {
+			return err
+		}
+		if exists {
+			printCopyResult("skip", src, dst, 0, start, fmt.Sprintf("Skipped %s, %s already exists", src.String(), dst.String()), nil)
+			return nil
+		}
+	}
+
+	written, err := transferObject(ctx, project, project, src, dst, *progress)
 	if err != nil {
 		return err
 	}
+
+	printCopyResult("copy", src, dst, written, start, fmt.Sprintf("%s copied to %s", src.String(), dst.String()), nil)
+
+	return nil
+}
+
+// transferObject downloads src from srcProject and uploads it as dst to
+// dstProject, keeping its expiration and custom metadata. The projects may be
+// opened with different accesses, in which case the object is re-encrypted.
+func transferObject(ctx context.Context, srcProject, dstProject *uplink.Project, src, dst fpath.FPath, showProgress bool) (written int64, err error) {
+	download, err := srcProject.DownloadObject(ctx, src.Bucket(), src.Path(), nil)
+	if err != nil {
+		return 0, err
+	}
 	defer func() { err = errs.Combine(err, download.Close()) }()
 
 	downloadInfo := download.Info()
 
-	var bar *progressbar.ProgressBar
+	var bar *progressBar
 	var reader io.Reader
-	if *progress {
-		bar = progressbar.New64(downloadInfo.System.ContentLength)
+	if showProgress {
+		bar = newProgressBar(downloadInfo.System.ContentLength)
 		reader = bar.NewProxyReader(download)
 		bar.Start()
 	} else {
 		reader = download
 	}
 
-	// if destination object name not specified, default to source object name
-	if strings.HasSuffix(dst.Path(), "/") {
-		dst = dst.Join(src.Base())
-	}
-
-	upload, err := project.UploadObject(ctx, dst.Bucket(), dst.Path(), &uplink.UploadOptions{
+	upload, err := dstProject.UploadObject(ctx, dst.Bucket(), dst.Path(), &uplink.UploadOptions{
 		Expires: downloadInfo.System.Expires,
 	})
+	if err != nil {
+		return 0, err
+	}
 
-	_, err = io.Copy(upload, reader)
+	written, err = io.Copy(upload, reader)
 	if err != nil {
 		abortErr := upload.Abort()
-		return errs.Combine(err, abortErr)
+		return 0, errs.Combine(err, abortErr)
 	}
 
 	err = upload.SetCustomMetadata(ctx, downloadInfo.Custom)
 	if err != nil {
 		abortErr := upload.Abort()
-		return errs.Combine(err, abortErr)
+		return 0, errs.Combine(err, abortErr)
 	}
 
 	err = upload.Commit()
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	if bar != nil {
 		bar.Finish()
 	}
+
+	return written, nil
+}
+
+// countingWriterAt counts the bytes written through it.
+type countingWriterAt struct {
+	object.WriterAt
+	written int64
+}
+
+// WriteAt writes bytes to the wrapped writer and counts them.
+func (w *countingWriterAt) WriteAt(p []byte, off int64) (n int, err error) {
+	n, err = w.WriterAt.WriteAt(p, off)
+	atomic.AddInt64(&w.written, int64(n))
+	return n, err
+}
+
+// copyResult is a completed operation as printed by cp with --output json.
+type copyResult struct {
+	Action      string `json:"action"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Bytes       int64  `json:"bytes"`
+	DurationMs  int64  `json:"durationMs"`
+	// Tags are only set for downloads with --show-tags.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// printCopyResult prints the completed operation in the output format of cp.
+// text is the line printed in the text format.
+func printCopyResult(action string, src, dst fpath.FPath, bytes int64, start time.Time, text string, tags map[string]string) {
+	if outputFormat != "json" {
+		fmt.Println(text)
+		return
+	}
+
+	err := json.NewEncoder(os.Stdout).Encode(copyResult{
+		Action:      action,
+		Source:      src.String(),
+		Destination: dst.String(),
+		Bytes:       bytes,
+		DurationMs:  time.Since(start).Milliseconds(),
+		Tags:        tags,
+	})
 	if err != nil {
-		return err
+		fmt.Fprintf(os.Stderr, "error printing result: %+v\n", err)
 	}
+}
 
-	fmt.Printf("%s copied to %s\n", src.String(), dst.String())
+// objectExists returns whether the object dst exists.
+func objectExists(ctx context.Context, project *uplink.Project, dst fpath.FPath) (bool, error) {
+	_, err := project.StatObject(ctx, dst.Bucket(), dst.Path())
+	if errors.Is(err, uplink.ErrObjectNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
+// checkTagFlag keeps the arguments of --tag as they were parsed from the
+// command line. process sets the flags again from their string form before
+// running the command, which appends that form to a StringArray flag.
+func checkTagFlag(cmd *cobra.Command, args []string) (err error) {
+	cpTagArgs = append([]string(nil), *cpTags...)
 	return nil
 }
 
 // copyMain is the function executed when cpCmd is called.
 func copyMain(cmd *cobra.Command, args []string) (err error) {
+	switch *progressFormat {
+	case "bar", "json":
+	default:
+		return fmt.Errorf("invalid progress format: %s", *progressFormat)
+	}
+
 	if len(args) == 0 {
 		return fmt.Errorf("no object specified for copy")
 	}
@@ -347,6 +594,19 @@ func copyMain(cmd *cobra.Command, args []string) (err error) {
 		return errors.New("at least one of the source or the destination must be a Storj URL")
 	}
 
+	var rateLimit memory.Size
+	if *limitRate != "" {
+		if err := rateLimit.Set(*limitRate); err != nil {
+			return fmt.Errorf("invalid rate limit (%s): %w", *limitRate, err)
+		}
+	}
+	limiter := newRateLimiter(ctx, rateLimit)
+
+	var metadataLimit memory.Size
+	if err := metadataLimit.Set(*maxMetadataSize); err != nil {
+		return fmt.Errorf("invalid max metadata size (%s): %w", *maxMetadataSize, err)
+	}
+
 	// if uploading
 	if src.IsLocal() {
 		var expiration time.Time
@@ -360,12 +620,33 @@ func copyMain(cmd *cobra.Command, args []string) (err error) {
 			}
 		}
 
-		return upload(ctx, src, dst, expiration, []byte(*metadata), *progress)
+		var resumePartSize memory.Size
+		if *resume {
+			if err := resumePartSize.Set(*partSize); err != nil {
+				return fmt.Errorf("invalid part size (%s): %w", *partSize, err)
+			}
+			if resumePartSize <= 0 {
+				return fmt.Errorf("invalid part size (%s): must be positive", *partSize)
+			}
+		}
+
+		if *contentType != "" {
+			if err := validateContentType(*contentType); err != nil {
+				return fmt.Errorf("invalid content type (%s): %w", *contentType, err)
+			}
+		}
+
+		tags, err := parseTags(cpTagArgs)
+		if err != nil {
+			return err
+		}
+
+		return upload(ctx, src, dst, expiration, []byte(*metadata), tags, *contentType, metadataLimit, *progress, limiter, resumePartSize, *abortStale)
 	}
 
 	// if downloading
 	if dst.IsLocal() {
-		return download(ctx, src, dst, *progress)
+		return download(ctx, src, dst, *progress, limiter)
 	}
 
 	// if copying from one remote location to another
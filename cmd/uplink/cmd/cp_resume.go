@@ -0,0 +1,194 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/fpath"
+	"storj.io/common/memory"
+	"storj.io/uplink"
+)
+
+// resumableUpload uploads file to dst as a multipart upload with parts of
+// partSize. Every part is uploaded with the SHA-256 hash of its content as its
+// ETag. When there is an uncommitted upload to dst, its parts are kept when
+// they have the size and the hash of the same range of file, and only the
+// missing parts are uploaded. Otherwise, when any part doesn't match the file,
+// an error describing the uncommitted upload is returned, unless abortStale is
+// set, in which case the uncommitted upload is aborted and a new upload is started.
+//
+// The uncommitted upload is kept when the upload fails, so that it can be
+// resumed later.
+func resumableUpload(ctx context.Context, project *uplink.Project, file *os.File, size int64, dst fpath.FPath, expiration time.Time, customMetadata uplink.CustomMetadata, partSize memory.Size, abortStale bool, bar *progressBar, limiter *rateLimiter) (written, skipped int64, err error) {
+	partCount := (size + partSize.Int64() - 1) / partSize.Int64()
+	if partCount == 0 {
+		// an empty file is uploaded as a single empty part.
+		partCount = 1
+	}
+	expectedPartSize := func(partNumber int64) int64 {
+		offset := (partNumber - 1) * partSize.Int64()
+		if remaining := size - offset; remaining < partSize.Int64() {
+			return remaining
+		}
+		return partSize.Int64()
+	}
+
+	uploadID, parts, err := findResumableUpload(ctx, project, dst)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if uploadID != "" {
+		for partNumber, part := range parts {
+			var mismatch string
+			switch {
+			case int64(partNumber) < 1 || int64(partNumber) > partCount:
+				mismatch = fmt.Sprintf("part %d is beyond the %d parts of the source file", partNumber, partCount)
+			case part.Size != expectedPartSize(int64(partNumber)):
+				mismatch = fmt.Sprintf("part %d has %d bytes instead of %d", partNumber, part.Size, expectedPartSize(int64(partNumber)))
+			default:
+				etag, err := partETag(io.NewSectionReader(file, (int64(partNumber)-1)*partSize.Int64(), part.Size))
+				if err != nil {
+					return 0, 0, err
+				}
+				if !bytes.Equal(etag, part.ETag) {
+					mismatch = fmt.Sprintf("part %d doesn't have the same content", partNumber)
+				}
+			}
+			if mismatch == "" {
+				continue
+			}
+
+			// the upload wasn't started with the same file or part size.
+			if !abortStale {
+				return 0, 0, fmt.Errorf("uncommitted upload %s to %s doesn't match the source file, %s (use --abort-stale to abort it and start a new upload)", uploadID, dst.String(), mismatch)
+			}
+			if err := project.AbortUpload(ctx, dst.Bucket(), dst.Path(), uploadID); err != nil {
+				return 0, 0, err
+			}
+			uploadID, parts = "", nil
+			break
+		}
+	}
+
+	if uploadID == "" {
+		info, err := project.BeginUpload(ctx, dst.Bucket(), dst.Path(), &uplink.UploadOptions{
+			Expires: expiration,
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+		uploadID = info.UploadID
+	}
+
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("%w (the upload can be resumed with --resume)", err)
+		}
+	}()
+
+	for partNumber := int64(1); partNumber <= partCount; partNumber++ {
+		length := expectedPartSize(partNumber)
+
+		if _, ok := parts[uint32(partNumber)]; ok {
+			skipped += length
+			if bar != nil {
+				bar.Add64(length)
+			}
+			continue
+		}
+
+		hash := sha256.New()
+		var reader io.Reader = io.NewSectionReader(file, (partNumber-1)*partSize.Int64(), length)
+		reader = io.TeeReader(reader, hash)
+		reader = limiter.Reader(reader)
+		if bar != nil {
+			reader = bar.NewProxyReader(reader)
+		}
+
+		part, err := project.UploadPart(ctx, dst.Bucket(), dst.Path(), uploadID, uint32(partNumber))
+		if err != nil {
+			return written, skipped, err
+		}
+
+		n, err := io.Copy(part, reader)
+		written += n
+		if err != nil {
+			return written, skipped, errs.Combine(err, part.Abort())
+		}
+
+		if err := part.SetETag(hash.Sum(nil)); err != nil {
+			return written, skipped, errs.Combine(err, part.Abort())
+		}
+		if err := part.Commit(); err != nil {
+			return written, skipped, err
+		}
+	}
+
+	_, err = project.CommitUpload(ctx, dst.Bucket(), dst.Path(), uploadID, &uplink.CommitUploadOptions{
+		CustomMetadata: customMetadata,
+	})
+	return written, skipped, err
+}
+
+// partETag returns the ETag resumableUpload uploads a part with content with.
+func partETag(content io.Reader) ([]byte, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
+
+// findResumableUpload returns the ID and the parts of the most recently
+// started uncommitted upload to dst. The ID is empty when there is none.
+func findResumableUpload(ctx context.Context, project *uplink.Project, dst fpath.FPath) (uploadID string, parts map[uint32]*uplink.Part, err error) {
+	prefix := ""
+	if i := strings.LastIndex(dst.Path(), "/"); i >= 0 {
+		prefix = dst.Path()[:i+1]
+	}
+
+	var created time.Time
+	uploads := project.ListUploads(ctx, dst.Bucket(), &uplink.ListUploadsOptions{
+		Prefix: prefix,
+		System: true,
+	})
+	for uploads.Next() {
+		item := uploads.Item()
+		if item.IsPrefix || item.Key != dst.Path() {
+			continue
+		}
+		if uploadID == "" || item.System.Created.After(created) {
+			uploadID, created = item.UploadID, item.System.Created
+		}
+	}
+	if err := uploads.Err(); err != nil {
+		return "", nil, err
+	}
+	if uploadID == "" {
+		return "", nil, nil
+	}
+
+	parts = make(map[uint32]*uplink.Part)
+	partIterator := project.ListUploadParts(ctx, dst.Bucket(), dst.Path(), uploadID, nil)
+	for partIterator.Next() {
+		part := partIterator.Item()
+		parts[part.PartNumber] = part
+	}
+	if err := partIterator.Err(); err != nil {
+		return "", nil, err
+	}
+
+	return uploadID, parts, nil
+}
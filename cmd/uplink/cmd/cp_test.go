@@ -0,0 +1,780 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"mime"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/uplink"
+)
+
+func TestCopyLimitRate(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		uplinkExe := ctx.Compile("storj.io/storj/cmd/uplink")
+
+		// Configure uplink.
+		{
+			access := planet.Uplinks[0].Access[planet.Satellites[0].ID()]
+
+			accessString, err := access.Serialize()
+			require.NoError(t, err)
+
+			output, err := exec.Command(uplinkExe,
+				"--config-dir", ctx.Dir("uplink"),
+				"import",
+				accessString,
+			).CombinedOutput()
+			t.Log(string(output))
+			require.NoError(t, err)
+		}
+
+		bucketName := testrand.BucketName()
+		require.NoError(t, planet.Uplinks[0].CreateBucket(ctx, planet.Satellites[0], bucketName))
+
+		// the first second worth of data is transferred as a burst,
+		// so transferring three seconds worth takes at least two seconds.
+		const rate = 32 * memory.KiB // matches --limit-rate
+		const minDuration = 2 * time.Second
+
+		data := testrand.BytesInt(3 * rate.Int())
+		src := ctx.File("src")
+		require.NoError(t, ioutil.WriteFile(src, data, 0644))
+		uri := "sj://" + bucketName + "/object"
+
+		copyLimited := func(args ...string) time.Duration {
+			args = append([]string{"--config-dir", ctx.Dir("uplink"), "cp", "--progress=false", "--limit-rate", "32KiB"}, args...)
+
+			start := time.Now()
+			output, err := exec.Command(uplinkExe, args...).CombinedOutput()
+			t.Log(string(output))
+			require.NoError(t, err)
+			return time.Since(start)
+		}
+
+		require.GreaterOrEqual(t, int64(copyLimited(src, uri)), int64(minDuration))
+
+		for _, parallelism := range []int{1, 2} {
+			dst := filepath.Join(ctx.Dir("download"), "parallelism-"+strconv.Itoa(parallelism))
+
+			elapsed := copyLimited("--parallelism", strconv.Itoa(parallelism), uri, dst)
+			require.GreaterOrEqual(t, int64(elapsed), int64(minDuration))
+
+			downloaded, err := ioutil.ReadFile(dst)
+			require.NoError(t, err)
+			require.Equal(t, data, downloaded)
+		}
+	})
+}
+
+func TestCopyToMissingBucket(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		uplinkExe := ctx.Compile("storj.io/storj/cmd/uplink")
+
+		// Configure uplink.
+		{
+			access := planet.Uplinks[0].Access[planet.Satellites[0].ID()]
+
+			accessString, err := access.Serialize()
+			require.NoError(t, err)
+
+			output, err := exec.Command(uplinkExe,
+				"--config-dir", ctx.Dir("uplink"),
+				"import",
+				accessString,
+			).CombinedOutput()
+			t.Log(string(output))
+			require.NoError(t, err)
+		}
+
+		bucketName := testrand.BucketName()
+		require.NoError(t, planet.Uplinks[0].Upload(ctx, planet.Satellites[0], bucketName, "object", testrand.Bytes(5*memory.KiB)))
+
+		output, err := exec.Command(uplinkExe,
+			"--config-dir", ctx.Dir("uplink"),
+			"cp", "--progress=false",
+			"sj://"+bucketName+"/object", "sj://missing-bucket/object",
+		).CombinedOutput()
+		t.Log(string(output))
+		require.Error(t, err)
+
+		var exitErr *exec.ExitError
+		require.True(t, errors.As(err, &exitErr))
+		require.False(t, strings.Contains(string(output), "panic"))
+		require.Contains(t, string(output), "bucket not found")
+	})
+}
+
+func TestCopyIfNotExists(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		uplinkExe := ctx.Compile("storj.io/storj/cmd/uplink")
+		satellite, uplinkPeer := planet.Satellites[0], planet.Uplinks[0]
+
+		// Configure uplink.
+		{
+			access := uplinkPeer.Access[satellite.ID()]
+
+			accessString, err := access.Serialize()
+			require.NoError(t, err)
+
+			output, err := exec.Command(uplinkExe,
+				"--config-dir", ctx.Dir("uplink"),
+				"import",
+				accessString,
+			).CombinedOutput()
+			t.Log(string(output))
+			require.NoError(t, err)
+		}
+
+		bucketName := testrand.BucketName()
+		existing := testrand.Bytes(5 * memory.KiB)
+		require.NoError(t, uplinkPeer.Upload(ctx, satellite, bucketName, "existing", existing))
+
+		copyIfNotExists := func(src, dst string) string {
+			output, err := exec.Command(uplinkExe,
+				"--config-dir", ctx.Dir("uplink"),
+				"cp", "--progress=false", "--if-not-exists",
+				src, dst,
+			).CombinedOutput()
+			t.Log(string(output))
+			require.NoError(t, err)
+			return string(output)
+		}
+
+		local := ctx.File("local")
+		data := testrand.Bytes(5 * memory.KiB)
+		require.NoError(t, ioutil.WriteFile(local, data, 0644))
+
+		t.Run("upload", func(t *testing.T) {
+			require.Contains(t, copyIfNotExists(local, "sj://"+bucketName+"/existing"), "Skipped")
+			downloaded, err := uplinkPeer.Download(ctx, satellite, bucketName, "existing")
+			require.NoError(t, err)
+			require.Equal(t, existing, downloaded)
+
+			require.Contains(t, copyIfNotExists(local, "sj://"+bucketName+"/uploaded"), "Created")
+			downloaded, err = uplinkPeer.Download(ctx, satellite, bucketName, "uploaded")
+			require.NoError(t, err)
+			require.Equal(t, data, downloaded)
+		})
+
+		t.Run("download", func(t *testing.T) {
+			require.Contains(t, copyIfNotExists("sj://"+bucketName+"/existing", local), "Skipped")
+			content, err := ioutil.ReadFile(local)
+			require.NoError(t, err)
+			require.Equal(t, data, content)
+
+			absent := filepath.Join(ctx.Dir("download"), "absent")
+			require.Contains(t, copyIfNotExists("sj://"+bucketName+"/existing", absent), "Downloaded")
+			content, err = ioutil.ReadFile(absent)
+			require.NoError(t, err)
+			require.Equal(t, existing, content)
+		})
+
+		t.Run("copy", func(t *testing.T) {
+			require.Contains(t, copyIfNotExists("sj://"+bucketName+"/existing", "sj://"+bucketName+"/uploaded"), "Skipped")
+			downloaded, err := uplinkPeer.Download(ctx, satellite, bucketName, "uploaded")
+			require.NoError(t, err)
+			require.Equal(t, data, downloaded)
+
+			require.Contains(t, copyIfNotExists("sj://"+bucketName+"/existing", "sj://"+bucketName+"/copied"), "copied to")
+			downloaded, err = uplinkPeer.Download(ctx, satellite, bucketName, "copied")
+			require.NoError(t, err)
+			require.Equal(t, existing, downloaded)
+		})
+	})
+}
+
+func TestCopyOutputJSON(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		uplinkExe := ctx.Compile("storj.io/storj/cmd/uplink")
+
+		// Configure uplink.
+		{
+			access := planet.Uplinks[0].Access[planet.Satellites[0].ID()]
+
+			accessString, err := access.Serialize()
+			require.NoError(t, err)
+
+			output, err := exec.Command(uplinkExe,
+				"--config-dir", ctx.Dir("uplink"),
+				"import",
+				accessString,
+			).CombinedOutput()
+			t.Log(string(output))
+			require.NoError(t, err)
+		}
+
+		bucketName := testrand.BucketName()
+		require.NoError(t, planet.Uplinks[0].CreateBucket(ctx, planet.Satellites[0], bucketName))
+
+		type copyResult struct {
+			Action      string `json:"action"`
+			Source      string `json:"source"`
+			Destination string `json:"destination"`
+			Bytes       int64  `json:"bytes"`
+			DurationMs  *int64 `json:"durationMs"`
+		}
+
+		copyJSON := func(src, dst string) copyResult {
+			command := exec.Command(uplinkExe,
+				"--config-dir", ctx.Dir("uplink"),
+				"cp", "--progress=false", "--output", "json",
+				src, dst,
+			)
+			var stderr bytes.Buffer
+			command.Stderr = &stderr
+
+			output, err := command.Output()
+			t.Log(string(output), stderr.String())
+			require.NoError(t, err)
+
+			var result copyResult
+			require.NoError(t, json.Unmarshal(output, &result))
+			require.NotNil(t, result.DurationMs)
+			return result
+		}
+
+		data := testrand.Bytes(10 * memory.KiB)
+		src := ctx.File("src")
+		require.NoError(t, ioutil.WriteFile(src, data, 0644))
+		uri := "sj://" + bucketName + "/object"
+		dst := filepath.Join(ctx.Dir("download"), "object")
+
+		result := copyJSON(src, uri)
+		result.DurationMs = nil
+		require.Equal(t, copyResult{Action: "upload", Source: src, Destination: uri, Bytes: int64(len(data))}, result)
+
+		result = copyJSON(uri, dst)
+		result.DurationMs = nil
+		require.Equal(t, copyResult{Action: "download", Source: uri, Destination: dst, Bytes: int64(len(data))}, result)
+
+		// errors are printed as JSON on stderr.
+		command := exec.Command(uplinkExe,
+			"--config-dir", ctx.Dir("uplink"),
+			"cp", "--progress=false", "--output", "json",
+			"sj://"+bucketName+"/missing", filepath.Join(ctx.Dir("download"), "missing"),
+		)
+		var stderr bytes.Buffer
+		command.Stderr = &stderr
+		_, err := command.Output()
+		t.Log(stderr.String())
+		require.Error(t, err)
+
+		var errResult struct {
+			Error string `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(stderr.Bytes(), &errResult))
+		require.NotEmpty(t, errResult.Error)
+	})
+}
+
+func TestCopyOversizedMetadata(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		uplinkExe := ctx.Compile("storj.io/storj/cmd/uplink")
+		satellite, uplinkPeer := planet.Satellites[0], planet.Uplinks[0]
+
+		// Configure uplink.
+		{
+			access := uplinkPeer.Access[satellite.ID()]
+
+			accessString, err := access.Serialize()
+			require.NoError(t, err)
+
+			output, err := exec.Command(uplinkExe,
+				"--config-dir", ctx.Dir("uplink"),
+				"import",
+				accessString,
+			).CombinedOutput()
+			t.Log(string(output))
+			require.NoError(t, err)
+		}
+
+		bucketName := testrand.BucketName()
+		require.NoError(t, uplinkPeer.CreateBucket(ctx, satellite, bucketName))
+
+		src := ctx.File("src")
+		require.NoError(t, ioutil.WriteFile(src, testrand.Bytes(5*memory.KiB), 0644))
+
+		copyWithMetadata := func(object string, size memory.Size, args ...string) (string, error) {
+			metadata, err := json.Marshal(map[string]string{
+				"key": strings.Repeat("v", size.Int()),
+			})
+			require.NoError(t, err)
+
+			args = append([]string{"--config-dir", ctx.Dir("uplink"), "cp", "--progress=false", "--metadata", string(metadata)}, args...)
+			args = append(args, src, "sj://"+bucketName+"/"+object)
+
+			output, err := exec.Command(uplinkExe, args...).CombinedOutput()
+			t.Log(string(output))
+			return string(output), err
+		}
+
+		// larger than the default limit.
+		output, err := copyWithMetadata("oversized", 3*memory.KiB)
+		require.Error(t, err)
+		require.Contains(t, output, "metadata is too large")
+
+		_, err = uplinkPeer.Download(ctx, satellite, bucketName, "oversized")
+		require.True(t, errors.Is(err, uplink.ErrObjectNotFound))
+
+		// within the default limit, but larger than the configured one.
+		output, err = copyWithMetadata("limited", memory.KiB, "--max-metadata-size", "512B")
+		require.Error(t, err)
+		require.Contains(t, output, "metadata is too large")
+
+		output, err = copyWithMetadata("limited", memory.KiB)
+		require.NoError(t, err)
+		require.Contains(t, output, "Created")
+	})
+}
+
+func TestCopyResume(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		uplinkExe := ctx.Compile("storj.io/storj/cmd/uplink")
+		satellite, uplinkPeer := planet.Satellites[0], planet.Uplinks[0]
+
+		// Configure uplink.
+		{
+			access := uplinkPeer.Access[satellite.ID()]
+
+			accessString, err := access.Serialize()
+			require.NoError(t, err)
+
+			output, err := exec.Command(uplinkExe,
+				"--config-dir", ctx.Dir("uplink"),
+				"import",
+				accessString,
+			).CombinedOutput()
+			t.Log(string(output))
+			require.NoError(t, err)
+		}
+
+		bucketName := testrand.BucketName()
+		require.NoError(t, uplinkPeer.CreateBucket(ctx, satellite, bucketName))
+
+		const partSize = 10 * memory.KiB // matches --part-size
+		data := testrand.BytesInt(3*partSize.Int() + 5*memory.KiB.Int())
+		src := ctx.File("src")
+		require.NoError(t, ioutil.WriteFile(src, data, 0644))
+
+		tryCopyResume := func(object string, flags ...string) (string, error) {
+			args := append([]string{
+				"--config-dir", ctx.Dir("uplink"),
+				"cp", "--progress=false", "--resume", "--part-size", "10KiB",
+			}, flags...)
+			output, err := exec.Command(uplinkExe, append(args, src, "sj://"+bucketName+"/"+object)...).CombinedOutput()
+			t.Log(string(output))
+			return string(output), err
+		}
+
+		copyResume := func(object string, flags ...string) string {
+			output, err := tryCopyResume(object, flags...)
+			require.NoError(t, err)
+			return output
+		}
+
+		t.Run("fresh", func(t *testing.T) {
+			output := copyResume("fresh")
+			require.Contains(t, output, "Created")
+			require.NotContains(t, output, "resumed")
+
+			downloaded, err := uplinkPeer.Download(ctx, satellite, bucketName, "fresh")
+			require.NoError(t, err)
+			require.Equal(t, data, downloaded)
+		})
+
+		t.Run("resumed", func(t *testing.T) {
+			project, err := uplinkPeer.GetProject(ctx, satellite)
+			require.NoError(t, err)
+			defer ctx.Check(project.Close)
+
+			// simulate an upload which failed after the first two parts.
+			info, err := project.BeginUpload(ctx, bucketName, "dir/resumed", nil)
+			require.NoError(t, err)
+
+			for i := 0; i < 2; i++ {
+				content := data[i*partSize.Int() : (i+1)*partSize.Int()]
+				etag := sha256.Sum256(content)
+
+				part, err := project.UploadPart(ctx, bucketName, "dir/resumed", info.UploadID, uint32(i+1))
+				require.NoError(t, err)
+				_, err = part.Write(content)
+				require.NoError(t, err)
+				require.NoError(t, part.SetETag(etag[:]))
+				require.NoError(t, part.Commit())
+			}
+
+			output := copyResume("dir/resumed")
+			require.Contains(t, output, "resumed after "+strconv.Itoa(2*partSize.Int())+" already uploaded bytes")
+
+			downloaded, err := uplinkPeer.Download(ctx, satellite, bucketName, "dir/resumed")
+			require.NoError(t, err)
+			require.Equal(t, data, downloaded)
+		})
+
+		t.Run("changed content", func(t *testing.T) {
+			project, err := uplinkPeer.GetProject(ctx, satellite)
+			require.NoError(t, err)
+			defer ctx.Check(project.Close)
+
+			// a part uploaded from a file which changed since has the same
+			// size, but not the same content, so the upload is only started
+			// again with --abort-stale.
+			info, err := project.BeginUpload(ctx, bucketName, "changed", nil)
+			require.NoError(t, err)
+
+			content := testrand.BytesInt(partSize.Int())
+			etag := sha256.Sum256(content)
+
+			part, err := project.UploadPart(ctx, bucketName, "changed", info.UploadID, 1)
+			require.NoError(t, err)
+			_, err = part.Write(content)
+			require.NoError(t, err)
+			require.NoError(t, part.SetETag(etag[:]))
+			require.NoError(t, part.Commit())
+
+			output, err := tryCopyResume("changed")
+			require.Error(t, err)
+			require.Contains(t, output, info.UploadID)
+			require.Contains(t, output, "part 1 doesn't have the same content")
+
+			output = copyResume("changed", "--abort-stale")
+			require.NotContains(t, output, "resumed")
+
+			downloaded, err := uplinkPeer.Download(ctx, satellite, bucketName, "changed")
+			require.NoError(t, err)
+			require.Equal(t, data, downloaded)
+		})
+
+		t.Run("mismatched parts", func(t *testing.T) {
+			project, err := uplinkPeer.GetProject(ctx, satellite)
+			require.NoError(t, err)
+			defer ctx.Check(project.Close)
+
+			// a part with a different size can't be from the same file.
+			info, err := project.BeginUpload(ctx, bucketName, "mismatched", nil)
+			require.NoError(t, err)
+
+			part, err := project.UploadPart(ctx, bucketName, "mismatched", info.UploadID, 1)
+			require.NoError(t, err)
+			_, err = part.Write(testrand.BytesInt(partSize.Int() / 2))
+			require.NoError(t, err)
+			require.NoError(t, part.Commit())
+
+			output, err := tryCopyResume("mismatched")
+			require.Error(t, err)
+			require.Contains(t, output, "part 1 has "+strconv.Itoa(partSize.Int()/2)+" bytes instead of "+strconv.Itoa(partSize.Int()))
+
+			output = copyResume("mismatched", "--abort-stale")
+			require.NotContains(t, output, "resumed")
+
+			downloaded, err := uplinkPeer.Download(ctx, satellite, bucketName, "mismatched")
+			require.NoError(t, err)
+			require.Equal(t, data, downloaded)
+		})
+	})
+}
+
+func TestCopyTags(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		uplinkExe := ctx.Compile("storj.io/storj/cmd/uplink")
+		satellite, uplinkPeer := planet.Satellites[0], planet.Uplinks[0]
+
+		// Configure uplink.
+		{
+			access := uplinkPeer.Access[satellite.ID()]
+
+			accessString, err := access.Serialize()
+			require.NoError(t, err)
+
+			output, err := exec.Command(uplinkExe,
+				"--config-dir", ctx.Dir("uplink"),
+				"import",
+				accessString,
+			).CombinedOutput()
+			t.Log(string(output))
+			require.NoError(t, err)
+		}
+
+		bucketName := testrand.BucketName()
+		require.NoError(t, uplinkPeer.CreateBucket(ctx, satellite, bucketName))
+
+		src := ctx.File("src")
+		require.NoError(t, ioutil.WriteFile(src, testrand.Bytes(5*memory.KiB), 0644))
+		uri := "sj://" + bucketName + "/object"
+
+		copyObject := func(args ...string) (string, error) {
+			args = append([]string{"--config-dir", ctx.Dir("uplink"), "cp", "--progress=false"}, args...)
+			output, err := exec.Command(uplinkExe, args...).CombinedOutput()
+			t.Log(string(output))
+			return string(output), err
+		}
+
+		// tags are merged with the metadata.
+		_, err := copyObject("--metadata", `{"key":"value"}`, "--tag", "env=prod", "--tag", "team=storage=nodes", "--content-type", "text/plain", src, uri)
+		require.NoError(t, err)
+
+		project, err := uplinkPeer.OpenProject(ctx, satellite)
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+
+		object, err := project.StatObject(ctx, bucketName, "object")
+		require.NoError(t, err)
+		require.Equal(t, uplink.CustomMetadata{
+			"key":          "value",
+			"tag:env":      "prod",
+			"tag:team":     "storage=nodes",
+			"Content-Type": "text/plain",
+		}, object.Custom)
+
+		// the tags are shown on download.
+		dst := filepath.Join(ctx.Dir("download"), "object")
+		output, err := copyObject("--show-tags", uri, dst)
+		require.NoError(t, err)
+		require.Contains(t, output, "Tag env=prod\nTag team=storage=nodes")
+
+		command := exec.Command(uplinkExe,
+			"--config-dir", ctx.Dir("uplink"),
+			"cp", "--progress=false", "--output", "json", "--show-tags",
+			uri, filepath.Join(ctx.Dir("download"), "json"),
+		)
+		jsonOutput, err := command.Output()
+		t.Log(string(jsonOutput))
+		require.NoError(t, err)
+
+		var result struct {
+			Tags map[string]string `json:"tags"`
+		}
+		require.NoError(t, json.Unmarshal(jsonOutput, &result))
+		require.Equal(t, map[string]string{"env": "prod", "team": "storage=nodes"}, result.Tags)
+
+		// invalid tags are rejected before uploading.
+		for _, tt := range []struct {
+			args     []string
+			expected string
+		}{
+			{args: []string{"--tag", "novalue"}, expected: "must be key=value"},
+			{args: []string{"--tag", "=value"}, expected: "key is empty"},
+			{args: []string{"--tag", "bad key=value"}, expected: "invalid character"},
+			{args: []string{"--tag", "key=" + strings.Repeat("v", 257)}, expected: "value is longer"},
+			{args: []string{"--tag", "key=a", "--tag", "key=b"}, expected: "duplicate key"},
+			{args: []string{"--metadata", `{"tag:env":"prod"}`}, expected: "reserved for tags"},
+		} {
+			output, err := copyObject(append(tt.args, src, "sj://"+bucketName+"/invalid")...)
+			require.Error(t, err)
+			require.Contains(t, output, tt.expected)
+		}
+
+		_, err = project.StatObject(ctx, bucketName, "invalid")
+		require.True(t, errors.Is(err, uplink.ErrObjectNotFound))
+	})
+}
+
+func TestCopyProgressJSON(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		uplinkExe := ctx.Compile("storj.io/storj/cmd/uplink")
+		satellite, uplinkPeer := planet.Satellites[0], planet.Uplinks[0]
+
+		// Configure uplink.
+		{
+			access := uplinkPeer.Access[satellite.ID()]
+
+			accessString, err := access.Serialize()
+			require.NoError(t, err)
+
+			output, err := exec.Command(uplinkExe,
+				"--config-dir", ctx.Dir("uplink"),
+				"import",
+				accessString,
+			).CombinedOutput()
+			t.Log(string(output))
+			require.NoError(t, err)
+		}
+
+		bucketName := testrand.BucketName()
+		data := testrand.BytesInt(3 * (32 * memory.KiB).Int())
+		require.NoError(t, uplinkPeer.Upload(ctx, satellite, bucketName, "object", data))
+
+		// the download is rate limited, so that it takes long enough for
+		// progress to be printed before it's finished.
+		command := exec.Command(uplinkExe,
+			"--config-dir", ctx.Dir("uplink"),
+			"cp", "--progress-format", "json", "--limit-rate", "32KiB",
+			"sj://"+bucketName+"/object", filepath.Join(ctx.Dir("download"), "object"),
+		)
+		var stderr bytes.Buffer
+		command.Stderr = &stderr
+
+		output, err := command.Output()
+		t.Log(string(output), stderr.String())
+		require.NoError(t, err)
+		require.Contains(t, string(output), "Downloaded")
+
+		type progressLine struct {
+			Transferred int64   `json:"transferred"`
+			Total       int64   `json:"total"`
+			Percent     float64 `json:"percent"`
+		}
+
+		var lines []progressLine
+		for _, text := range strings.Split(strings.TrimSpace(stderr.String()), "\n") {
+			var line progressLine
+			require.NoError(t, json.Unmarshal([]byte(text), &line), text)
+			lines = append(lines, line)
+		}
+		require.True(t, len(lines) > 1, "expected progress before the download finished")
+
+		for i, line := range lines {
+			require.Equal(t, int64(len(data)), line.Total)
+			if i > 0 {
+				require.GreaterOrEqual(t, line.Transferred, lines[i-1].Transferred)
+			}
+		}
+		require.Equal(t, progressLine{Transferred: int64(len(data)), Total: int64(len(data)), Percent: 100}, lines[len(lines)-1])
+	})
+}
+
+func TestCopyContentType(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		uplinkExe := ctx.Compile("storj.io/storj/cmd/uplink")
+		satellite, uplinkPeer := planet.Satellites[0], planet.Uplinks[0]
+
+		// Configure uplink.
+		{
+			access := uplinkPeer.Access[satellite.ID()]
+
+			accessString, err := access.Serialize()
+			require.NoError(t, err)
+
+			output, err := exec.Command(uplinkExe,
+				"--config-dir", ctx.Dir("uplink"),
+				"import",
+				accessString,
+			).CombinedOutput()
+			t.Log(string(output))
+			require.NoError(t, err)
+		}
+
+		bucketName := testrand.BucketName()
+		require.NoError(t, uplinkPeer.CreateBucket(ctx, satellite, bucketName))
+
+		project, err := uplinkPeer.OpenProject(ctx, satellite)
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+
+		html := []byte("<!DOCTYPE html><html><body>storj</body></html>")
+		png := append([]byte("\x89PNG\r\n\x1a\n"), testrand.Bytes(memory.KiB)...)
+		binary := bytes.Repeat([]byte{0, 1, 2, 3}, 256)
+		// the system's types may differ from those built in.
+		htmlType := mime.TypeByExtension(".html")
+
+		for _, tt := range []struct {
+			name     string
+			file     string
+			data     []byte
+			stdin    bool
+			args     []string
+			expected string
+		}{
+			{name: "extension", file: "page.html", data: html, expected: htmlType},
+			{name: "sniffed", file: "image", data: png, expected: "image/png"},
+			{name: "undetected", file: "binary", data: binary, expected: ""},
+			{name: "stdin extension", file: "stdin.html", data: binary, stdin: true, expected: htmlType},
+			{name: "stdin sniffed", file: "stdin", data: png, stdin: true, expected: "image/png"},
+			{name: "override", file: "override.html", data: html, args: []string{"--content-type", "application/x-custom"}, expected: "application/x-custom"},
+			{name: "metadata", file: "metadata.html", data: html, args: []string{"--metadata", `{"Content-Type":"text/plain"}`}, expected: "text/plain"},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				args := append([]string{"--config-dir", ctx.Dir("uplink"), "cp", "--progress=false"}, tt.args...)
+
+				command := exec.Command(uplinkExe)
+				if tt.stdin {
+					command.Stdin = bytes.NewReader(tt.data)
+					args = append(args, "-", "sj://"+bucketName+"/"+tt.file)
+				} else {
+					src := filepath.Join(ctx.Dir("src"), tt.file)
+					require.NoError(t, ioutil.WriteFile(src, tt.data, 0644))
+					args = append(args, src, "sj://"+bucketName+"/")
+				}
+				command.Args = append(command.Args, args...)
+
+				output, err := command.CombinedOutput()
+				t.Log(string(output))
+				require.NoError(t, err)
+
+				object, err := project.StatObject(ctx, bucketName, tt.file)
+				require.NoError(t, err)
+				require.Equal(t, tt.expected, object.Custom["Content-Type"])
+
+				// the bytes read for detection are uploaded too.
+				downloaded, err := uplinkPeer.Download(ctx, satellite, bucketName, tt.file)
+				require.NoError(t, err)
+				require.Equal(t, tt.data, downloaded)
+			})
+		}
+
+		src := filepath.Join(ctx.Dir("src"), "invalid")
+		require.NoError(t, ioutil.WriteFile(src, html, 0644))
+		output, err := exec.Command(uplinkExe,
+			"--config-dir", ctx.Dir("uplink"),
+			"cp", "--progress=false", "--content-type", "text/",
+			src, "sj://"+bucketName+"/invalid",
+		).CombinedOutput()
+		t.Log(string(output))
+		require.Error(t, err)
+		require.Contains(t, string(output), "invalid content type")
+	})
+}
@@ -6,19 +6,34 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"storj.io/common/fpath"
+	"storj.io/uplink"
+)
+
+var (
+	metaGetSystemFlag *bool
 )
 
 func init() {
-	addCmd(&cobra.Command{
+	metaGetCmd := addCmd(&cobra.Command{
 		Use:   "get [KEY] PATH",
 		Short: "Get a Storj object's metadata",
 		RunE:  metaGetMain,
 		Args:  cobra.RangeArgs(1, 2),
 	}, metaCmd)
+	metaGetSystemFlag = metaGetCmd.Flags().Bool("system", false, "if true, print the object's system metadata (size, created, expires) along with its custom metadata")
+	setBasicFlags(metaGetCmd.Flags(), "system")
+}
+
+// objectSystemMetadata is the system metadata printed by meta get --system.
+type objectSystemMetadata struct {
+	Size    int64      `json:"size"`
+	Created time.Time  `json:"created"`
+	Expires *time.Time `json:"expires,omitempty"`
 }
 
 // metaGetMain is the function executed when metaGetCmd is called.
@@ -38,6 +53,10 @@ func metaGetMain(cmd *cobra.Command, args []string) (err error) {
 		return fmt.Errorf("too many arguments")
 	}
 
+	if key != nil && *metaGetSystemFlag {
+		return fmt.Errorf("--system can't be used when getting a single key")
+	}
+
 	ctx, _ := withTelemetry(cmd)
 
 	src, err := fpath.New(path)
@@ -81,6 +100,33 @@ func metaGetMain(cmd *cobra.Command, args []string) (err error) {
 		return nil
 	}
 
+	if *metaGetSystemFlag {
+		system := objectSystemMetadata{
+			Size:    object.System.ContentLength,
+			Created: object.System.Created,
+		}
+		if !object.System.Expires.IsZero() {
+			system.Expires = &object.System.Expires
+		}
+
+		custom := object.Custom
+		if custom == nil {
+			custom = uplink.CustomMetadata{}
+		}
+
+		str, err := json.MarshalIndent(struct {
+			System objectSystemMetadata  `json:"system"`
+			Custom uplink.CustomMetadata `json:"custom"`
+		}{system, custom}, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s\n", string(str))
+
+		return nil
+	}
+
 	if object.Custom != nil {
 		str, err := json.MarshalIndent(object.Custom, "", "  ")
 		if err != nil {
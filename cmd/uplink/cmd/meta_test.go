@@ -10,6 +10,7 @@ import (
 	"os/exec"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -83,6 +84,10 @@ func TestSetGetMeta(t *testing.T) {
 		err = json.Unmarshal(metadataBs, &metadataNorm)
 		require.NoError(t, err)
 
+		// the content type is set, since it would be detected from the
+		// extension of the random path otherwise.
+		metadataNorm["Content-Type"] = "application/octet-stream"
+
 		path := testrand.URLPathNonFolder()
 		uri := "sj://" + bucketName + "/" + path
 
@@ -91,6 +96,7 @@ func TestSetGetMeta(t *testing.T) {
 				"--config-dir", ctx.Dir("uplink"),
 				"cp",
 				"--metadata", metadataStr,
+				"--content-type", "application/octet-stream",
 				"-", uri,
 			).CombinedOutput()
 			t.Log(string(output))
@@ -124,6 +130,43 @@ func TestSetGetMeta(t *testing.T) {
 			assert.Equal(t, metadataNorm, md)
 		}
 
+		// Get system and custom metadata.
+		{
+			cmd := exec.Command(uplinkExe,
+				"--config-dir", ctx.Dir("uplink"),
+				"meta", "get", "--system", uri,
+			)
+			t.Log(cmd)
+
+			output, err := cmd.Output()
+			t.Log(string(output))
+			if !assert.NoError(t, err) {
+				var ee *exec.ExitError
+				if errors.As(err, &ee) {
+					t.Log(ee)
+					t.Log(string(ee.Stderr))
+				}
+
+				return
+			}
+
+			var md struct {
+				System struct {
+					Size    int64      `json:"size"`
+					Created time.Time  `json:"created"`
+					Expires *time.Time `json:"expires"`
+				} `json:"system"`
+				Custom map[string]string `json:"custom"`
+			}
+			err = json.Unmarshal(output, &md)
+			require.NoError(t, err)
+
+			assert.Equal(t, int64(0), md.System.Size)
+			assert.WithinDuration(t, time.Now(), md.System.Created, time.Hour)
+			assert.Nil(t, md.System.Expires)
+			assert.Equal(t, metadataNorm, md.Custom)
+		}
+
 		// Get specific metadata.
 		//
 		// NOTE: The CLI expects JSON encoded strings for input and
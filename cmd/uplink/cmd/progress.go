@@ -0,0 +1,118 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	progressbar "github.com/cheggaaa/pb/v3"
+)
+
+// progressInterval is how often the progress is printed in the json format.
+const progressInterval = 200 * time.Millisecond
+
+// progressBar shows the progress of a transfer, either as a progress bar or
+// as JSON lines printed to stderr periodically. Either are driven by the byte
+// counts of the embedded bar.
+type progressBar struct {
+	*progressbar.ProgressBar
+
+	// output is where the JSON lines are printed, nil for the progress bar.
+	output io.Writer
+
+	mu       sync.Mutex
+	stop     chan struct{}
+	stopped  chan struct{}
+	finished bool
+}
+
+// progressLine is the progress as printed in the json format.
+type progressLine struct {
+	Transferred int64   `json:"transferred"`
+	Total       int64   `json:"total"`
+	Percent     float64 `json:"percent"`
+}
+
+// newProgressBar creates the progress of a transfer of total bytes in the
+// format of --progress-format.
+func newProgressBar(total int64) *progressBar {
+	bar := &progressBar{ProgressBar: progressbar.New64(total)}
+	if *progressFormat == "json" {
+		// the bar only counts the bytes, without being rendered.
+		bar.ProgressBar.Set(progressbar.Static, true)
+		bar.output = os.Stderr
+	}
+	return bar
+}
+
+// Start starts showing the progress.
+func (bar *progressBar) Start() {
+	bar.ProgressBar.Start()
+	if bar.output == nil {
+		return
+	}
+
+	bar.stop = make(chan struct{})
+	bar.stopped = make(chan struct{})
+	go func() {
+		defer close(bar.stopped)
+
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				bar.printLine()
+			case <-bar.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Finish stops showing the progress, printing the final progress in the json
+// format.
+func (bar *progressBar) Finish() {
+	bar.ProgressBar.Finish()
+	if bar.output == nil {
+		return
+	}
+
+	bar.mu.Lock()
+	finished := bar.finished
+	bar.finished = true
+	bar.mu.Unlock()
+	if finished {
+		return
+	}
+
+	if bar.stop != nil {
+		close(bar.stop)
+		<-bar.stopped
+	}
+	bar.printLine()
+}
+
+// printLine prints the current progress as a JSON line.
+func (bar *progressBar) printLine() {
+	line := progressLine{
+		Transferred: bar.Current(),
+		Total:       bar.Total(),
+	}
+	if line.Total > 0 {
+		line.Percent = math.Round(float64(line.Transferred)/float64(line.Total)*10000) / 100
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintf(bar.output, "%s\n", data)
+}
@@ -10,12 +10,15 @@ import (
 	"github.com/spf13/cobra"
 
 	"storj.io/common/fpath"
+	"storj.io/common/memory"
 )
 
 var (
 	putProgress *bool
 	putExpires  *string
 	putMetadata *string
+
+	putMaxMetadataSize *string
 )
 
 func init() {
@@ -29,6 +32,7 @@ func init() {
 	putProgress = putCmd.Flags().Bool("progress", false, "if true, show upload progress")
 	putExpires = putCmd.Flags().String("expires", "", "optional expiration date of the new object. Please use format (yyyy-mm-ddThh:mm:ssZhh:mm)")
 	putMetadata = putCmd.Flags().String("metadata", "", "optional metadata for the object. Please use a single level JSON object of string to string only")
+	putMaxMetadataSize = putCmd.Flags().String("max-metadata-size", "2KiB", "maximum total size of the keys and values of the metadata, checked before uploading. Unlimited if set to 0")
 
 	setBasicFlags(putCmd.Flags(), "progress", "expires", "metadata")
 }
@@ -66,5 +70,10 @@ func putMain(cmd *cobra.Command, args []string) (err error) {
 		}
 	}
 
-	return upload(ctx, src, dst, expiration, []byte(*putMetadata), *putProgress)
+	var metadataLimit memory.Size
+	if err := metadataLimit.Set(*putMaxMetadataSize); err != nil {
+		return fmt.Errorf("invalid max metadata size (%s): %w", *putMaxMetadataSize, err)
+	}
+
+	return upload(ctx, src, dst, expiration, []byte(*putMetadata), nil, "", metadataLimit, *putProgress, nil, 0, false)
 }
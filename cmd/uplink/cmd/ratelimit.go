@@ -0,0 +1,100 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"io"
+
+	"golang.org/x/time/rate"
+
+	"storj.io/common/memory"
+	"storj.io/uplink/private/object"
+)
+
+// rateLimiter limits the combined rate of all the readers and writers it wraps.
+type rateLimiter struct {
+	ctx     context.Context
+	limiter *rate.Limiter
+}
+
+// newRateLimiter returns a rate limiter allowing bytesPerSecond. It returns
+// nil, which doesn't limit anything, when bytesPerSecond isn't positive.
+func newRateLimiter(ctx context.Context, bytesPerSecond memory.Size) *rateLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+
+	// a burst of a second worth of data is also the largest chunk it can wait for.
+	return &rateLimiter{
+		ctx:     ctx,
+		limiter: rate.NewLimiter(rate.Limit(bytesPerSecond), int(bytesPerSecond)),
+	}
+}
+
+// Reader returns reader limited by the rate limiter.
+func (l *rateLimiter) Reader(reader io.Reader) io.Reader {
+	if l == nil {
+		return reader
+	}
+	return &rateLimitedReader{limiter: l, reader: reader}
+}
+
+// WriterAt returns writer limited by the rate limiter.
+func (l *rateLimiter) WriterAt(writer object.WriterAt) object.WriterAt {
+	if l == nil {
+		return writer
+	}
+	return &rateLimitedWriterAt{limiter: l, WriterAt: writer}
+}
+
+// rateLimitedReader is a reader which reads at most as fast as its limiter allows.
+type rateLimitedReader struct {
+	limiter *rateLimiter
+	reader  io.Reader
+}
+
+// Read reads at most a burst of bytes and waits until the limiter allows them.
+func (r *rateLimitedReader) Read(p []byte) (n int, err error) {
+	if burst := r.limiter.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+
+	n, err = r.reader.Read(p)
+	if n > 0 {
+		if waitErr := r.limiter.limiter.WaitN(r.limiter.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+// rateLimitedWriterAt is a writer which writes at most as fast as its limiter allows.
+type rateLimitedWriterAt struct {
+	limiter *rateLimiter
+	object.WriterAt
+}
+
+// WriteAt writes p in chunks of at most a burst of bytes, waiting until the limiter allows each of them.
+func (w *rateLimitedWriterAt) WriteAt(p []byte, off int64) (n int, err error) {
+	burst := w.limiter.limiter.Burst()
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > burst {
+			chunk = chunk[:burst]
+		}
+
+		if err := w.limiter.limiter.WaitN(w.limiter.ctx, len(chunk)); err != nil {
+			return n, err
+		}
+
+		written, err := w.WriterAt.WriteAt(chunk, off+int64(n))
+		n += written
+		if err != nil {
+			return n, err
+		}
+		p = p[written:]
+	}
+	return n, nil
+}
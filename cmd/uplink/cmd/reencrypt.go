@@ -0,0 +1,161 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/common/fpath"
+	"storj.io/common/storj"
+	"storj.io/uplink"
+)
+
+var (
+	reencryptDstAccess *string
+	reencryptProgress  *bool
+)
+
+func init() {
+	reencryptCmd := addCmd(&cobra.Command{
+		Use:   "reencrypt sj://BUCKET[/PREFIX/] [sj://BUCKET[/PREFIX/]]",
+		Short: "Re-encrypts objects with the encryption key of another access",
+		Long: "Re-encrypts all objects under the source prefix by downloading them with the configured access " +
+			"and uploading them with --dst-access, keeping their metadata and expiration. The objects are " +
+			"uploaded under the destination prefix, or under the source prefix when no destination is given. " +
+			"The source objects are not deleted.",
+		RunE: reencryptMain,
+		Args: cobra.RangeArgs(1, 2),
+	}, RootCmd)
+	reencryptDstAccess = reencryptCmd.Flags().String("dst-access", "", "the access name or serialized access grant to upload the objects with")
+	reencryptProgress = reencryptCmd.Flags().Bool("progress", true, "if true, show progress")
+
+	setBasicFlags(reencryptCmd.Flags(), "dst-access", "progress")
+}
+
+// reencryptMain is the function executed when reencryptCmd is called.
+func reencryptMain(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := withTelemetry(cmd)
+
+	srcBucket, srcPrefix, err := reencryptPrefix(args[0])
+	if err != nil {
+		return err
+	}
+	dstBucket, dstPrefix := srcBucket, srcPrefix
+	if len(args) > 1 {
+		dstBucket, dstPrefix, err = reencryptPrefix(args[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	if *reencryptDstAccess == "" {
+		return fmt.Errorf("destination access must be specified with --dst-access")
+	}
+
+	srcAccessConfig := cfg.accessConfig()
+	srcAccess, err := srcAccessConfig.GetAccess()
+	if err != nil {
+		return err
+	}
+
+	dstAccessConfig := srcAccessConfig
+	dstAccessConfig.Access = *reencryptDstAccess
+	dstAccess, err := dstAccessConfig.GetAccess()
+	if err != nil {
+		return err
+	}
+
+	srcSerialized, err := srcAccess.Serialize()
+	if err != nil {
+		return err
+	}
+	dstSerialized, err := dstAccess.Serialize()
+	if err != nil {
+		return err
+	}
+	if srcSerialized == dstSerialized && srcBucket == dstBucket && srcPrefix == dstPrefix {
+		return fmt.Errorf("destination access must differ from the source access")
+	}
+
+	srcProject, err := cfg.openProject(ctx, srcAccess, false)
+	if err != nil {
+		return err
+	}
+	defer closeProject(srcProject)
+
+	dstProject, err := cfg.openProject(ctx, dstAccess, false)
+	if err != nil {
+		return err
+	}
+	defer closeProject(dstProject)
+
+	keys, err := listReencryptKeys(ctx, srcProject, srcBucket, srcPrefix)
+	if err != nil {
+		if storj.ErrBucketNotFound.Has(err) {
+			return fmt.Errorf("bucket not found: %s", srcBucket)
+		}
+		return err
+	}
+
+	for _, key := range keys {
+		srcObject, err := fpath.New(fmt.Sprintf("sj://%s/%s", srcBucket, key))
+		if err != nil {
+			return err
+		}
+		dstObject, err := fpath.New(fmt.Sprintf("sj://%s/%s%s", dstBucket, dstPrefix, strings.TrimPrefix(key, srcPrefix)))
+		if err != nil {
+			return err
+		}
+
+		_, err = transferObject(ctx, srcProject, dstProject, srcObject, dstObject, *reencryptProgress)
+		if err != nil {
+			return fmt.Errorf("failed to re-encrypt %s: %w", srcObject, err)
+		}
+		fmt.Printf("%s re-encrypted to %s\n", srcObject, dstObject)
+	}
+
+	fmt.Printf("Re-encrypted %d objects\n", len(keys))
+	return nil
+}
+
+// reencryptPrefix parses a Storj URL which is a bucket or a prefix and
+// returns the bucket and the prefix including its trailing slash.
+func reencryptPrefix(arg string) (bucket, prefix string, err error) {
+	path, err := fpath.New(arg)
+	if err != nil {
+		return "", "", err
+	}
+	if path.IsLocal() {
+		return "", "", fmt.Errorf("path must be Storj URL: %s", path)
+	}
+	if path.Path() == "" {
+		return path.Bucket(), "", nil
+	}
+	// fpath cleans the trailing slash from the path.
+	if !strings.HasSuffix(arg, "/") {
+		return "", "", fmt.Errorf("prefix must end with a slash: %s", path)
+	}
+	return path.Bucket(), path.Path() + "/", nil
+}
+
+// listReencryptKeys returns the keys of all objects under the prefix. The
+// keys are listed before any object is uploaded, so that the listing doesn't
+// include objects re-encrypted into the same prefix.
+func listReencryptKeys(ctx context.Context, project *uplink.Project, bucket, prefix string) (keys []string, err error) {
+	objects := project.ListObjects(ctx, bucket, &uplink.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	})
+	for objects.Next() {
+		keys = append(keys, objects.Item().Key)
+	}
+	if err := objects.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
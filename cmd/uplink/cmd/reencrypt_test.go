@@ -0,0 +1,121 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd_test
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/uplink"
+)
+
+func TestReencrypt(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		uplinkExe := ctx.Compile("storj.io/storj/cmd/uplink")
+
+		uplinkPeer := planet.Uplinks[0]
+		satellite := planet.Satellites[0]
+
+		oldAccess := uplinkPeer.Access[satellite.ID()]
+		oldAccessString, err := oldAccess.Serialize()
+		require.NoError(t, err)
+
+		newAccess, err := uplink.RequestAccessWithPassphrase(ctx, satellite.NodeURL().String(), uplinkPeer.APIKey[satellite.ID()].Serialize(), "new passphrase")
+		require.NoError(t, err)
+		newAccessString, err := newAccess.Serialize()
+		require.NoError(t, err)
+
+		output, err := exec.Command(uplinkExe,
+			"--config-dir", ctx.Dir("uplink"),
+			"import",
+			oldAccessString,
+		).CombinedOutput()
+		t.Log(string(output))
+		require.NoError(t, err)
+
+		oldProject, err := uplink.OpenProject(ctx, oldAccess)
+		require.NoError(t, err)
+		defer ctx.Check(oldProject.Close)
+
+		newProject, err := uplink.OpenProject(ctx, newAccess)
+		require.NoError(t, err)
+		defer ctx.Check(newProject.Close)
+
+		_, err = oldProject.CreateBucket(ctx, "old")
+		require.NoError(t, err)
+		_, err = oldProject.CreateBucket(ctx, "new")
+		require.NoError(t, err)
+
+		objects := map[string][]byte{
+			"prefix/a":   testrand.Bytes(5 * memory.KiB),
+			"prefix/b/c": testrand.Bytes(10 * memory.KiB),
+		}
+		for key, data := range objects {
+			upload, err := oldProject.UploadObject(ctx, "old", key, nil)
+			require.NoError(t, err)
+			_, err = upload.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, upload.SetCustomMetadata(ctx, uplink.CustomMetadata{"key": key}))
+			require.NoError(t, upload.Commit())
+		}
+		require.NoError(t, uplinkPeer.Upload(ctx, satellite, "old", "other", testrand.Bytes(memory.KiB)))
+
+		output, err = exec.Command(uplinkExe,
+			"--config-dir", ctx.Dir("uplink"),
+			"reencrypt",
+			"--progress=false",
+			"--dst-access", newAccessString,
+			"sj://old/prefix/", "sj://new/rotated/",
+		).CombinedOutput()
+		t.Log(string(output))
+		require.NoError(t, err)
+		require.Contains(t, string(output), "Re-encrypted 2 objects")
+
+		// the objects are readable with the new passphrase only
+		var keys []string
+		list := newProject.ListObjects(ctx, "new", &uplink.ListObjectsOptions{Recursive: true})
+		for list.Next() {
+			keys = append(keys, list.Item().Key)
+		}
+		require.NoError(t, list.Err())
+		require.ElementsMatch(t, []string{"rotated/a", "rotated/b/c"}, keys)
+
+		for key, data := range objects {
+			newKey := "rotated/" + key[len("prefix/"):]
+
+			download, err := newProject.DownloadObject(ctx, "new", newKey, nil)
+			require.NoError(t, err)
+			downloaded, err := ioutil.ReadAll(download)
+			require.NoError(t, err)
+			require.NoError(t, download.Close())
+			require.Equal(t, data, downloaded)
+			require.Equal(t, uplink.CustomMetadata{"key": key}, download.Info().Custom)
+
+			_, err = oldProject.DownloadObject(ctx, "new", newKey, nil)
+			require.Error(t, err)
+		}
+
+		// copying with the same access into the same prefix is rejected
+		output, err = exec.Command(uplinkExe,
+			"--config-dir", ctx.Dir("uplink"),
+			"reencrypt",
+			"--dst-access", oldAccessString,
+			"sj://old/prefix/",
+		).CombinedOutput()
+		t.Log(string(output))
+		require.Error(t, err)
+		require.Contains(t, string(output), "destination access must differ from the source access")
+	})
+}
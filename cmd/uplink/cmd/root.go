@@ -7,6 +7,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -33,6 +34,10 @@ import (
 
 const advancedFlagName = "advanced"
 
+// accessEnvVar is the environment variable the access grant is read from when
+// the --access flag isn't set.
+const accessEnvVar = "UPLINK_ACCESS"
+
 // UplinkFlags configuration flags.
 type UplinkFlags struct {
 	Config
@@ -46,6 +51,12 @@ var (
 	cfg     UplinkFlags
 	confDir string
 
+	// accessFlagChanged is whether the access was set explicitly with the --access flag.
+	accessFlagChanged bool
+
+	// outputFormat is the format of the results and errors, set with the --output flag.
+	outputFormat string
+
 	defaults = cfgstruct.DefaultsFlag(RootCmd)
 
 	// Error is the class of errors returned by this package.
@@ -60,8 +71,9 @@ func init() {
 
 	// NB: more-help flag is always retrieved using `findBoolFlagEarly()`
 	RootCmd.PersistentFlags().BoolVar(new(bool), advancedFlagName, false, "if used in with -h, print advanced flags help")
+	RootCmd.PersistentFlags().StringVar(&outputFormat, "output", "text", "output format of the results and errors, either text or json")
 
-	setBasicFlags(RootCmd.PersistentFlags(), "config-dir", advancedFlagName)
+	setBasicFlags(RootCmd.PersistentFlags(), "config-dir", advancedFlagName, "output")
 	setUsageFunc(RootCmd)
 }
 
@@ -73,11 +85,14 @@ var RootCmd = &cobra.Command{
 	Use:                "uplink",
 	Short:              "The Storj client-side CLI",
 	Args:               cobra.OnlyValidArgs,
-	PersistentPreRunE:  combineCobraFuncs(startCPUProfile, modifyFlagDefaults),
+	PersistentPreRunE:  combineCobraFuncs(startCPUProfile, modifyFlagDefaults, checkAccessFlag, checkOutputFlag),
 	PersistentPostRunE: stopAndWriteProfile,
 }
 
 func addCmd(cmd *cobra.Command, root *cobra.Command) *cobra.Command {
+	if cmd.RunE != nil {
+		cmd.RunE = withOutputError(cmd.RunE)
+	}
 	root.AddCommand(cmd)
 
 	process.Bind(cmd, &cfg, defaults, cfgstruct.ConfDir(getConfDir()))
@@ -85,12 +100,34 @@ func addCmd(cmd *cobra.Command, root *cobra.Command) *cobra.Command {
 	return cmd
 }
 
+// accessConfig returns the access configuration, with the access taken from
+// the UPLINK_ACCESS environment variable if it is set.
+func (cliCfg *UplinkFlags) accessConfig() AccessConfig {
+	return cliCfg.AccessConfig.withAccessEnv()
+}
+
+// withAccessEnv returns the access configuration with the access taken from
+// the UPLINK_ACCESS environment variable if it is set. The --access flag takes
+// precedence over the environment variable, which takes precedence over the
+// configuration file.
+func (a AccessConfig) withAccessEnv() AccessConfig {
+	if access := os.Getenv(accessEnvVar); access != "" && !accessFlagChanged {
+		a.Access = access
+	}
+	return a
+}
+
 func (cliCfg *UplinkFlags) getProject(ctx context.Context, encryptionBypass bool) (_ *uplink.Project, err error) {
-	access, err := cfg.GetAccess()
+	access, err := cliCfg.accessConfig().GetAccess()
 	if err != nil {
 		return nil, err
 	}
 
+	return cliCfg.openProject(ctx, access, encryptionBypass)
+}
+
+// openProject opens a project for the given access with the configured client options.
+func (cliCfg *UplinkFlags) openProject(ctx context.Context, access *uplink.Access, encryptionBypass bool) (_ *uplink.Project, err error) {
 	uplinkCfg := uplink.Config{}
 	uplinkCfg.UserAgent = cliCfg.Client.UserAgent
 	uplinkCfg.DialTimeout = cliCfg.Client.DialTimeout
@@ -188,6 +225,68 @@ func modifyFlagDefaults(cmd *cobra.Command, args []string) (err error) {
 	return nil
 }
 
+func checkAccessFlag(cmd *cobra.Command, args []string) (err error) {
+	if accessFlag := cmd.Flag("access"); accessFlag != nil {
+		accessFlagChanged = accessFlag.Changed
+	}
+	return nil
+}
+
+func checkOutputFlag(cmd *cobra.Command, args []string) (err error) {
+	switch outputFormat {
+	case "text", "json":
+		return nil
+	default:
+		return Error.New("invalid output format: %s", outputFormat)
+	}
+}
+
+// OutputError is the error of a command run with the json output format. It
+// carries the JSON payload to print on stderr.
+type OutputError struct {
+	Err     error
+	Payload []byte
+}
+
+// Error returns the message of the command error.
+func (err *OutputError) Error() string { return err.Err.Error() }
+
+// Unwrap returns the command error.
+func (err *OutputError) Unwrap() error { return err.Err }
+
+// commandOutputError is the error of the command that ran, set by withOutputError.
+var commandOutputError *OutputError
+
+// CommandOutputError returns the error of the command that ran when the output
+// format is json, or nil. The caller is expected to print its payload and exit
+// with a failure once the root command has been executed.
+func CommandOutputError() *OutputError {
+	return commandOutputError
+}
+
+// withOutputError hands the error returned by runE over to
+// CommandOutputError, encoded as JSON, when the output format is json.
+// Otherwise process prints the error as text.
+//
+// process exits as soon as RunE returns an error, which would skip
+// PersistentPostRunE and print the error as text. So the json error isn't
+// returned but kept until the root command has returned.
+func withOutputError(runE func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		err := runE(cmd, args)
+		if err == nil || outputFormat != "json" {
+			return err
+		}
+
+		payload, encodeErr := json.Marshal(map[string]string{"error": err.Error()})
+		if encodeErr != nil {
+			return err
+		}
+		commandOutputError = &OutputError{Err: err, Payload: payload}
+		return nil
+	}
+}
+
 func combineCobraFuncs(funcs ...func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) (err error) {
 		for _, fn := range funcs {
@@ -164,7 +164,7 @@ func createAccessGrant(args []string) (newAccess *uplink.Access, newAccessData s
 		uplinkSharePrefixes = append(uplinkSharePrefixes, uplinkSharePrefix)
 	}
 
-	access, err := shareCfg.GetAccess()
+	access, err := shareCfg.AccessConfig.withAccessEnv().GetAccess()
 	if err != nil {
 		return newAccess, newAccessData, sharePrefixes, permission, err
 	}
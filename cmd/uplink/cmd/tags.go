@@ -0,0 +1,109 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"storj.io/uplink"
+)
+
+const (
+	// tagPrefix is the prefix of the custom metadata keys that store tags.
+	tagPrefix = "tag:"
+
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// parseTags parses the key=value arguments of the --tag flag.
+func parseTags(args []string) (map[string]string, error) {
+	tags := make(map[string]string, len(args))
+	for _, arg := range args {
+		split := strings.Index(arg, "=")
+		if split < 0 {
+			return nil, fmt.Errorf("invalid tag %q: must be key=value", arg)
+		}
+		key, value := arg[:split], arg[split+1:]
+
+		if err := validateTag(key, value); err != nil {
+			return nil, fmt.Errorf("invalid tag %q: %w", arg, err)
+		}
+		if _, ok := tags[key]; ok {
+			return nil, fmt.Errorf("invalid tag %q: duplicate key", arg)
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+// validateTag checks the key and the value against the tag rules.
+func validateTag(key, value string) error {
+	if key == "" {
+		return fmt.Errorf("key is empty")
+	}
+	if len(key) > maxTagKeyLength {
+		return fmt.Errorf("key is longer than %d characters", maxTagKeyLength)
+	}
+	for _, r := range key {
+		if !isTagKeyRune(r) {
+			return fmt.Errorf("key contains invalid character %q", r)
+		}
+	}
+
+	if !utf8.ValidString(value) {
+		return fmt.Errorf("value is not valid UTF-8")
+	}
+	if utf8.RuneCountInString(value) > maxTagValueLength {
+		return fmt.Errorf("value is longer than %d characters", maxTagValueLength)
+	}
+	for _, r := range value {
+		if !unicode.IsPrint(r) {
+			return fmt.Errorf("value contains invalid character %q", r)
+		}
+	}
+	return nil
+}
+
+func isTagKeyRune(r rune) bool {
+	switch {
+	case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		return true
+	case strings.ContainsRune("_.-:/", r):
+		return true
+	}
+	return false
+}
+
+// addTags adds the tags to the custom metadata with the tag prefix.
+func addTags(customMetadata uplink.CustomMetadata, tags map[string]string) {
+	for key, value := range tags {
+		customMetadata[tagPrefix+key] = value
+	}
+}
+
+// objectTags returns the tags stored in the custom metadata.
+func objectTags(customMetadata uplink.CustomMetadata) map[string]string {
+	tags := make(map[string]string)
+	for key, value := range customMetadata {
+		if strings.HasPrefix(key, tagPrefix) {
+			tags[strings.TrimPrefix(key, tagPrefix)] = value
+		}
+	}
+	return tags
+}
+
+// formatTags formats the tags as sorted key=value lines.
+func formatTags(tags map[string]string) string {
+	lines := make([]string, 0, len(tags))
+	for key, value := range tags {
+		lines = append(lines, "Tag "+key+"="+value)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
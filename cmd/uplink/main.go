@@ -4,6 +4,9 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
@@ -24,4 +27,9 @@ func main() {
 		}
 		return nil
 	})
+
+	if err := cmd.CommandOutputError(); err != nil {
+		fmt.Fprintln(os.Stderr, string(err.Payload))
+		os.Exit(1)
+	}
 }
@@ -26,8 +26,12 @@ type external struct {
 	}
 
 	migration struct {
-		migrated bool  // true if a migration has been attempted
-		err      error // any error from the migration attempt
+		migrated      bool     // true if a migration has been attempted
+		err           error    // any error from the migration attempt
+		skipInvalid   bool     // true if invalid legacy accesses should be skipped
+		warnings      []string // any warnings from the migration attempt
+		extraFiles    []string // additional legacy config files to merge
+		defaultAccess string   // legacy access to use as the default instead of the legacy default
 	}
 
 	config struct {
@@ -64,6 +68,23 @@ func (ex *external) Setup(f clingy.Flags) {
 		clingy.Advanced,
 	).(string)
 
+	ex.migration.skipInvalid = f.Flag(
+		"migrate-skip-invalid-accesses", "Skips invalid accesses in the legacy configuration instead of failing. Only used during migration", false,
+		clingy.Transform(strconv.ParseBool),
+		clingy.Advanced,
+	).(bool)
+
+	ex.migration.extraFiles = f.Flag(
+		"legacy-config-file", "Additional legacy configuration files to merge. Only used during migration", []string{},
+		clingy.Repeated,
+		clingy.Advanced,
+	).([]string)
+
+	ex.migration.defaultAccess = f.Flag(
+		"migrate-default-access", "Name of the legacy access to use as the default access instead of the legacy default. Only used during migration", "",
+		clingy.Advanced,
+	).(string)
+
 	ex.dirs.loaded = true
 }
 
@@ -71,6 +92,12 @@ func (ex *external) AccessInfoFile() string   { return filepath.Join(ex.dirs.cur
 func (ex *external) ConfigFile() string       { return filepath.Join(ex.dirs.current, "config.ini") }
 func (ex *external) legacyConfigFile() string { return filepath.Join(ex.dirs.legacy, "config.yaml") }
 
+// legacyConfigFiles returns the default legacy config file followed by any
+// additional legacy config files that should be merged during migration.
+func (ex *external) legacyConfigFiles() []string {
+	return append([]string{ex.legacyConfigFile()}, ex.migration.extraFiles...)
+}
+
 // Dynamic is called by clingy to look up values for global flags not specified on the command
 // line. This call lets us fill in values from config files or environment variables.
 func (ex *external) Dynamic(name string) (vals []string, err error) {
@@ -102,6 +129,10 @@ func (ex *external) Wrap(ctx clingy.Context, cmd clingy.Command) error {
 	if err := ex.migrate(); err != nil {
 		return err
 	}
+	for _, warning := range ex.migration.warnings {
+		fmt.Fprintln(ctx.Stderr(), "Warning:", warning)
+	}
+	ex.migration.warnings = nil
 	if err := ex.loadConfig(); err != nil {
 		return err
 	}
@@ -87,9 +87,21 @@ func (ex *external) GetAccessInfo(required bool) (string, map[string]string, err
 
 // SaveAccessInfo writes out the access file using the provided values.
 func (ex *external) SaveAccessInfo(defaultName string, accesses map[string]string) error {
-	// TODO(jeff): write it atomically
+	// write to a temporary path and rename it into place so that a failed
+	// write never leaves a truncated access file behind.
+	accessTmp := ex.AccessInfoFile() + ".tmp"
+	defer func() { _ = os.Remove(accessTmp) }()
 
-	accessFh, err := os.OpenFile(ex.AccessInfoFile(), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err := writeAccessFile(accessTmp, defaultName, accesses); err != nil {
+		return err
+	}
+	return errs.Wrap(os.Rename(accessTmp, ex.AccessInfoFile()))
+}
+
+// writeAccessFile writes out the default access name and accesses as json at
+// the provided path.
+func writeAccessFile(path string, defaultName string, accesses map[string]string) error {
+	accessFh, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		return errs.Wrap(err)
 	}
@@ -4,6 +4,8 @@
 package main
 
 import (
+	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
@@ -43,47 +45,63 @@ func (ex *external) loadConfig() error {
 	return nil
 }
 
+// configEntry is an ini entry along with a comment to write above it.
+type configEntry struct {
+	ini.Entry
+	Comment string
+}
+
+// sortConfigEntries sorts the entries by section and then by key.
+func sortConfigEntries(entries []configEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Section == entries[j].Section {
+			return entries[i].Key < entries[j].Key
+		}
+		return entries[i].Section < entries[j].Section
+	})
+}
+
 // SaveConfig writes out the config file using the provided values.
 // It is only intended to be used during initial migration and setup.
 func (ex *external) SaveConfig(values map[string]string) error {
-	entries := make([]ini.Entry, 0, len(values))
+	entries := make([]configEntry, 0, len(values))
 	for k, v := range values {
 		var section string
 		if idx := strings.LastIndexByte(k, '.'); idx >= 0 {
 			section, k = k[:idx], k[idx+1:]
 		}
-		entries = append(entries, ini.Entry{
+		entries = append(entries, configEntry{Entry: ini.Entry{
 			Section: section,
 			Key:     k,
 			Value:   v,
-		})
+		}})
 	}
-	sort.Slice(entries, func(i, j int) bool {
-		if entries[i].Section == entries[j].Section {
-			return entries[i].Key < entries[j].Key
-		}
-		return entries[i].Section < entries[j].Section
-	})
+	sortConfigEntries(entries)
 	return ex.saveConfig(entries)
 }
 
 // saveConfig writes out the config file using the provided values.
 // It is only intended to be used during initial migration and setup.
-func (ex *external) saveConfig(entries []ini.Entry) error {
-	// TODO(jeff): write it atomically
+func (ex *external) saveConfig(entries []configEntry) error {
+	// write to a temporary path and rename it into place, like the access file.
+	configTmp := ex.ConfigFile() + ".tmp"
+	defer func() { _ = os.Remove(configTmp) }()
+
+	if err := writeConfigFile(configTmp, entries); err != nil {
+		return err
+	}
+	return errs.Wrap(os.Rename(configTmp, ex.ConfigFile()))
+}
 
-	newFh, err := os.Create(ex.ConfigFile())
+// writeConfigFile writes out the entries as an ini file at the provided path.
+func writeConfigFile(path string, entries []configEntry) error {
+	newFh, err := os.Create(path)
 	if err != nil {
 		return errs.Wrap(err)
 	}
 	defer func() { _ = newFh.Close() }()
 
-	err = ini.Write(newFh, func(emit func(ini.Entry)) {
-		for _, ent := range entries {
-			emit(ent)
-		}
-	})
-	if err != nil {
+	if err := writeConfigEntries(newFh, entries); err != nil {
 		return errs.Wrap(err)
 	}
 
@@ -97,3 +115,51 @@ func (ex *external) saveConfig(entries []ini.Entry) error {
 
 	return nil
 }
+
+// writeConfigEntries writes the entries in ini format with their comments. The ini
+// package has no support for comments, so we write the section headers and comments
+// ourselves and use ini.Write for each individual key and value.
+func writeConfigEntries(w io.Writer, entries []configEntry) error {
+	var section string
+	var wrote bool
+
+	for _, ent := range entries {
+		if ent.Section != section {
+			if wrote {
+				if _, err := fmt.Fprintln(w); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintf(w, "[%s]\n", escapeConfigSection(ent.Section)); err != nil {
+				return err
+			}
+			section = ent.Section
+		}
+
+		if ent.Comment != "" {
+			for _, line := range strings.Split(ent.Comment, "\n") {
+				if _, err := fmt.Fprintln(w, strings.TrimSpace("# "+line)); err != nil {
+					return err
+				}
+			}
+		}
+
+		// the section header has already been written, so clear the section to
+		// keep ini.Write from writing it again.
+		entry := ent.Entry
+		entry.Section = ""
+		if err := ini.Write(w, func(emit func(ini.Entry)) { emit(entry) }); err != nil {
+			return err
+		}
+
+		wrote = true
+	}
+
+	return nil
+}
+
+// escapeConfigSection escapes the newlines in a section name the same way
+// ini.Write does, so that the section header is read back as a single line.
+func escapeConfigSection(section string) string {
+	return strings.ReplaceAll(section, "\n", "\\\n")
+}
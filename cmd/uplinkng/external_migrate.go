@@ -4,18 +4,24 @@
 package main
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/zeebo/errs"
 	"github.com/zeebo/ini"
 	"gopkg.in/yaml.v3"
+
+	"storj.io/uplink"
 )
 
-// migrate attempts to create the config file from the old config file if the
+// migrate attempts to create the config file from the old config files if the
 // config file does not exist. It will only attempt to do so at most once
-// and so calls to migrate are idempotent.
+// and so calls to migrate are idempotent. The legacy config files are never
+// modified, and any existing file that would be overwritten is first copied
+// to a file with a ".backup" suffix.
 func (ex *external) migrate() (err error) {
 	if ex.migration.migrated {
 		return ex.migration.err
@@ -30,16 +36,24 @@ func (ex *external) migrate() (err error) {
 		return nil
 	}
 
-	// if the old config file does not exist, we cannot migrate
-	legacyFh, err := os.Open(ex.legacyConfigFile())
+	// load the information necessary to write the new config from
+	// the old files.
+	ok, access, accesses, entries, err := ex.loadLegacyConfigs()
 	if err != nil {
+		return errs.Wrap(err)
+	} else if !ok {
+		// if no old config file exists, we cannot migrate
 		return nil
 	}
-	defer func() { _ = legacyFh.Close() }()
 
-	// load the information necessary to write the new config from
-	// the old file.
-	access, accesses, entries, err := ex.parseLegacyConfig(legacyFh)
+	// ensure that every access we are about to write can actually be used.
+	if err := ex.validateLegacyAccesses(accesses); err != nil {
+		return errs.Wrap(err)
+	}
+
+	// the default is resolved once invalid accesses have been skipped so that
+	// it never names an access that is not migrated.
+	access, err = ex.resolveLegacyDefault(access, accesses)
 	if err != nil {
 		return errs.Wrap(err)
 	}
@@ -49,14 +63,38 @@ func (ex *external) migrate() (err error) {
 		return errs.Wrap(err)
 	}
 
-	// first, create and write the access file. that way, if there's an error
-	// creating the config file, we will recreate this file.
-	if err := ex.SaveAccessInfo(access, accesses); err != nil {
+	// write both new files out to temporary paths first so that a failure
+	// writing either one does not leave us in a half migrated state. the
+	// legacy config files are only ever read and are left untouched.
+	accessTmp := ex.AccessInfoFile() + ".tmp"
+	configTmp := ex.ConfigFile() + ".tmp"
+	defer func() {
+		_ = os.Remove(accessTmp)
+		_ = os.Remove(configTmp)
+	}()
+
+	if err := writeAccessFile(accessTmp, access, accesses); err != nil {
+		return errs.Wrap(err)
+	}
+	if err := writeConfigFile(configTmp, entries); err != nil {
 		return errs.Wrap(err)
 	}
 
-	// now, write out the config file from the stored entries.
-	if err := ex.saveConfig(entries); err != nil {
+	// keep a backup of anything that we are about to overwrite.
+	if err := backupFile(ex.AccessInfoFile()); err != nil {
+		return errs.Wrap(err)
+	}
+	if err := backupFile(ex.ConfigFile()); err != nil {
+		return errs.Wrap(err)
+	}
+
+	// move the access file into place first. the config file existing is what
+	// marks the migration as complete, so if renaming it fails we will attempt
+	// the migration again next time.
+	if err := os.Rename(accessTmp, ex.AccessInfoFile()); err != nil {
+		return errs.Wrap(err)
+	}
+	if err := os.Rename(configTmp, ex.ConfigFile()); err != nil {
 		return errs.Wrap(err)
 	}
 
@@ -64,12 +102,168 @@ func (ex *external) migrate() (err error) {
 	return nil
 }
 
+// backupFile copies the file at path to a file with a ".backup" suffix if
+// the file exists.
+func backupFile(path string) (err error) {
+	fh, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errs.Wrap(err)
+	}
+	defer func() { _ = fh.Close() }()
+
+	info, err := fh.Stat()
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	backupFh, err := os.OpenFile(path+".backup", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, backupFh.Close()) }()
+
+	if _, err := io.Copy(backupFh, fh); err != nil {
+		return errs.Wrap(err)
+	}
+
+	return errs.Wrap(backupFh.Sync())
+}
+
+// loadLegacyConfigs loads and merges every legacy config file. The default legacy
+// config file is skipped if it does not exist, but any additionally specified
+// files must exist. The first access found becomes the default access, and any
+// access names that are defined differently in multiple files are reported as an
+// error rather than being overwritten. It returns false if no files were loaded.
+func (ex *external) loadLegacyConfigs() (bool, string, map[string]string, []configEntry, error) {
+	var (
+		loaded     bool
+		access     string
+		accesses   = make(map[string]string)
+		entries    = make([]configEntry, 0)
+		seen       = make(map[string]bool)
+		collisions []string
+	)
+
+	for i, path := range ex.legacyConfigFiles() {
+		fileAccess, fileAccesses, fileEntries, err := ex.parseLegacyConfigFile(path)
+		if i == 0 && os.IsNotExist(errs.Unwrap(err)) {
+			continue
+		} else if err != nil {
+			return false, "", nil, nil, errs.New("%s: %w", path, err)
+		}
+		loaded = true
+
+		if access == "" {
+			access = fileAccess
+		}
+
+		for name, data := range fileAccesses {
+			if existing, ok := accesses[name]; ok && existing != data {
+				collisions = append(collisions, fmt.Sprintf("%q in %s", name, path))
+				continue
+			}
+			accesses[name] = data
+		}
+
+		// the first file to set a config value wins.
+		for _, ent := range fileEntries {
+			key := ent.Section + "." + ent.Key
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			entries = append(entries, ent)
+		}
+	}
+
+	if len(collisions) > 0 {
+		sort.Strings(collisions)
+		return false, "", nil, nil, errs.New("conflicting legacy access names: %s", strings.Join(collisions, ", "))
+	}
+	sortConfigEntries(entries)
+
+	return loaded, access, accesses, entries, nil
+}
+
+// parseLegacyConfigFile opens the file at path and parses it as a legacy config.
+func (ex *external) parseLegacyConfigFile(path string) (string, map[string]string, []configEntry, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return "", nil, nil, errs.Wrap(err)
+	}
+	defer func() { _ = fh.Close() }()
+
+	return ex.parseLegacyConfig(fh)
+}
+
+// validateLegacyAccesses checks that every access in accesses can be parsed as an
+// access grant. Invalid accesses are either removed from accesses with a warning
+// or cause an error to be returned, depending on if skipping is enabled.
+func (ex *external) validateLegacyAccesses(accesses map[string]string) error {
+	names := make([]string, 0, len(accesses))
+	for name := range accesses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := uplink.ParseAccess(accesses[name]); err != nil {
+			if !ex.migration.skipInvalid {
+				return errs.New("legacy access %q is invalid: %w", name, err)
+			}
+			ex.migration.warnings = append(ex.migration.warnings,
+				fmt.Sprintf("skipping invalid legacy access %q: %v", name, err))
+			delete(accesses, name)
+		}
+	}
+
+	return nil
+}
+
+// resolveLegacyDefault returns the default access to write for the migrated
+// accesses. The legacy default is replaced by the access named by
+// --migrate-default-access if it is set. A legacy default that is neither a
+// migrated access name nor a valid access grant is either dropped with a
+// warning or causes an error to be returned, depending on if skipping is
+// enabled.
+func (ex *external) resolveLegacyDefault(access string, accesses map[string]string) (string, error) {
+	if name := ex.migration.defaultAccess; name != "" {
+		if _, ok := accesses[name]; !ok {
+			return "", errs.New("default access %q is not a legacy access", name)
+		}
+		return name, nil
+	}
+
+	if access == "" {
+		return "", nil
+	}
+	if _, ok := accesses[access]; ok {
+		return access, nil
+	}
+	// legacy configs may set the default to an access grant instead of a name.
+	if _, err := uplink.ParseAccess(access); err == nil {
+		return access, nil
+	}
+
+	// the value is not included in the messages since it may be a malformed
+	// access grant.
+	if !ex.migration.skipInvalid {
+		return "", errs.New("legacy default access is neither a valid legacy access name nor a valid access grant")
+	}
+	ex.migration.warnings = append(ex.migration.warnings,
+		"skipping invalid legacy default access, no default access is set")
+	return "", nil
+}
+
 // parseLegacyConfig loads the default access name, the map of available accesses, and
-// a list of config entries from the yaml file in the reader.
-func (ex *external) parseLegacyConfig(r io.Reader) (string, map[string]string, []ini.Entry, error) {
+// a list of config entries from the yaml file in the reader. The entries are sorted by
+// section and then key, and keep any comments that were attached to them.
+func (ex *external) parseLegacyConfig(r io.Reader) (string, map[string]string, []configEntry, error) {
 	access := ""
 	accesses := make(map[string]string)
-	entries := make([]ini.Entry, 0)
+	entries := make([]configEntry, 0)
 
 	// load the old config if possible and write out a new config
 	var node yaml.Node
@@ -112,10 +306,13 @@ func (ex *external) parseLegacyConfig(r io.Reader) (string, map[string]string, [
 				} else if section == "accesses" {
 					accesses[key] = value
 				} else {
-					entries = append(entries, ini.Entry{
-						Key:     key,
-						Value:   value,
-						Section: section,
+					entries = append(entries, configEntry{
+						Entry: ini.Entry{
+							Key:     key,
+							Value:   value,
+							Section: section,
+						},
+						Comment: legacyComment(keyn, valuen),
 					})
 				}
 
@@ -142,5 +339,23 @@ func (ex *external) parseLegacyConfig(r io.Reader) (string, map[string]string, [
 		return "", nil, nil, err
 	}
 
+	// sort the entries so that the migrated config is stable and readable.
+	sortConfigEntries(entries)
+
 	return access, accesses, entries, nil
 }
+
+// legacyComment returns the text of the head and line comments attached to a
+// yaml key and value with the leading comment markers removed.
+func legacyComment(keyn, valuen *yaml.Node) string {
+	var lines []string
+	for _, comment := range []string{keyn.HeadComment, keyn.LineComment, valuen.LineComment} {
+		if comment == "" {
+			continue
+		}
+		for _, line := range strings.Split(comment, "\n") {
+			lines = append(lines, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#")))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
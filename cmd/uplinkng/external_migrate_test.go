@@ -0,0 +1,370 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/grant"
+	"storj.io/common/macaroon"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+)
+
+// testAccess returns a serialized access grant that is valid to parse.
+func testAccess(t *testing.T, satelliteAddress string) string {
+	apiKey, err := macaroon.NewAPIKey([]byte("secret"))
+	require.NoError(t, err)
+
+	access, err := (&grant.Access{
+		SatelliteAddress: satelliteAddress,
+		APIKey:           apiKey,
+		EncAccess:        grant.NewEncryptionAccessWithDefaultKey(&storj.Key{}),
+	}).Serialize()
+	require.NoError(t, err)
+
+	return access
+}
+
+// testLegacyConfig returns the contents of a legacy config file that has two
+// valid accesses named main and other.
+func testLegacyConfig(t *testing.T) (config, main, other string) {
+	main = testAccess(t, "12whfK1EDvHJtajBiAUeajQLYcWqxcQmdYQU5zX5cCf6bAxfgu4@main.example.test:7777")
+	other = testAccess(t, "12whfK1EDvHJtajBiAUeajQLYcWqxcQmdYQU5zX5cCf6bAxfgu4@other.example.test:7777")
+	config = "access: main\n" +
+		"accesses.main: " + main + "\n" +
+		"accesses.other: " + other + "\n" +
+		"client.user-agent: test\n"
+	return config, main, other
+}
+
+// newMigrateExternal returns an external with its config directories set up
+// inside of the test context and a legacy config file with the provided
+// contents.
+func newMigrateExternal(ctx *testcontext.Context, legacy string) *external {
+	ex := newExternal()
+	ex.dirs.current = ctx.Dir("current")
+	ex.dirs.legacy = ctx.Dir("legacy")
+	ex.dirs.loaded = true
+
+	ctx.Check(func() error { return ioutil.WriteFile(ex.legacyConfigFile(), []byte(legacy), 0644) })
+
+	return ex
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	legacy, main, other := testLegacyConfig(t)
+
+	ex := newMigrateExternal(ctx, legacy)
+	require.NoError(t, ex.migrate())
+
+	defaultName, accesses, err := ex.GetAccessInfo(true)
+	require.NoError(t, err)
+	require.Equal(t, "main", defaultName)
+	require.Equal(t, map[string]string{
+		"main":  main,
+		"other": other,
+	}, accesses)
+
+	require.NoError(t, ex.loadConfig())
+	require.Equal(t, []string{"test"}, ex.config.values["client.user-agent"])
+
+	// the legacy config must be left untouched.
+	data, err := ioutil.ReadFile(ex.legacyConfigFile())
+	require.NoError(t, err)
+	require.Equal(t, legacy, string(data))
+
+	// no temporary files should remain.
+	requireNoFile(t, ex.AccessInfoFile()+".tmp")
+	requireNoFile(t, ex.ConfigFile()+".tmp")
+}
+
+func TestMigrateBackup(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	legacy, _, _ := testLegacyConfig(t)
+
+	ex := newMigrateExternal(ctx, legacy)
+	require.NoError(t, ioutil.WriteFile(ex.AccessInfoFile(), []byte("previous"), 0600))
+
+	require.NoError(t, ex.migrate())
+
+	data, err := ioutil.ReadFile(ex.AccessInfoFile() + ".backup")
+	require.NoError(t, err)
+	require.Equal(t, "previous", string(data))
+
+	_, _, err = ex.GetAccessInfo(true)
+	require.NoError(t, err)
+}
+
+func TestMigrateSaveConfigFailure(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	legacy, _, _ := testLegacyConfig(t)
+
+	ex := newMigrateExternal(ctx, legacy)
+
+	// a directory where the temporary config file would be written causes
+	// writing the config to fail after the access file has been written.
+	require.NoError(t, os.Mkdir(ex.ConfigFile()+".tmp", 0755))
+
+	require.Error(t, ex.migrate())
+
+	requireNoFile(t, ex.AccessInfoFile())
+	requireNoFile(t, ex.AccessInfoFile()+".tmp")
+	requireNoFile(t, ex.ConfigFile())
+
+	data, err := ioutil.ReadFile(ex.legacyConfigFile())
+	require.NoError(t, err)
+	require.Equal(t, legacy, string(data))
+}
+
+func TestMigrateInvalidAccess(t *testing.T) {
+	valid := testAccess(t, "12whfK1EDvHJtajBiAUeajQLYcWqxcQmdYQU5zX5cCf6bAxfgu4@main.example.test:7777")
+	legacy := "access: valid\n" +
+		"accesses.valid: " + valid + "\n" +
+		"accesses.malformed: not-an-access\n"
+
+	t.Run("Fail", func(t *testing.T) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		ex := newMigrateExternal(ctx, legacy)
+
+		err := ex.migrate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "malformed")
+
+		requireNoFile(t, ex.AccessInfoFile())
+		requireNoFile(t, ex.ConfigFile())
+	})
+
+	t.Run("Skip", func(t *testing.T) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		ex := newMigrateExternal(ctx, legacy)
+		ex.migration.skipInvalid = true
+
+		require.NoError(t, ex.migrate())
+		require.Len(t, ex.migration.warnings, 1)
+		require.Contains(t, ex.migration.warnings[0], "malformed")
+
+		defaultName, accesses, err := ex.GetAccessInfo(true)
+		require.NoError(t, err)
+		require.Equal(t, "valid", defaultName)
+		require.Equal(t, map[string]string{"valid": valid}, accesses)
+	})
+}
+
+func TestMigrateDefaultAccess(t *testing.T) {
+	valid := testAccess(t, "12whfK1EDvHJtajBiAUeajQLYcWqxcQmdYQU5zX5cCf6bAxfgu4@main.example.test:7777")
+
+	t.Run("Override", func(t *testing.T) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		legacy, _, _ := testLegacyConfig(t)
+
+		ex := newMigrateExternal(ctx, legacy)
+		ex.migration.defaultAccess = "other"
+		require.NoError(t, ex.migrate())
+
+		defaultName, _, err := ex.GetAccessInfo(true)
+		require.NoError(t, err)
+		require.Equal(t, "other", defaultName)
+	})
+
+	t.Run("OverrideMissing", func(t *testing.T) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		legacy, _, _ := testLegacyConfig(t)
+
+		ex := newMigrateExternal(ctx, legacy)
+		ex.migration.defaultAccess = "missing"
+
+		err := ex.migrate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `"missing"`)
+		requireNoFile(t, ex.ConfigFile())
+	})
+
+	t.Run("Grant", func(t *testing.T) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		ex := newMigrateExternal(ctx, "access: "+valid+"\n")
+		require.NoError(t, ex.migrate())
+
+		defaultName, _, err := ex.GetAccessInfo(true)
+		require.NoError(t, err)
+		require.Equal(t, valid, defaultName)
+	})
+
+	t.Run("SkippedDefault", func(t *testing.T) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		legacy := "access: malformed\n" +
+			"accesses.valid: " + valid + "\n" +
+			"accesses.malformed: not-an-access\n"
+
+		ex := newMigrateExternal(ctx, legacy)
+		ex.migration.skipInvalid = true
+		require.NoError(t, ex.migrate())
+		require.Len(t, ex.migration.warnings, 2)
+
+		defaultName, accesses, err := ex.GetAccessInfo(true)
+		require.NoError(t, err)
+		require.Equal(t, "", defaultName)
+		require.Equal(t, map[string]string{"valid": valid}, accesses)
+	})
+
+	t.Run("UndefinedDefault", func(t *testing.T) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		ex := newMigrateExternal(ctx, "access: undefined\n"+
+			"accesses.valid: "+valid+"\n")
+
+		require.Error(t, ex.migrate())
+		requireNoFile(t, ex.AccessInfoFile())
+		requireNoFile(t, ex.ConfigFile())
+	})
+}
+
+func TestMigrateMultipleLegacyConfigs(t *testing.T) {
+	first := testAccess(t, "12whfK1EDvHJtajBiAUeajQLYcWqxcQmdYQU5zX5cCf6bAxfgu4@first.example.test:7777")
+	second := testAccess(t, "12whfK1EDvHJtajBiAUeajQLYcWqxcQmdYQU5zX5cCf6bAxfgu4@second.example.test:7777")
+	shared := testAccess(t, "12whfK1EDvHJtajBiAUeajQLYcWqxcQmdYQU5zX5cCf6bAxfgu4@shared.example.test:7777")
+
+	t.Run("Merge", func(t *testing.T) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		ex := newMigrateExternal(ctx, "access: first\n"+
+			"accesses.first: "+first+"\n"+
+			"accesses.shared: "+shared+"\n"+
+			"client.user-agent: first\n")
+		ex.migration.extraFiles = []string{ctx.File("extra", "config.yaml")}
+		require.NoError(t, ioutil.WriteFile(ex.migration.extraFiles[0], []byte("access: second\n"+
+			"accesses.second: "+second+"\n"+
+			"accesses.shared: "+shared+"\n"+
+			"client.user-agent: second\n"), 0644))
+
+		require.NoError(t, ex.migrate())
+
+		defaultName, accesses, err := ex.GetAccessInfo(true)
+		require.NoError(t, err)
+		require.Equal(t, "first", defaultName)
+		require.Equal(t, map[string]string{
+			"first":  first,
+			"second": second,
+			"shared": shared,
+		}, accesses)
+
+		require.NoError(t, ex.loadConfig())
+		require.Equal(t, []string{"first"}, ex.config.values["client.user-agent"])
+	})
+
+	t.Run("Collision", func(t *testing.T) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		ex := newMigrateExternal(ctx, "access: shared\n"+
+			"accesses.shared: "+first+"\n")
+		ex.migration.extraFiles = []string{ctx.File("extra", "config.yaml")}
+		require.NoError(t, ioutil.WriteFile(ex.migration.extraFiles[0], []byte(
+			"accesses.shared: "+second+"\n"), 0644))
+
+		err := ex.migrate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `"shared"`)
+
+		requireNoFile(t, ex.AccessInfoFile())
+		requireNoFile(t, ex.ConfigFile())
+	})
+
+	t.Run("MissingExtra", func(t *testing.T) {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		ex := newMigrateExternal(ctx, "access: first\n"+
+			"accesses.first: "+first+"\n")
+		ex.migration.extraFiles = []string{ctx.File("missing", "config.yaml")}
+
+		require.Error(t, ex.migrate())
+		requireNoFile(t, ex.ConfigFile())
+	})
+}
+
+func TestMigrateCommentsAndOrdering(t *testing.T) {
+	legacy := "# the default access\n" +
+		"access: main\n" +
+		"accesses.main: " + testAccess(t, "12whfK1EDvHJtajBiAUeajQLYcWqxcQmdYQU5zX5cCf6bAxfgu4@main.example.test:7777") + "\n" +
+		"metrics.interval: 1m0s\n" +
+		"# the user agent to report\n" +
+		"client.user-agent: test\n" +
+		"client.dial-timeout: 20s # how long to wait\n" +
+		"log:\n" +
+		"  # the log level\n" +
+		"  level: info\n"
+
+	migrated := func() string {
+		ctx := testcontext.New(t)
+		defer ctx.Cleanup()
+
+		ex := newMigrateExternal(ctx, legacy)
+		require.NoError(t, ex.migrate())
+
+		data, err := ioutil.ReadFile(ex.ConfigFile())
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	config := migrated()
+	for i := 0; i < 5; i++ {
+		require.Equal(t, config, migrated())
+	}
+
+	require.Equal(t, ""+
+		"# how long to wait\n"+
+		"client.dial-timeout = 20s\n"+
+		"# the user agent to report\n"+
+		"client.user-agent = test\n"+
+		"metrics.interval = 1m0s\n"+
+		"\n"+
+		"[log]\n"+
+		"# the log level\n"+
+		"level = info\n",
+		config)
+}
+
+func TestMigrateSectionEscaping(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	ex := newMigrateExternal(ctx, "\"multi\\nline\":\n"+
+		"  key: value\n")
+	require.NoError(t, ex.migrate())
+
+	require.NoError(t, ex.loadConfig())
+	require.Equal(t, []string{"value"}, ex.config.values["multi\nline.key"])
+}
+
+func requireNoFile(t *testing.T, path string) {
+	_, err := os.Stat(path)
+	require.True(t, os.IsNotExist(err), "%s should not exist", filepath.Base(path))
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package web
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/zeebo/errs"
+)
+
+// Networks is a list of IP networks.
+type Networks []*net.IPNet
+
+// String formats the networks as CIDRs.
+func (networks Networks) String() string {
+	cidrs := make([]string, 0, len(networks))
+	for _, network := range networks {
+		cidrs = append(cidrs, network.String())
+	}
+	return strings.Join(cidrs, ",")
+}
+
+// Set implements pflag.Value by parsing a comma separated list of CIDRs.
+// A single IP address is treated as a network containing only that address.
+func (networks *Networks) Set(value string) error {
+	var toSet Networks
+	for _, cidr := range strings.Split(value, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return errs.New("invalid IP address %q", cidr)
+			}
+			if ip4 := ip.To4(); ip4 != nil {
+				ip = ip4
+			}
+			toSet = append(toSet, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return errs.New("invalid CIDR %q: %v", cidr, err)
+		}
+		toSet = append(toSet, network)
+	}
+
+	*networks = toSet
+	return nil
+}
+
+// Type returns the type of the pflag.Value.
+func (networks Networks) Type() string {
+	return "networks"
+}
+
+// Contains returns whether ip is in any of the networks.
+func (networks Networks) Contains(ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetClientIP gets the IP address of the client which sent the request.
+//
+// The X-Forwarded-For and X-Real-IP headers are only used when the request
+// comes from one of the trusted proxies, otherwise anyone could choose the
+// address they are seen as by setting the headers. X-Forwarded-For is read
+// from the right, skipping trusted proxies, since the addresses to the left
+// of the ones added by the trusted proxies are set by the client.
+func GetClientIP(r *http.Request, trustedProxies Networks) (string, error) {
+	peer, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return "", err
+	}
+
+	if !trustedProxies.Contains(net.ParseIP(peer)) {
+		return peer, nil
+	}
+
+	if forwardedIPs := r.Header.Get("X-Forwarded-For"); forwardedIPs != "" {
+		ips := strings.Split(forwardedIPs, ",")
+		for i := len(ips) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(ips[i]))
+			if ip == nil {
+				// the proxies don't add invalid addresses, so the rest of the
+				// header can't be trusted.
+				break
+			}
+			if i == 0 || !trustedProxies.Contains(ip) {
+				return ip.String(), nil
+			}
+		}
+	}
+
+	if realIP := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); realIP != nil {
+		return realIP.String(), nil
+	}
+
+	return peer, nil
+}
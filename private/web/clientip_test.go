@@ -0,0 +1,116 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package web_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/private/web"
+)
+
+func TestNetworks(t *testing.T) {
+	var networks web.Networks
+	require.NoError(t, networks.Set("10.0.0.0/8, 192.168.1.1,fd00::/8"))
+	require.Equal(t, "10.0.0.0/8,192.168.1.1/32,fd00::/8", networks.String())
+
+	require.True(t, networks.Contains(parseIP(t, "10.1.2.3")))
+	require.True(t, networks.Contains(parseIP(t, "192.168.1.1")))
+	require.True(t, networks.Contains(parseIP(t, "fd00::1")))
+	require.False(t, networks.Contains(parseIP(t, "192.168.1.2")))
+	require.False(t, networks.Contains(parseIP(t, "11.0.0.1")))
+
+	require.Error(t, networks.Set("10.0.0.0/33"))
+	require.Error(t, networks.Set("not-an-ip"))
+
+	require.NoError(t, networks.Set(""))
+	require.Empty(t, networks)
+}
+
+func TestGetClientIP(t *testing.T) {
+	var trusted web.Networks
+	require.NoError(t, trusted.Set("10.0.0.0/8"))
+
+	for _, tt := range []struct {
+		name          string
+		remoteAddress string
+		forwardedFor  string
+		realIP        string
+		expected      string
+	}{
+		{
+			name:          "untrusted peer without headers",
+			remoteAddress: "1.2.3.4:5000",
+			expected:      "1.2.3.4",
+		},
+		{
+			name:          "untrusted peer with spoofed headers",
+			remoteAddress: "1.2.3.4:5000",
+			forwardedFor:  "5.6.7.8",
+			realIP:        "5.6.7.8",
+			expected:      "1.2.3.4",
+		},
+		{
+			name:          "trusted peer without headers",
+			remoteAddress: "10.0.0.1:5000",
+			expected:      "10.0.0.1",
+		},
+		{
+			name:          "trusted peer with forwarded for",
+			remoteAddress: "10.0.0.1:5000",
+			forwardedFor:  "1.2.3.4",
+			expected:      "1.2.3.4",
+		},
+		{
+			name:          "trusted peer with real ip",
+			remoteAddress: "10.0.0.1:5000",
+			realIP:        "1.2.3.4",
+			expected:      "1.2.3.4",
+		},
+		{
+			name:          "trusted peer with chained proxies",
+			remoteAddress: "10.0.0.1:5000",
+			forwardedFor:  "1.2.3.4, 10.0.0.2",
+			expected:      "1.2.3.4",
+		},
+		{
+			name:          "trusted peer with spoofed forwarded for",
+			remoteAddress: "10.0.0.1:5000",
+			forwardedFor:  "5.6.7.8, 1.2.3.4",
+			expected:      "1.2.3.4",
+		},
+		{
+			name:          "trusted peer with invalid forwarded for",
+			remoteAddress: "10.0.0.1:5000",
+			forwardedFor:  "garbage",
+			expected:      "10.0.0.1",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddress
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			ip, err := web.GetClientIP(req, trusted)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, ip)
+		})
+	}
+}
+
+func parseIP(t *testing.T, s string) net.IP {
+	ip := net.ParseIP(s)
+	require.NotNil(t, ip, s)
+	return ip
+}
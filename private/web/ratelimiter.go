@@ -19,6 +19,8 @@ type RateLimiterConfig struct {
 	Duration  time.Duration `help:"the rate at which request are allowed" default:"5m"`
 	Burst     int           `help:"number of events before the limit kicks in" default:"5" testDefault:"3"`
 	NumLimits int           `help:"number of clients whose rate limits we store" default:"1000" testDefault:"10"`
+
+	TrustedProxies Networks `help:"comma separated networks of proxies trusted to set the client IP in the X-Forwarded-For and X-Real-IP headers. When empty the headers are ignored, so behind a load balancer it must be set, otherwise all clients share the limit of the load balancer address" default:""`
 }
 
 // RateLimiter imposes a rate limit per key.
@@ -35,9 +37,12 @@ type userLimit struct {
 	lastSeen time.Time
 }
 
-// NewIPRateLimiter constructs a RateLimiter that limits based on IP address.
+// NewIPRateLimiter constructs a RateLimiter that limits based on the client IP address.
+// The client IP is taken from the request headers only for requests sent by the trusted proxies.
 func NewIPRateLimiter(config RateLimiterConfig) *RateLimiter {
-	return NewRateLimiter(config, GetRequestIP)
+	return NewRateLimiter(config, func(r *http.Request) (string, error) {
+		return GetClientIP(r, config.TrustedProxies)
+	})
 }
 
 // NewRateLimiter constructs a RateLimiter.
@@ -77,13 +82,12 @@ func (rl *RateLimiter) cleanupLimiters() {
 // Limit applies per-key rate limiting as an HTTP Handler.
 func (rl *RateLimiter) Limit(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		key, err := rl.keyFunc(r)
+		allowed, err := rl.Allow(r)
 		if err != nil {
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
 		}
-		limit := rl.getUserLimit(key)
-		if !limit.Allow() {
+		if !allowed {
 			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
 			return
 		}
@@ -91,7 +95,19 @@ func (rl *RateLimiter) Limit(next http.Handler) http.Handler {
 	})
 }
 
+// Allow returns whether the request is within the rate limit of its key.
+func (rl *RateLimiter) Allow(r *http.Request) (bool, error) {
+	key, err := rl.keyFunc(r)
+	if err != nil {
+		return false, err
+	}
+	return rl.getUserLimit(key).Allow(), nil
+}
+
 // GetRequestIP gets the original IP address of the request by handling the request headers.
+//
+// Deprecated: the headers are taken from any request, so clients can choose
+// the address they are seen as. Use GetClientIP instead.
 func GetRequestIP(r *http.Request) (ip string, err error) {
 	realIP := r.Header.Get("X-REAL-IP")
 	if realIP != "" {
@@ -68,3 +68,41 @@ func testWithAddress(ctx context.Context, t *testing.T, remoteAddress string, bu
 	handler.ServeHTTP(rr, req)
 	assert.Equal(t, rr.Code, http.StatusTooManyRequests, remoteAddress)
 }
+
+func TestIPRateLimiterTrustedProxies(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	config := web.RateLimiterConfig{}
+	cfgstruct.Bind(&pflag.FlagSet{}, &config, cfgstruct.UseDevDefaults())
+	require.NoError(t, config.TrustedProxies.Set("10.0.0.1"))
+	rateLimiter := web.NewIPRateLimiter(config)
+
+	handler := rateLimiter.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	request := func(remoteAddress, forwardedFor string) int {
+		req, err := http.NewRequestWithContext(ctx, "GET", "", nil)
+		require.NoError(t, err)
+		req.RemoteAddr = remoteAddress
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// clients behind the trusted proxy are limited separately.
+	for x := 0; x < rateLimiter.Burst(); x++ {
+		require.Equal(t, http.StatusOK, request("10.0.0.1:5000", "1.1.1.1"))
+	}
+	require.Equal(t, http.StatusTooManyRequests, request("10.0.0.1:5000", "1.1.1.1"))
+	require.Equal(t, http.StatusOK, request("10.0.0.1:5000", "2.2.2.2"))
+
+	// untrusted peers can't avoid the limit by changing the header.
+	for x := 0; x < rateLimiter.Burst(); x++ {
+		require.Equal(t, http.StatusOK, request("3.3.3.3:5000", "4.4.4.4"))
+	}
+	require.Equal(t, http.StatusTooManyRequests, request("3.3.3.3:5000", "5.5.5.5"))
+}
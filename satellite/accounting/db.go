@@ -77,6 +77,23 @@ type ProjectUsage struct {
 	Before time.Time `json:"before"`
 }
 
+// UsagePoint is a single timestamped value of a usage series.
+type UsagePoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     int64     `json:"value"`
+}
+
+// ProjectUsageSeries contains the storage and egress of a project split into
+// consecutive intervals. Storage points are the bytes stored at the last tally
+// of each interval, egress points are the bytes downloaded during the interval.
+type ProjectUsageSeries struct {
+	Storage []UsagePoint `json:"storage"`
+	Egress  []UsagePoint `json:"egress"`
+
+	Since  time.Time `json:"since"`
+	Before time.Time `json:"before"`
+}
+
 // ProjectLimits contains the storage and bandwidth limits.
 type ProjectLimits struct {
 	Usage     *int64
@@ -200,6 +217,8 @@ type ProjectAccounting interface {
 	GetProjectLimits(ctx context.Context, projectID uuid.UUID) (ProjectLimits, error)
 	// GetProjectTotal returns project usage summary for specified period of time.
 	GetProjectTotal(ctx context.Context, projectID uuid.UUID, since, before time.Time) (*ProjectUsage, error)
+	// GetProjectUsageSeries returns project storage and egress for every interval of the specified period of time.
+	GetProjectUsageSeries(ctx context.Context, projectID uuid.UUID, since, before time.Time, interval time.Duration) (*ProjectUsageSeries, error)
 	// GetBucketUsageRollups returns usage rollup per each bucket for specified period of time.
 	GetBucketUsageRollups(ctx context.Context, projectID uuid.UUID, since, before time.Time) ([]BucketUsageRollup, error)
 	// GetBucketTotals returns per bucket usage summary for specified period of time.
@@ -184,6 +184,13 @@ func (usage *Service) GetProjectBandwidthLimit(ctx context.Context, projectID uu
 	return usage.projectLimitCache.GetProjectBandwidthLimit(ctx, projectID)
 }
 
+// GetProjectLimits returns the current storage and bandwidth limits of the project.
+// The limits are read from the database, bypassing the project limit cache.
+func (usage *Service) GetProjectLimits(ctx context.Context, projectID uuid.UUID) (_ ProjectLimits, err error) {
+	defer mon.Task()(&ctx, projectID)(&err)
+	return usage.projectLimitCache.GetProjectLimits(ctx, projectID)
+}
+
 // UpdateProjectLimits sets new value for project's bandwidth and storage limit.
 func (usage *Service) UpdateProjectLimits(ctx context.Context, projectID uuid.UUID, limit memory.Size) (err error) {
 	defer mon.Task()(&ctx, projectID)(&err)
@@ -208,7 +208,14 @@ func (service *Service) Tally(ctx context.Context) (err error) {
 		monAccounting.IntVal("total_objects").Observe(total.ObjectCount) //mon:locked
 		monAccounting.IntVal("total_segments").Observe(total.Segments()) //mon:locked
 		monAccounting.IntVal("total_bytes").Observe(total.Bytes())       //mon:locked
+
+		for _, project := range collector.Projects() {
+			monAccounting.IntVal("project_objects").Observe(project.Objects)
+			monAccounting.IntVal("project_segments").Observe(project.Segments)
+			monAccounting.IntVal("project_bytes").Observe(project.Bytes)
+		}
 	}
+	mon.IntVal("no_metadata_object_count").Observe(collector.NoMetadataObjects)
 
 	// return errors if something went wrong.
 	return errAtRest
@@ -219,6 +226,9 @@ type BucketTallyCollector struct {
 	Now    time.Time
 	Log    *zap.Logger
 	Bucket map[metabase.BucketLocation]*accounting.BucketTally
+	// number of objects without any encrypted metadata, which indicates a
+	// faulty upload
+	NoMetadataObjects int64
 
 	metabase *metabase.DB
 	config   Config
@@ -246,7 +256,7 @@ func (observer *BucketTallyCollector) Run(ctx context.Context) (err error) {
 		return err
 	}
 
-	return observer.metabase.IterateLoopObjects(ctx, metabase.IterateLoopObjects{
+	count, err := observer.metabase.IterateLoopObjects(ctx, metabase.IterateLoopObjects{
 		BatchSize:          observer.config.ListLimit,
 		AsOfSystemTime:     startingTime,
 		AsOfSystemInterval: observer.config.AsOfSystemInterval,
@@ -260,6 +270,8 @@ func (observer *BucketTallyCollector) Run(ctx context.Context) (err error) {
 		}
 		return nil
 	})
+	observer.Log.Debug("collected bucket tallies", zap.Int64("objects", count), zap.Error(err))
+	return err
 }
 
 // ensureBucket returns bucket corresponding to the passed in path.
@@ -283,6 +295,10 @@ func (observer *BucketTallyCollector) object(ctx context.Context, object metabas
 		return nil
 	}
 
+	if object.EncryptedMetadataSize == 0 {
+		observer.NoMetadataObjects++
+	}
+
 	bucket := observer.ensureBucket(ctx, object.ObjectStream.Location())
 	bucket.TotalSegments += int64(object.SegmentCount)
 	bucket.TotalBytes += object.TotalEncryptedSize
@@ -292,6 +308,28 @@ func (observer *BucketTallyCollector) object(ctx context.Context, object metabas
 	return nil
 }
 
+// ProjectCounts contains the counts of a single project.
+type ProjectCounts struct {
+	Objects  int64
+	Segments int64
+	// encrypted size
+	Bytes int64
+}
+
+// Projects returns the object, segment and byte counts per project, summed
+// from the collected bucket tallies.
+func (observer *BucketTallyCollector) Projects() map[uuid.UUID]ProjectCounts {
+	projects := make(map[uuid.UUID]ProjectCounts)
+	for _, bucket := range observer.Bucket {
+		counts := projects[bucket.ProjectID]
+		counts.Objects += bucket.ObjectCount
+		counts.Segments += bucket.TotalSegments
+		counts.Bytes += bucket.TotalBytes
+		projects[bucket.ProjectID] = counts
+	}
+	return projects
+}
+
 func projectTotalsFromBuckets(buckets map[metabase.BucketLocation]*accounting.BucketTally) map[uuid.UUID]int64 {
 	projectTallyTotals := make(map[uuid.UUID]int64)
 	for _, bucket := range buckets {
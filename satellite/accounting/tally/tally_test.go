@@ -21,6 +21,8 @@ import (
 	"storj.io/storj/satellite/accounting"
 	"storj.io/storj/satellite/accounting/tally"
 	"storj.io/storj/satellite/metabase"
+	"storj.io/storj/satellite/metabase/metabasetest"
+	"storj.io/uplink"
 )
 
 func TestDeleteTalliesBefore(t *testing.T) {
@@ -197,6 +199,88 @@ func TestTallyIgnoresExpiredPointers(t *testing.T) {
 	})
 }
 
+func TestTallyNoMetadataObjects(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		ul := planet.Uplinks[0]
+
+		// uplink always stores encrypted stream information as metadata, even
+		// when there is no custom metadata.
+		err := ul.Upload(ctx, satellite, "bucket", "without-custom-metadata", testrand.Bytes(memory.KiB))
+		require.NoError(t, err)
+
+		project, err := ul.OpenProject(ctx, satellite)
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+
+		upload, err := project.UploadObject(ctx, "bucket", "with-custom-metadata", nil)
+		require.NoError(t, err)
+		_, err = upload.Write(testrand.Bytes(memory.KiB))
+		require.NoError(t, err)
+		require.NoError(t, upload.SetCustomMetadata(ctx, uplink.CustomMetadata{"key": "value"}))
+		require.NoError(t, upload.Commit())
+
+		// objects committed without any metadata at all indicate a faulty upload.
+		for i := 0; i < 2; i++ {
+			obj := metabasetest.RandObjectStream()
+			obj.ProjectID = ul.Projects[0].ID
+			metabasetest.CreateObject(ctx, t, satellite.Metainfo.Metabase, obj, 1)
+		}
+
+		collector := tally.NewBucketTallyCollector(satellite.Log.Named("bucket tally"), time.Now(), satellite.Metainfo.Metabase, satellite.Config.Tally)
+		err = collector.Run(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, collector.NoMetadataObjects)
+	})
+}
+
+func TestTallyProjects(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 2,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		inlineUplink, remoteUplink := planet.Uplinks[0], planet.Uplinks[1]
+
+		segmentSize := 8 * memory.KiB
+
+		// upload 2 inline files to two buckets of the first project
+		for i := 0; i < 2; i++ {
+			testData := testrand.Bytes(segmentSize / 8)
+			err := inlineUplink.Upload(ctx, satellite, fmt.Sprintf("bucket%d", i), "some/inline/path", testData)
+			require.NoError(t, err)
+		}
+
+		// upload 3 remote files with 1 segment to the second project
+		for i := 0; i < 3; i++ {
+			testData := testrand.Bytes(segmentSize)
+			err := remoteUplink.Upload(ctx, satellite, "testbucket", fmt.Sprintf("some/remote/path/%d", i), testData)
+			require.NoError(t, err)
+		}
+
+		collector := tally.NewBucketTallyCollector(satellite.Log.Named("bucket tally"), time.Now(), satellite.Metainfo.Metabase, satellite.Config.Tally)
+		err := collector.Run(ctx)
+		require.NoError(t, err)
+
+		projects := collector.Projects()
+		require.Len(t, projects, 2)
+
+		inlineProject := projects[inlineUplink.Projects[0].ID]
+		require.EqualValues(t, 2, inlineProject.Objects)
+		require.EqualValues(t, 2, inlineProject.Segments)
+		require.NotZero(t, inlineProject.Bytes)
+
+		remoteProject := projects[remoteUplink.Projects[0].ID]
+		require.EqualValues(t, 3, remoteProject.Objects)
+		require.EqualValues(t, 3, remoteProject.Segments)
+		require.Equal(t, collector.Bucket[metabase.BucketLocation{
+			ProjectID:  remoteUplink.Projects[0].ID,
+			BucketName: "testbucket",
+		}].TotalBytes, remoteProject.Bytes)
+	})
+}
+
 func TestTallyLiveAccounting(t *testing.T) {
 	testplanet.Run(t, testplanet.Config{
 		SatelliteCount: 1, StorageNodeCount: 6, UplinkCount: 1,
@@ -14,14 +14,22 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"storj.io/common/identity"
+	"storj.io/common/peertls/extensions"
+	"storj.io/common/peertls/tlsopts"
+	"storj.io/common/rpc"
+	"storj.io/common/signing"
 	"storj.io/common/storj"
 	"storj.io/private/debug"
 	"storj.io/private/version"
 	"storj.io/storj/private/lifecycle"
 	"storj.io/storj/private/version/checker"
 	"storj.io/storj/satellite/admin"
+	"storj.io/storj/satellite/metabase"
+	"storj.io/storj/satellite/orders"
+	"storj.io/storj/satellite/overlay"
 	"storj.io/storj/satellite/payments"
 	"storj.io/storj/satellite/payments/stripecoinpayments"
+	"storj.io/storj/satellite/repair/repairer"
 )
 
 // Admin is the satellite core process that runs chores.
@@ -36,6 +44,8 @@ type Admin struct {
 	Servers  *lifecycle.Group
 	Services *lifecycle.Group
 
+	Dialer rpc.Dialer
+
 	Debug struct {
 		Listener net.Listener
 		Server   *debug.Server
@@ -46,6 +56,12 @@ type Admin struct {
 		Service *checker.Service
 	}
 
+	Overlay *overlay.Service
+	Orders  struct {
+		Service *orders.Service
+	}
+	ObjectVerifier *repairer.ObjectVerifier
+
 	Payments struct {
 		Accounts payments.Accounts
 		Service  *stripecoinpayments.Service
@@ -60,6 +76,7 @@ type Admin struct {
 
 // NewAdmin creates a new satellite admin peer.
 func NewAdmin(log *zap.Logger, full *identity.FullIdentity, db DB,
+	metabaseDB *metabase.DB, revocationDB extensions.RevocationDB,
 	versionInfo version.Info, config *Config, atomicLogLevel *zap.AtomicLevel) (*Admin, error) {
 	peer := &Admin{
 		Log:      log,
@@ -107,6 +124,54 @@ func NewAdmin(log *zap.Logger, full *identity.FullIdentity, db DB,
 		})
 	}
 
+	{ // setup dialer
+		sc := config.Server
+
+		tlsOptions, err := tlsopts.NewOptions(peer.Identity, sc.Config, revocationDB)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
+
+		peer.Dialer = rpc.NewDefaultDialer(tlsOptions)
+	}
+
+	{ // setup overlay
+		var err error
+		peer.Overlay, err = overlay.NewService(log.Named("overlay"), peer.DB.OverlayCache(), config.Overlay)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
+		peer.Services.Add(lifecycle.Item{
+			Name:  "overlay",
+			Close: peer.Overlay.Close,
+		})
+	}
+
+	{ // setup orders
+		var err error
+		peer.Orders.Service, err = orders.NewService(
+			log.Named("orders"),
+			signing.SignerFromFullIdentity(peer.Identity),
+			peer.Overlay,
+			peer.DB.Orders(),
+			peer.DB.Buckets(),
+			config.Orders,
+		)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
+	}
+
+	{ // setup object verifier
+		ec := repairer.NewECRepairer(
+			log.Named("ec-repair"),
+			peer.Dialer,
+			signing.SigneeFromPeerIdentity(peer.Identity.PeerIdentity()),
+			config.Repairer,
+		)
+		peer.ObjectVerifier = repairer.NewObjectVerifier(log.Named("object-verifier"), metabaseDB, peer.Orders.Service, ec)
+	}
+
 	{ // setup payments
 		pc := config.Payments
 
@@ -156,7 +221,7 @@ func NewAdmin(log *zap.Logger, full *identity.FullIdentity, db DB,
 		adminConfig := config.Admin
 		adminConfig.AuthorizationToken = config.Console.AuthToken
 
-		peer.Admin.Server = admin.NewServer(log.Named("admin"), peer.Admin.Listener, peer.DB, peer.Payments.Accounts, adminConfig)
+		peer.Admin.Server = admin.NewServer(log.Named("admin"), peer.Admin.Listener, peer.DB, peer.Payments.Accounts, peer.ObjectVerifier, adminConfig)
 		peer.Servers.Add(lifecycle.Item{
 			Name:  "admin",
 			Run:   peer.Admin.Server.Run,
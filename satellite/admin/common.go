@@ -5,9 +5,15 @@ package admin
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/zeebo/errs"
+
+	"storj.io/common/memory"
 )
 
 // Error is default error class for admin package.
@@ -30,3 +36,53 @@ func httpJSONError(w http.ResponseWriter, error, detail string, statusCode int)
 	w.Header().Set("Content-Type", "application/json")
 	_, _ = w.Write(byt) // any error here entitles a client side disconnect or similar, which we do not care about.
 }
+
+// sizeUnits are the units of the suffixes accepted by parseSize.
+var sizeUnits = map[string]memory.Size{
+	"B":   memory.B,
+	"KB":  memory.KB,
+	"KIB": memory.KiB,
+	"MB":  memory.MB,
+	"MIB": memory.MiB,
+	"GB":  memory.GB,
+	"GIB": memory.GiB,
+	"TB":  memory.TB,
+	"TIB": memory.TiB,
+	"PB":  memory.PB,
+	"PIB": memory.PiB,
+	"EB":  memory.EB,
+	"EIB": memory.EiB,
+}
+
+// parseSize parses a human-readable size, e.g. "1TiB", "1.5 GB" or "100".
+// Both IEC and SI suffixes are accepted case-insensitively, the trailing B
+// can be omitted and a number without a suffix is in bytes.
+func parseSize(s string) (memory.Size, error) {
+	s = strings.TrimSpace(s)
+
+	number := strings.TrimSpace(strings.TrimRightFunc(s, unicode.IsLetter))
+	suffix := strings.ToUpper(strings.TrimLeftFunc(s[len(number):], unicode.IsSpace))
+	if !strings.HasSuffix(suffix, "B") {
+		suffix += "B"
+	}
+
+	unit, ok := sizeUnits[suffix]
+	if !ok {
+		return 0, Error.New("invalid size %q: unknown unit", s)
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, Error.New("invalid size %q: not a number", s)
+	}
+	if value < 0 {
+		return 0, Error.New("invalid size %q: size can't be negative", s)
+	}
+
+	bytes := value * unit.Float64()
+	if bytes >= math.MaxInt64 {
+		return 0, Error.New("invalid size %q: size is too large", s)
+	}
+
+	return memory.Size(bytes), nil
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+)
+
+func TestParseSize(t *testing.T) {
+	for _, test := range []struct {
+		input    string
+		expected memory.Size
+	}{
+		{"0", 0},
+		{"100", 100 * memory.B},
+		{"100B", 100 * memory.B},
+		{"1KB", memory.KB},
+		{"1KiB", memory.KiB},
+		{"1.5MB", 3 * memory.MB / 2},
+		{"1MiB", memory.MiB},
+		{"1GB", memory.GB},
+		{"2GiB", 2 * memory.GiB},
+		{"1TB", memory.TB},
+		{"1TiB", memory.TiB},
+		{"1PB", memory.PB},
+		{"1PiB", memory.PiB},
+		{"1EB", memory.EB},
+		{"1 GB", memory.GB},
+		{" 25 gb ", 25 * memory.GB},
+		{"1tib", memory.TiB},
+		{"1G", memory.GB},
+		{"1Gi", memory.GiB},
+	} {
+		size, err := parseSize(test.input)
+		require.NoError(t, err, test.input)
+		require.Equal(t, test.expected, size, test.input)
+	}
+
+	for _, input := range []string{
+		"",
+		"GB",
+		"-1",
+		"-1GB",
+		"abc",
+		"1XB",
+		"1GBB",
+		"1.2.3GB",
+		"NaN",
+		"InfB",
+		"10EiB",
+		"1e308",
+	} {
+		_, err := parseSize(input)
+		require.Error(t, err, input)
+	}
+}
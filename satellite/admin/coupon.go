@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -147,3 +148,115 @@ func (server *Server) deleteCoupon(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func (server *Server) revokeCoupon(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	vars := mux.Vars(r)
+	userEmail, ok := vars["useremail"]
+	if !ok {
+		httpJSONError(w, "user-email missing",
+			"", http.StatusBadRequest)
+		return
+	}
+
+	couponID, err := uuid.FromString(vars["couponid"])
+	if err != nil {
+		httpJSONError(w, "invalid couponid",
+			err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, err := server.db.Console().Users().GetByEmail(ctx, userEmail)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpJSONError(w, fmt.Sprintf("user with email %q not found", userEmail),
+			"", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		httpJSONError(w, "failed to get user",
+			err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = server.payments.Coupons().Revoke(ctx, user.ID, couponID)
+	if payments.ErrCouponNotFound.Has(err) {
+		httpJSONError(w, fmt.Sprintf("coupon with id %q of user %q not found", couponID, userEmail),
+			"", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		httpJSONError(w, "failed to revoke coupon",
+			err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (server *Server) couponUsage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	query := r.URL.Query()
+
+	since, err := time.Parse(time.RFC3339, query.Get("since"))
+	if err != nil {
+		httpJSONError(w, "invalid since",
+			err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	before, err := time.Parse(time.RFC3339, query.Get("before"))
+	if err != nil {
+		httpJSONError(w, "invalid before",
+			err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !since.Before(before) {
+		httpJSONError(w, "since must be earlier than before",
+			"", http.StatusBadRequest)
+		return
+	}
+
+	summary, err := server.db.StripeCoinPayments().Coupons().UsageSummary(ctx, since, before)
+	if err != nil {
+		httpJSONError(w, "failed to get coupon usage",
+			err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type typeUsage struct {
+		Type    payments.CouponType `json:"type"`
+		Granted int64               `json:"granted"`
+		Used    int64               `json:"used"`
+	}
+	var output struct {
+		Since   time.Time   `json:"since"`
+		Before  time.Time   `json:"before"`
+		Granted int64       `json:"granted"`
+		Used    int64       `json:"used"`
+		Types   []typeUsage `json:"types"`
+	}
+
+	output.Since = since
+	output.Before = before
+	output.Types = []typeUsage{}
+	for _, usage := range summary {
+		output.Granted += usage.Granted
+		output.Used += usage.Used
+		output.Types = append(output.Types, typeUsage{
+			Type:    usage.Type,
+			Granted: usage.Granted,
+			Used:    usage.Used,
+		})
+	}
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		httpJSONError(w, "json encoding failed",
+			err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data) // nothing to do with the error response, probably the client requesting disappeared
+}
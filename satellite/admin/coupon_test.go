@@ -8,8 +8,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -19,6 +21,7 @@ import (
 	"storj.io/storj/private/testplanet"
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/payments"
+	"storj.io/storj/satellite/payments/stripecoinpayments"
 )
 
 func TestAddCoupon(t *testing.T) {
@@ -171,3 +174,160 @@ func TestCouponDelete(t *testing.T) {
 		require.Len(t, coupons, 1)
 	})
 }
+
+func TestCouponUsage(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Admin.Address = "127.0.0.1:0"
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		address := planet.Satellites[0].Admin.Admin.Listener.Addr()
+		couponsDB := planet.Satellites[0].DB.StripeCoinPayments().Coupons()
+		user, err := planet.Satellites[0].DB.Console().Users().GetByEmail(ctx, planet.Uplinks[0].Projects[0].Owner.Email)
+		require.NoError(t, err)
+
+		type typeUsage struct {
+			Type    payments.CouponType `json:"type"`
+			Granted int64               `json:"granted"`
+			Used    int64               `json:"used"`
+		}
+		type usage struct {
+			Granted int64       `json:"granted"`
+			Used    int64       `json:"used"`
+			Types   []typeUsage `json:"types"`
+		}
+
+		getUsage := func(since, before time.Time) usage {
+			link := fmt.Sprintf("http://%s/api/coupons/usage?since=%s&before=%s", address,
+				url.QueryEscape(since.Format(time.RFC3339)), url.QueryEscape(before.Format(time.RFC3339)))
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", planet.Satellites[0].Config.Console.AuthToken)
+
+			response, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			responseBody, err := ioutil.ReadAll(response.Body)
+			require.NoError(t, err)
+			require.NoError(t, response.Body.Close())
+			require.Equal(t, http.StatusOK, response.StatusCode, string(responseBody))
+
+			var output usage
+			require.NoError(t, json.Unmarshal(responseBody, &output))
+			return output
+		}
+
+		now := time.Now()
+		since, before := now.Add(-time.Hour), now.Add(time.Hour)
+
+		// each created user has a coupon already
+		baseline := getUsage(since, before)
+
+		var couponIDs []uuid.UUID
+		for _, amount := range []int64{3000, 2000} {
+			duration := 2
+			coupon, err := couponsDB.Insert(ctx, payments.CouponOld{
+				UserID:      user.ID,
+				Amount:      amount,
+				Duration:    &duration,
+				Description: "testcoupon",
+				Type:        payments.CouponTypePromotional,
+				Status:      payments.CouponActive,
+			})
+			require.NoError(t, err)
+			couponIDs = append(couponIDs, coupon.ID)
+		}
+
+		for _, couponUsage := range []stripecoinpayments.CouponUsage{
+			{CouponID: couponIDs[0], Amount: 500, Period: now},
+			{CouponID: couponIDs[1], Amount: 300, Period: now},
+			// charged for a period outside of the window
+			{CouponID: couponIDs[0], Amount: 700, Period: now.Add(-48 * time.Hour)},
+		} {
+			require.NoError(t, couponsDB.AddUsage(ctx, couponUsage))
+		}
+
+		output := getUsage(since, before)
+		require.Equal(t, baseline.Granted+5000, output.Granted)
+		require.Equal(t, baseline.Used+800, output.Used)
+		require.Len(t, output.Types, 1)
+		require.Equal(t, payments.CouponTypePromotional, output.Types[0].Type)
+		require.Equal(t, output.Granted, output.Types[0].Granted)
+		require.Equal(t, output.Used, output.Types[0].Used)
+
+		// only the usage is within a window before the coupons were created
+		output = getUsage(now.Add(-72*time.Hour), now.Add(-24*time.Hour))
+		require.Equal(t, usage{
+			Used:  700,
+			Types: []typeUsage{{Type: payments.CouponTypePromotional, Used: 700}},
+		}, output)
+
+		t.Run("invalid window", func(t *testing.T) {
+			link := fmt.Sprintf("http://%s/api/coupons/usage?since=%s", address, url.QueryEscape(now.Format(time.RFC3339)))
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", planet.Satellites[0].Config.Console.AuthToken)
+
+			response, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, response.Body.Close())
+			require.Equal(t, http.StatusBadRequest, response.StatusCode)
+		})
+	})
+}
+
+func TestCouponRevoke(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      2,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Admin.Address = "127.0.0.1:0"
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		address := planet.Satellites[0].Admin.Admin.Listener.Addr()
+		couponsDB := planet.Satellites[0].DB.StripeCoinPayments().Coupons()
+		owner := planet.Uplinks[0].Projects[0].Owner
+		other := planet.Uplinks[1].Projects[0].Owner
+
+		duration := 2
+		coupon, err := couponsDB.Insert(ctx, payments.CouponOld{
+			UserID:      owner.ID,
+			Amount:      3000,
+			Duration:    &duration,
+			Description: "testcoupon-alice",
+			Status:      payments.CouponActive,
+		})
+		require.NoError(t, err)
+
+		revoke := func(email string) int {
+			link := fmt.Sprintf("http://%s/api/users/%s/coupons/%s/revoke", address, email, coupon.ID)
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, link, nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", planet.Satellites[0].Config.Console.AuthToken)
+
+			response, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, response.Body.Close())
+			return response.StatusCode
+		}
+
+		require.Equal(t, http.StatusNotFound, revoke(other.Email))
+		got, err := couponsDB.Get(ctx, coupon.ID)
+		require.NoError(t, err)
+		require.Equal(t, payments.CouponActive, got.Status)
+
+		require.Equal(t, http.StatusOK, revoke(owner.Email))
+		got, err = couponsDB.Get(ctx, coupon.ID)
+		require.NoError(t, err)
+		require.Equal(t, payments.CouponExpired, got.Status)
+
+		require.Equal(t, http.StatusOK, revoke(owner.Email))
+	})
+}
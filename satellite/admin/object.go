@@ -0,0 +1,118 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"storj.io/common/storj"
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/metabase"
+)
+
+func (server *Server) verifyObject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	vars := mux.Vars(r)
+	projectUUIDString, ok := vars["project"]
+	if !ok {
+		httpJSONError(w, "project-uuid missing",
+			"", http.StatusBadRequest)
+		return
+	}
+
+	projectUUID, err := uuid.FromString(projectUUIDString)
+	if err != nil {
+		httpJSONError(w, "invalid project-uuid",
+			err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	bucket := vars["bucket"]
+	if bucket == "" {
+		httpJSONError(w, "bucket name missing",
+			"", http.StatusBadRequest)
+		return
+	}
+
+	key := vars["key"]
+	if key == "" {
+		httpJSONError(w, "object key missing",
+			"", http.StatusBadRequest)
+		return
+	}
+
+	verification, err := server.objects.VerifyObject(ctx, metabase.ObjectLocation{
+		ProjectID:  projectUUID,
+		BucketName: bucket,
+		ObjectKey:  metabase.ObjectKey(key),
+	})
+	if err != nil {
+		if storj.ErrObjectNotFound.Has(err) {
+			httpJSONError(w, "object does not exist",
+				err.Error(), http.StatusNotFound)
+			return
+		}
+		httpJSONError(w, "unable to verify object",
+			err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type pieceOutput struct {
+		Number   int          `json:"number"`
+		NodeID   storj.NodeID `json:"nodeId"`
+		Verified bool         `json:"verified"`
+		Error    string       `json:"error,omitempty"`
+	}
+	type segmentOutput struct {
+		Position        uint64        `json:"position"`
+		Inline          bool          `json:"inline"`
+		Pieces          []pieceOutput `json:"pieces"`
+		Reconstructable bool          `json:"reconstructable"`
+		Error           string        `json:"error,omitempty"`
+	}
+	var output struct {
+		Segments        []segmentOutput `json:"segments"`
+		Reconstructable bool            `json:"reconstructable"`
+	}
+
+	output.Reconstructable = verification.Reconstructable
+	output.Segments = []segmentOutput{}
+	for _, segment := range verification.Segments {
+		segmentOut := segmentOutput{
+			Position:        segment.Position.Encode(),
+			Inline:          segment.Inline,
+			Pieces:          []pieceOutput{},
+			Reconstructable: segment.Reconstructable,
+		}
+		if segment.Err != nil {
+			segmentOut.Error = segment.Err.Error()
+		}
+		for _, piece := range segment.Pieces {
+			pieceOut := pieceOutput{
+				Number:   piece.Number,
+				NodeID:   piece.NodeID,
+				Verified: piece.Err == nil,
+			}
+			if piece.Err != nil {
+				pieceOut.Error = piece.Err.Error()
+			}
+			segmentOut.Pieces = append(segmentOut.Pieces, pieceOut)
+		}
+		output.Segments = append(output.Segments, segmentOut)
+	}
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		httpJSONError(w, "json encoding failed",
+			err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data) // nothing to do with the error response, probably the client requesting disappeared
+}
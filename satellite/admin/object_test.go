@@ -0,0 +1,144 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package admin_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite"
+	"storj.io/storj/storage"
+)
+
+func TestVerifyObject(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				testplanet.ReconfigureRS(2, 3, 4, 4)(log, index, config)
+				config.Admin.Address = "127.0.0.1:0"
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		address := sat.Admin.Admin.Listener.Addr()
+		projectID := planet.Uplinks[0].Projects[0].ID
+
+		require.NoError(t, planet.Uplinks[0].Upload(ctx, sat, "testbucket", "object", testrand.Bytes(10*memory.KiB)))
+
+		objects, err := sat.Metainfo.Metabase.TestingAllObjects(ctx)
+		require.NoError(t, err)
+		require.Len(t, objects, 1)
+		segments, err := sat.Metainfo.Metabase.TestingAllSegments(ctx)
+		require.NoError(t, err)
+		require.Len(t, segments, 1)
+		require.False(t, segments[0].Inline())
+
+		link := "http://" + address.String() + "/api/objects/" + projectID.String() + "/testbucket/" + url.PathEscape(string(objects[0].ObjectKey)) + "/verify"
+
+		type verification struct {
+			Segments []struct {
+				Pieces []struct {
+					Number   int          `json:"number"`
+					NodeID   storj.NodeID `json:"nodeId"`
+					Verified bool         `json:"verified"`
+					Error    string       `json:"error"`
+				} `json:"pieces"`
+				Reconstructable bool `json:"reconstructable"`
+			} `json:"segments"`
+			Reconstructable bool `json:"reconstructable"`
+		}
+		verify := func() verification {
+			body := assertReq(ctx, t, link, http.MethodGet, "", http.StatusOK, "", sat.Config.Console.AuthToken)
+
+			var output verification
+			require.NoError(t, json.Unmarshal(body, &output))
+			require.Len(t, output.Segments, 1)
+			return output
+		}
+
+		t.Run("unauthorized", func(t *testing.T) {
+			assertReq(ctx, t, link, http.MethodGet, "", http.StatusForbidden, "", "invalid-token")
+		})
+
+		t.Run("missing object", func(t *testing.T) {
+			missing := "http://" + address.String() + "/api/objects/" + projectID.String() + "/testbucket/missing/verify"
+			assertReq(ctx, t, missing, http.MethodGet, "", http.StatusNotFound, "", sat.Config.Console.AuthToken)
+		})
+
+		t.Run("healthy", func(t *testing.T) {
+			output := verify()
+			require.True(t, output.Reconstructable)
+			require.True(t, output.Segments[0].Reconstructable)
+			require.Len(t, output.Segments[0].Pieces, 2)
+			for _, piece := range output.Segments[0].Pieces {
+				require.True(t, piece.Verified, piece.Error)
+			}
+		})
+
+		t.Run("corrupted piece", func(t *testing.T) {
+			// the pieces are tried in order, so the one with the lowest number
+			// is always verified.
+			corrupted := segments[0].Pieces[0]
+			for _, piece := range segments[0].Pieces {
+				if piece.Number < corrupted.Number {
+					corrupted = piece
+				}
+			}
+
+			node := planet.FindNode(corrupted.StorageNode)
+			require.NotNil(t, node)
+
+			blobRef := storage.BlobRef{
+				Namespace: sat.ID().Bytes(),
+				Key:       segments[0].RootPieceID.Derive(corrupted.StorageNode, int32(corrupted.Number)).Bytes(),
+			}
+
+			reader, err := node.Storage2.BlobsCache.Open(ctx, blobRef)
+			require.NoError(t, err)
+			pieceData, err := ioutil.ReadAll(reader)
+			require.NoError(t, err)
+			require.NoError(t, reader.Close())
+			require.NoError(t, node.Storage2.BlobsCache.Delete(ctx, blobRef))
+
+			// corrupt the piece data, not the piece header.
+			pieceData[len(pieceData)-1]++
+			writer, err := node.Storage2.BlobsCache.Create(ctx, blobRef, int64(len(pieceData)))
+			require.NoError(t, err)
+			_, err = writer.Write(pieceData)
+			require.NoError(t, err)
+			require.NoError(t, writer.Commit(ctx))
+
+			output := verify()
+			require.True(t, output.Reconstructable)
+
+			var failed, verified int
+			for _, piece := range output.Segments[0].Pieces {
+				if piece.Verified {
+					verified++
+					continue
+				}
+				failed++
+				require.Equal(t, int(corrupted.Number), piece.Number)
+				require.Equal(t, corrupted.StorageNode, piece.NodeID)
+				require.NotEmpty(t, piece.Error)
+			}
+			require.Equal(t, 1, failed)
+			require.Equal(t, 2, verified)
+		})
+	})
+}
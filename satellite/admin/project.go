@@ -175,10 +175,10 @@ func (server *Server) putProjectLimit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var arguments struct {
-		Usage     *memory.Size `schema:"usage"`
-		Bandwidth *memory.Size `schema:"bandwidth"`
-		Rate      *int         `schema:"rate"`
-		Buckets   *int         `schema:"buckets"`
+		Usage     *string `schema:"usage"`
+		Bandwidth *string `schema:"bandwidth"`
+		Rate      *int    `schema:"rate"`
+		Buckets   *int    `schema:"buckets"`
 	}
 
 	if err := r.ParseForm(); err != nil {
@@ -196,13 +196,14 @@ func (server *Server) putProjectLimit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if arguments.Usage != nil {
-		if *arguments.Usage < 0 {
-			httpJSONError(w, "negative usage",
-				fmt.Sprintf("%v", arguments.Usage), http.StatusBadRequest)
+		usage, err := parseSize(*arguments.Usage)
+		if err != nil {
+			httpJSONError(w, "invalid usage",
+				err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		err = server.db.ProjectAccounting().UpdateProjectUsageLimit(ctx, projectUUID, *arguments.Usage)
+		err = server.db.ProjectAccounting().UpdateProjectUsageLimit(ctx, projectUUID, usage)
 		if err != nil {
 			httpJSONError(w, "failed to update usage",
 				err.Error(), http.StatusInternalServerError)
@@ -211,13 +212,14 @@ func (server *Server) putProjectLimit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if arguments.Bandwidth != nil {
-		if *arguments.Bandwidth < 0 {
-			httpJSONError(w, "negative bandwidth",
-				fmt.Sprintf("%v", arguments.Usage), http.StatusBadRequest)
+		bandwidth, err := parseSize(*arguments.Bandwidth)
+		if err != nil {
+			httpJSONError(w, "invalid bandwidth",
+				err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		err = server.db.ProjectAccounting().UpdateProjectBandwidthLimit(ctx, projectUUID, *arguments.Bandwidth)
+		err = server.db.ProjectAccounting().UpdateProjectBandwidthLimit(ctx, projectUUID, bandwidth)
 		if err != nil {
 			httpJSONError(w, "failed to update bandwidth",
 				err.Error(), http.StatusInternalServerError)
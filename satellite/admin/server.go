@@ -22,6 +22,7 @@ import (
 	"storj.io/storj/satellite/metainfo"
 	"storj.io/storj/satellite/payments"
 	"storj.io/storj/satellite/payments/stripecoinpayments"
+	"storj.io/storj/satellite/repair/repairer"
 )
 
 // Config defines configuration for debug server.
@@ -53,12 +54,13 @@ type Server struct {
 
 	db       DB
 	payments payments.Accounts
+	objects  *repairer.ObjectVerifier
 
 	nowFn func() time.Time
 }
 
 // NewServer returns a new administration Server.
-func NewServer(log *zap.Logger, listener net.Listener, db DB, accounts payments.Accounts, config Config) *Server {
+func NewServer(log *zap.Logger, listener net.Listener, db DB, accounts payments.Accounts, objects *repairer.ObjectVerifier, config Config) *Server {
 	server := &Server{
 		log: log,
 
@@ -67,6 +69,7 @@ func NewServer(log *zap.Logger, listener net.Listener, db DB, accounts payments.
 
 		db:       db,
 		payments: accounts,
+		objects:  objects,
 
 		nowFn: time.Now,
 	}
@@ -81,7 +84,9 @@ func NewServer(log *zap.Logger, listener net.Listener, db DB, accounts payments.
 	server.mux.HandleFunc("/api/users/{useremail}", server.updateUser).Methods("PUT")
 	server.mux.HandleFunc("/api/users/{useremail}", server.userInfo).Methods("GET")
 	server.mux.HandleFunc("/api/users/{useremail}", server.deleteUser).Methods("DELETE")
+	server.mux.HandleFunc("/api/users/{useremail}/coupons/{couponid}/revoke", server.revokeCoupon).Methods("POST")
 	server.mux.HandleFunc("/api/coupons", server.addCoupon).Methods("POST")
+	server.mux.HandleFunc("/api/coupons/usage", server.couponUsage).Methods("GET")
 	server.mux.HandleFunc("/api/coupons/{couponid}", server.couponInfo).Methods("GET")
 	server.mux.HandleFunc("/api/coupons/{couponid}", server.deleteCoupon).Methods("DELETE")
 	server.mux.HandleFunc("/api/projects", server.addProject).Methods("POST")
@@ -95,6 +100,7 @@ func NewServer(log *zap.Logger, listener net.Listener, db DB, accounts payments.
 	server.mux.HandleFunc("/api/projects/{project}/apikeys", server.addAPIKey).Methods("POST")
 	server.mux.HandleFunc("/api/projects/{project}/apikeys/{name}", server.deleteAPIKeyByName).Methods("DELETE")
 	server.mux.HandleFunc("/api/apikeys/{apikey}", server.deleteAPIKey).Methods("DELETE")
+	server.mux.HandleFunc("/api/objects/{project}/{bucket}/{key:.+}/verify", server.verifyObject).Methods("GET")
 
 	return server
 }
@@ -105,6 +105,7 @@ type API struct {
 		Metabase      *metabase.DB
 		Service       *metainfo.Service
 		PieceDeletion *piecedeletion.Service
+		APIKeyUsage   *metainfo.APIKeyUsageCache
 		Endpoint      *metainfo.Endpoint
 	}
 
@@ -398,6 +399,19 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 			Close: peer.Metainfo.PieceDeletion.Close,
 		})
 
+		peer.Metainfo.APIKeyUsage = metainfo.NewAPIKeyUsageCache(
+			peer.Log.Named("metainfo:apikeyusage"),
+			peer.DB.Console().APIKeys(),
+			config.Metainfo.APIKeyUsage,
+		)
+		peer.Services.Add(lifecycle.Item{
+			Name:  "metainfo:apikeyusage",
+			Run:   peer.Metainfo.APIKeyUsage.Run,
+			Close: peer.Metainfo.APIKeyUsage.Close,
+		})
+		peer.Debug.Server.Panel.Add(
+			debug.Cycle("Metainfo API Key Usage", peer.Metainfo.APIKeyUsage.Loop))
+
 		peer.Metainfo.Endpoint, err = metainfo.NewEndpoint(
 			peer.Log.Named("metainfo:endpoint"),
 			peer.Metainfo.Service,
@@ -408,6 +422,7 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 			peer.Marketing.PartnersService,
 			peer.DB.PeerIdentities(),
 			peer.DB.Console().APIKeys(),
+			peer.Metainfo.APIKeyUsage,
 			peer.Accounting.ProjectUsage,
 			peer.DB.Console().Projects(),
 			signing.SignerFromFullIdentity(peer.Identity),
@@ -576,11 +591,13 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 			peer.DB.ProjectAccounting(),
 			peer.Accounting.ProjectUsage,
 			peer.DB.Buckets(),
+			peer.Metainfo.Service,
 			peer.Marketing.PartnersService,
 			peer.Payments.Accounts,
 			peer.Analytics.Service,
 			consoleConfig.Config,
 			config.Payments.MinCoinPayment,
+			config.Metainfo.ProjectLimits.MaxBuckets,
 		)
 		if err != nil {
 			return nil, errs.Combine(err, peer.Close())
@@ -590,6 +607,7 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 			StorageTBPrice: config.Payments.StorageTBPrice,
 			EgressTBPrice:  config.Payments.EgressTBPrice,
 			ObjectPrice:    config.Payments.ObjectPrice,
+			Regions:        config.Payments.RegionalPricing,
 		}
 
 		peer.Console.Endpoint = consoleweb.NewServer(
@@ -601,8 +619,10 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 			peer.Analytics.Service,
 			peer.Console.Listener,
 			config.Payments.StripeCoinPayments.StripePublicKey,
+			peer.Payments.Service.Webhooks(peer.Console.Service.Payments()),
 			pricing,
 			peer.URL(),
+			versionInfo,
 		)
 
 		peer.Servers.Add(lifecycle.Item{
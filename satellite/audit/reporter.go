@@ -33,6 +33,54 @@ type Report struct {
 	Unknown       storj.NodeIDList
 }
 
+// Add appends the audit results of other to the report. Fields that are empty
+// in both reports are left nil.
+func (report *Report) Add(other Report) {
+	report.Successes = append(report.Successes, other.Successes...)
+	report.Fails = append(report.Fails, other.Fails...)
+	report.Offlines = append(report.Offlines, other.Offlines...)
+	report.PendingAudits = append(report.PendingAudits, other.PendingAudits...)
+	report.Unknown = append(report.Unknown, other.Unknown...)
+}
+
+// Normalize removes duplicate node IDs from the report. A node that appears in
+// more than one category is kept only in the one with the highest priority,
+// which is, from highest to lowest: Fails, PendingAudits, Unknown, Offlines and
+// Successes. The first pending audit of a node is kept.
+//
+// The lists are rebuilt, so the slices of the original report aren't modified.
+func (report *Report) Normalize() {
+	seen := make(map[storj.NodeID]struct{})
+
+	dedup := func(nodeIDs storj.NodeIDList) storj.NodeIDList {
+		var result storj.NodeIDList
+		for _, nodeID := range nodeIDs {
+			if _, ok := seen[nodeID]; ok {
+				continue
+			}
+			seen[nodeID] = struct{}{}
+			result = append(result, nodeID)
+		}
+		return result
+	}
+
+	report.Fails = dedup(report.Fails)
+
+	var pendingAudits []*PendingAudit
+	for _, pending := range report.PendingAudits {
+		if _, ok := seen[pending.NodeID]; ok {
+			continue
+		}
+		seen[pending.NodeID] = struct{}{}
+		pendingAudits = append(pendingAudits, pending)
+	}
+	report.PendingAudits = pendingAudits
+
+	report.Unknown = dedup(report.Unknown)
+	report.Offlines = dedup(report.Offlines)
+	report.Successes = dedup(report.Successes)
+}
+
 // NewReporter instantiates a reporter.
 func NewReporter(log *zap.Logger, reputations *reputation.Service, containment Containment, maxRetries int, maxReverifyCount int32) *Reporter {
 	return &Reporter{
@@ -49,6 +97,8 @@ func NewReporter(log *zap.Logger, reputations *reputation.Service, containment C
 func (reporter *Reporter) RecordAudits(ctx context.Context, req Report) (_ Report, err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	req.Normalize()
+
 	successes := req.Successes
 	fails := req.Fails
 	unknowns := req.Unknown
@@ -272,3 +272,71 @@ func TestReportOfflineAudits(t *testing.T) {
 		require.EqualValues(t, 0, info.UnknownAuditReputationBeta)
 	})
 }
+
+func TestReportAdd(t *testing.T) {
+	nodes := make(storj.NodeIDList, 6)
+	for i := range nodes {
+		nodes[i] = testrand.NodeID()
+	}
+	pending := &audit.PendingAudit{NodeID: nodes[5]}
+
+	var report audit.Report
+	report.Add(audit.Report{})
+	require.Nil(t, report.Successes)
+	require.Nil(t, report.Fails)
+	require.Nil(t, report.Offlines)
+	require.Nil(t, report.PendingAudits)
+	require.Nil(t, report.Unknown)
+
+	report.Add(audit.Report{
+		Successes: storj.NodeIDList{nodes[0]},
+		Fails:     storj.NodeIDList{nodes[1]},
+	})
+	report.Add(audit.Report{
+		Successes:     storj.NodeIDList{nodes[2]},
+		Offlines:      storj.NodeIDList{nodes[3]},
+		PendingAudits: []*audit.PendingAudit{pending},
+	})
+	report.Add(audit.Report{
+		Unknown: storj.NodeIDList{nodes[4]},
+	})
+
+	require.Equal(t, storj.NodeIDList{nodes[0], nodes[2]}, report.Successes)
+	require.Equal(t, storj.NodeIDList{nodes[1]}, report.Fails)
+	require.Equal(t, storj.NodeIDList{nodes[3]}, report.Offlines)
+	require.Equal(t, []*audit.PendingAudit{pending}, report.PendingAudits)
+	require.Equal(t, storj.NodeIDList{nodes[4]}, report.Unknown)
+}
+
+func TestReportNormalize(t *testing.T) {
+	nodes := make(storj.NodeIDList, 5)
+	for i := range nodes {
+		nodes[i] = testrand.NodeID()
+	}
+	pending := &audit.PendingAudit{NodeID: nodes[1]}
+	duplicatePending := &audit.PendingAudit{NodeID: nodes[1]}
+	failedPending := &audit.PendingAudit{NodeID: nodes[0]}
+
+	successes := storj.NodeIDList{nodes[0], nodes[1], nodes[2], nodes[3], nodes[4], nodes[4]}
+	report := audit.Report{
+		Successes:     successes,
+		Fails:         storj.NodeIDList{nodes[0], nodes[0]},
+		Offlines:      storj.NodeIDList{nodes[1], nodes[2], nodes[3]},
+		PendingAudits: []*audit.PendingAudit{failedPending, pending, duplicatePending},
+		Unknown:       storj.NodeIDList{nodes[2], nodes[1]},
+	}
+	report.Normalize()
+
+	require.Equal(t, storj.NodeIDList{nodes[0]}, report.Fails)
+	require.Equal(t, []*audit.PendingAudit{pending}, report.PendingAudits)
+	require.Equal(t, storj.NodeIDList{nodes[2]}, report.Unknown)
+	require.Equal(t, storj.NodeIDList{nodes[3]}, report.Offlines)
+	require.Equal(t, storj.NodeIDList{nodes[4]}, report.Successes)
+
+	// the slices of the original report are left untouched
+	require.Equal(t, storj.NodeIDList{nodes[0], nodes[1], nodes[2], nodes[3], nodes[4], nodes[4]}, successes)
+
+	var empty audit.Report
+	empty.Normalize()
+	require.Equal(t, audit.Report{}, empty)
+}
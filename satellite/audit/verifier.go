@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/spacemonkeygo/monkit/v3"
@@ -402,6 +403,18 @@ func (verifier *Verifier) Reverify(ctx context.Context, segment Segment) (report
 	ch := make(chan result, len(pieces))
 	var containedInSegment int64
 
+	// pendingPiece is a pending audit which is reverified.
+	type pendingPiece struct {
+		pending *PendingAudit
+		segment metabase.Segment
+	}
+	var toReverify []pendingPiece
+	var auditPieces []orders.AuditPiece
+
+	// the segments of the pending audits are looked up concurrently.
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
 	for _, piece := range pieces {
 		pending, err := verifier.containment.Get(ctx, piece.StorageNode)
 		if err != nil {
@@ -423,7 +436,10 @@ func (verifier *Verifier) Reverify(ctx context.Context, segment Segment) (report
 
 		containedInSegment++
 
+		wg.Add(1)
 		go func(pending *PendingAudit) {
+			defer wg.Done()
+
 			pendingSegment, err := verifier.metabase.GetSegmentByPosition(ctx, metabase.GetSegmentByPosition{
 				StreamID: pending.StreamID,
 				Position: pending.Position,
@@ -463,29 +479,57 @@ func (verifier *Verifier) Reverify(ctx context.Context, segment Segment) (report
 				return
 			}
 
-			limit, piecePrivateKey, cachedIPAndPort, err := verifier.orders.CreateAuditOrderLimit(ctx, pending.NodeID, pieceNum, pending.PieceID, pending.ShareSize)
-			if err != nil {
-				if overlay.ErrNodeDisqualified.Has(err) {
-					ch <- result{nodeID: pending.NodeID, status: skipped, release: true}
-					verifier.log.Debug("Reverify: order limit not created (disqualified)", zap.Stringer("Node ID", pending.NodeID))
-					return
-				}
-				if overlay.ErrNodeFinishedGE.Has(err) {
-					ch <- result{nodeID: pending.NodeID, status: skipped, release: true}
-					verifier.log.Debug("Reverify: order limit not created (completed graceful exit)", zap.Stringer("Node ID", pending.NodeID))
-					return
-				}
-				if overlay.ErrNodeOffline.Has(err) {
-					ch <- result{nodeID: pending.NodeID, status: offline}
-					verifier.log.Debug("Reverify: order limit not created (offline)", zap.Stringer("Node ID", pending.NodeID))
-					return
-				}
-				ch <- result{nodeID: pending.NodeID, status: erred, err: err}
-				verifier.log.Debug("Reverify: error creating order limit", zap.Stringer("Node ID", pending.NodeID), zap.Error(err))
-				return
+			mu.Lock()
+			defer mu.Unlock()
+			toReverify = append(toReverify, pendingPiece{pending: pending, segment: pendingSegment})
+			auditPieces = append(auditPieces, orders.AuditPiece{
+				NodeID:      pending.NodeID,
+				RootPieceID: pending.PieceID,
+				PieceNum:    pieceNum,
+				ShareSize:   pending.ShareSize,
+			})
+		}(pending)
+	}
+	wg.Wait()
+
+	// the order limits of all pending audits are created at once.
+	limits, limitsErr := verifier.orders.CreateAuditPieceOrderLimits(ctx, auditPieces)
+	if limitsErr != nil {
+		verifier.log.Debug("Reverify: error creating order limits", zap.Error(limitsErr))
+		for _, piece := range toReverify {
+			ch <- result{nodeID: piece.pending.NodeID, status: erred, err: limitsErr}
+		}
+		limits = nil
+	}
+
+	for i, limit := range limits {
+		pending := toReverify[i].pending
+
+		if err := limit.Err; err != nil {
+			if overlay.ErrNodeDisqualified.Has(err) {
+				ch <- result{nodeID: pending.NodeID, status: skipped, release: true}
+				verifier.log.Debug("Reverify: order limit not created (disqualified)", zap.Stringer("Node ID", pending.NodeID))
+				continue
 			}
+			if overlay.ErrNodeFinishedGE.Has(err) {
+				ch <- result{nodeID: pending.NodeID, status: skipped, release: true}
+				verifier.log.Debug("Reverify: order limit not created (completed graceful exit)", zap.Stringer("Node ID", pending.NodeID))
+				continue
+			}
+			if overlay.ErrNodeOffline.Has(err) {
+				ch <- result{nodeID: pending.NodeID, status: offline}
+				verifier.log.Debug("Reverify: order limit not created (offline)", zap.Stringer("Node ID", pending.NodeID))
+				continue
+			}
+			ch <- result{nodeID: pending.NodeID, status: erred, err: err}
+			verifier.log.Debug("Reverify: error creating order limit", zap.Stringer("Node ID", pending.NodeID), zap.Error(err))
+			continue
+		}
 
-			share, err := verifier.GetShare(ctx, limit, piecePrivateKey, cachedIPAndPort, pending.StripeIndex, pending.ShareSize, int(pieceNum))
+		go func(pending *PendingAudit, pendingSegment metabase.Segment, limit orders.AuditPieceLimit) {
+			pieceNum := limit.Piece.PieceNum
+
+			share, err := verifier.GetShare(ctx, limit.Limit, limit.PrivateKey, limit.CachedIPAndPort, pending.StripeIndex, pending.ShareSize, int(pieceNum))
 
 			// check if the pending audit was deleted while downloading the share
 			_, getErr := verifier.containment.Get(ctx, pending.NodeID)
@@ -559,7 +603,7 @@ func (verifier *Verifier) Reverify(ctx context.Context, segment Segment) (report
 					zap.Binary("expected hash", pending.ExpectedShareHash), zap.Binary("downloaded hash", downloadedHash))
 				ch <- result{nodeID: pending.NodeID, status: failed, release: true}
 			}
-		}(pending)
+		}(pending, toReverify[i].segment, limit)
 	}
 
 	for range pieces {
@@ -26,6 +26,12 @@ type APIKeys interface {
 	Create(ctx context.Context, head []byte, info APIKeyInfo) (*APIKeyInfo, error)
 	// Update updates APIKeyInfo in store
 	Update(ctx context.Context, key APIKeyInfo) error
+	// UpdateSecret replaces the head and secret of the api key, so that the macaroons derived from the previous ones stop authorizing
+	UpdateSecret(ctx context.Context, id uuid.UUID, head, secret []byte) error
+	// UpdateUsage adds the requests to the daily request counts of the api keys and sets when they were last used
+	UpdateUsage(ctx context.Context, updates []APIKeyUsageUpdate) error
+	// GetUsage returns the number of requests the api key authorized per day, starting from the day of since
+	GetUsage(ctx context.Context, id uuid.UUID, since time.Time) ([]APIKeyDailyUsage, error)
 	// Delete deletes APIKeyInfo from store
 	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -38,6 +44,29 @@ type APIKeyInfo struct {
 	Name      string    `json:"name"`
 	Secret    []byte    `json:"-"`
 	CreatedAt time.Time `json:"createdAt"`
+
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
+
+// APIKeyUsage holds the number of requests an api key authorized per day.
+type APIKeyUsage struct {
+	APIKeyID uuid.UUID          `json:"apiKeyId"`
+	Days     []APIKeyDailyUsage `json:"days"`
+}
+
+// APIKeyDailyUsage holds the number of requests an api key authorized on a day.
+type APIKeyDailyUsage struct {
+	Day      time.Time `json:"day"`
+	Requests int64     `json:"requests"`
+}
+
+// APIKeyUsageUpdate holds the number of requests to add to the request count
+// of an api key on a day, and when the key was last used on that day.
+type APIKeyUsageUpdate struct {
+	APIKeyID   uuid.UUID
+	Day        time.Time
+	Requests   int64
+	LastUsedAt time.Time
 }
 
 // APIKeyCursor holds info for api keys cursor pagination.
@@ -6,11 +6,13 @@ package console_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"storj.io/common/macaroon"
 	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/satellitedb/satellitedbtest"
@@ -117,6 +119,92 @@ func TestApiKeysRepository(t *testing.T) {
 			assert.NoError(t, err)
 		})
 
+		t.Run("Usage success", func(t *testing.T) {
+			cursor := console.APIKeyCursor{
+				Page:   1,
+				Limit:  10,
+				Search: "",
+			}
+			page, err := apikeys.GetPagedByProjectID(ctx, project.ID, cursor)
+			assert.NoError(t, err)
+			keyID := page.APIKeys[0].ID
+
+			day := time.Date(2021, 9, 2, 0, 0, 0, 0, time.UTC)
+			assert.NoError(t, apikeys.UpdateUsage(ctx, []console.APIKeyUsageUpdate{
+				{APIKeyID: keyID, Day: day.AddDate(0, 0, -1), Requests: 1, LastUsedAt: day.Add(-time.Hour)},
+				{APIKeyID: keyID, Day: day, Requests: 1, LastUsedAt: day.Add(2 * time.Hour)},
+			}))
+			// the requests are added to the stored counts, an earlier use doesn't move the last use back.
+			assert.NoError(t, apikeys.UpdateUsage(ctx, []console.APIKeyUsageUpdate{
+				{APIKeyID: keyID, Day: day, Requests: 1, LastUsedAt: day.Add(time.Hour)},
+			}))
+
+			key, err := apikeys.Get(ctx, keyID)
+			assert.NoError(t, err)
+			if assert.NotNil(t, key.LastUsedAt) {
+				assert.True(t, key.LastUsedAt.Equal(day.Add(2*time.Hour)))
+			}
+
+			usage, err := apikeys.GetUsage(ctx, keyID, day.AddDate(0, 0, -1))
+			assert.NoError(t, err)
+			assert.Len(t, usage, 2)
+			assert.Equal(t, []console.APIKeyDailyUsage{
+				{Day: day.AddDate(0, 0, -1), Requests: 1},
+				{Day: day, Requests: 2},
+			}, usage)
+
+			usage, err = apikeys.GetUsage(ctx, keyID, day.Add(time.Hour))
+			assert.NoError(t, err)
+			assert.Equal(t, []console.APIKeyDailyUsage{
+				{Day: day, Requests: 2},
+			}, usage)
+
+			// the usage of deleted keys is skipped without failing the batch.
+			assert.NoError(t, apikeys.UpdateUsage(ctx, []console.APIKeyUsageUpdate{
+				{APIKeyID: testrand.UUID(), Day: day, Requests: 1, LastUsedAt: day},
+				{APIKeyID: keyID, Day: day, Requests: 1, LastUsedAt: day},
+			}))
+
+			usage, err = apikeys.GetUsage(ctx, keyID, day)
+			assert.NoError(t, err)
+			assert.Equal(t, []console.APIKeyDailyUsage{
+				{Day: day, Requests: 3},
+			}, usage)
+		})
+
+		t.Run("UpdateSecret success", func(t *testing.T) {
+			oldKey, err := macaroon.NewAPIKey([]byte("oldSecret"))
+			assert.NoError(t, err)
+
+			created, err := apikeys.Create(ctx, oldKey.Head(), console.APIKeyInfo{
+				Name:      "rotated key",
+				ProjectID: project.ID,
+				Secret:    []byte("oldSecret"),
+			})
+			assert.NoError(t, err)
+
+			// populate the cache with the previous head.
+			_, err = apikeys.GetByHead(ctx, oldKey.Head())
+			assert.NoError(t, err)
+
+			newKey, err := macaroon.NewAPIKey([]byte("newSecret"))
+			assert.NoError(t, err)
+
+			err = apikeys.UpdateSecret(ctx, created.ID, newKey.Head(), []byte("newSecret"))
+			assert.NoError(t, err)
+
+			_, err = apikeys.GetByHead(ctx, oldKey.Head())
+			assert.Error(t, err)
+
+			updated, err := apikeys.GetByHead(ctx, newKey.Head())
+			assert.NoError(t, err)
+			assert.Equal(t, created.ID, updated.ID)
+			assert.Equal(t, created.Name, updated.Name)
+			assert.Equal(t, []byte("newSecret"), updated.Secret)
+
+			assert.NoError(t, apikeys.Delete(ctx, created.ID))
+		})
+
 		t.Run("Delete success", func(t *testing.T) {
 			cursor := console.APIKeyCursor{
 				Page:   1,
@@ -41,6 +41,9 @@ const authKey key = 0
 // requestKey is context key for Requests.
 const requestKey key = 1
 
+// requestIDKey is context key for request IDs.
+const requestIDKey key = 2
+
 // ErrUnauthorized is error class for authorization related errors.
 var ErrUnauthorized = errs.Class("unauthorized")
 
@@ -31,3 +31,21 @@ type Buckets interface {
 	// CountBuckets returns the number of buckets a project currently has.
 	CountBuckets(ctx context.Context, projectID uuid.UUID) (int, error)
 }
+
+// BucketObjects is the interface for validating buckets and for inspecting
+// and removing the objects stored in a bucket.
+//
+// architecture: Service
+type BucketObjects interface {
+	// ValidateBucketName validates that the bucket name follows the bucket naming rules.
+	ValidateBucketName(bucketName []byte) error
+	// IsBucketEmpty returns whether the bucket has no objects.
+	IsBucketEmpty(ctx context.Context, projectID uuid.UUID, bucketName []byte) (bool, error)
+	// DeleteBucketObjects deletes all objects in the bucket and returns how
+	// many were deleted. Their pieces are left for garbage collection.
+	DeleteBucketObjects(ctx context.Context, projectID uuid.UUID, bucketName []byte) (deleted int64, err error)
+	// ListBucketObjects lists the objects and prefixes under the prefix, starting after the cursor.
+	ListBucketObjects(ctx context.Context, projectID uuid.UUID, bucketName []byte, prefix, cursor metabase.ObjectKey, limit int) (entries []metabase.ObjectEntry, more bool, err error)
+	// GetBucketObject returns the latest version of the object.
+	GetBucketObject(ctx context.Context, projectID uuid.UUID, bucketName []byte, key metabase.ObjectKey) (metabase.Object, error)
+}
@@ -18,6 +18,8 @@ type Claims struct {
 	ID         uuid.UUID `json:"id"`
 	Email      string    `json:"email,omitempty"`
 	Expiration time.Time `json:"expires,omitempty"`
+	Epoch      int64     `json:"epoch,omitempty"`
+	NewEmail   string    `json:"newEmail,omitempty"`
 }
 
 // JSON returns json representation of Claims.
@@ -4,8 +4,13 @@
 package consoleapi
 
 import (
+	"database/sql"
+	"encoding/json"
 	"net/http"
+	"net/url"
+	"strconv"
 
+	"github.com/gorilla/mux"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 
@@ -18,6 +23,9 @@ var (
 	ErrAPIKeysAPI = errs.Class("console api keys")
 )
 
+// apiKeysPageLimit is the default number of api keys returned per page.
+const apiKeysPageLimit = 50
+
 // APIKeys is an api controller that exposes all api keys related functionality.
 type APIKeys struct {
 	log     *zap.Logger
@@ -69,6 +77,232 @@ func (keys *APIKeys) DeleteByNameAndProjectID(w http.ResponseWriter, r *http.Req
 	}
 }
 
+// GetProjectAPIKeys returns a page of the api keys of a project, including when
+// they were created and last used.
+func (keys *APIKeys) GetProjectAPIKeys(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query()
+
+	projectID, err := uuid.FromString(query.Get("projectID"))
+	if err != nil {
+		keys.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	limit, err := parsePositiveUint(query, "limit", apiKeysPageLimit)
+	if err != nil {
+		keys.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	page, err := parsePositiveUint(query, "page", 1)
+	if err != nil {
+		keys.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	cursor := console.APIKeyCursor{
+		Search:         query.Get("search"),
+		Limit:          limit,
+		Page:           page,
+		Order:          console.CreationDate,
+		OrderDirection: console.Descending,
+	}
+
+	switch query.Get("order") {
+	case "":
+	case "name":
+		cursor.Order = console.KeyName
+	case "createdAt":
+		cursor.Order = console.CreationDate
+	default:
+		keys.serveJSONError(w, http.StatusBadRequest, errs.New("invalid order: %q", query.Get("order")))
+		return
+	}
+
+	switch query.Get("orderDirection") {
+	case "":
+	case "asc":
+		cursor.OrderDirection = console.Ascending
+	case "desc":
+		cursor.OrderDirection = console.Descending
+	default:
+		keys.serveJSONError(w, http.StatusBadRequest, errs.New("invalid order direction: %q", query.Get("orderDirection")))
+		return
+	}
+
+	keysPage, err := keys.service.GetAPIKeys(ctx, projectID, cursor)
+	if err != nil {
+		if console.ErrUnauthorized.Has(err) {
+			keys.serveJSONError(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		keys.serveJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	apiKeys := keysPage.APIKeys
+	if apiKeys == nil {
+		apiKeys = []console.APIKeyInfo{}
+	}
+
+	err = json.NewEncoder(w).Encode(struct {
+		APIKeys     []console.APIKeyInfo `json:"apiKeys"`
+		Search      string               `json:"search"`
+		Limit       uint                 `json:"limit"`
+		PageCount   uint                 `json:"pageCount"`
+		CurrentPage uint                 `json:"currentPage"`
+		TotalCount  uint64               `json:"totalCount"`
+	}{
+		APIKeys:     apiKeys,
+		Search:      keysPage.Search,
+		Limit:       keysPage.Limit,
+		PageCount:   keysPage.PageCount,
+		CurrentPage: keysPage.CurrentPage,
+		TotalCount:  keysPage.TotalCount,
+	})
+	if err != nil {
+		keys.log.Error("failed to write json api keys response", zap.Error(ErrAPIKeysAPI.Wrap(err)))
+	}
+}
+
+// DeleteByID deletes specific api key by it's ID.
+func (keys *APIKeys) DeleteByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	id, err := uuid.FromString(mux.Vars(r)["id"])
+	if err != nil {
+		keys.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = keys.service.DeleteAPIKeys(ctx, []uuid.UUID{id})
+	if err != nil {
+		if console.ErrUnauthorized.Has(err) {
+			keys.serveJSONError(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		if errs.Is(err, sql.ErrNoRows) {
+			keys.serveJSONError(w, http.StatusNotFound, err)
+			return
+		}
+
+		keys.serveJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+}
+
+// Rotate replaces the secret of the api key and returns the new serialized
+// key, which can't be retrieved again.
+func (keys *APIKeys) Rotate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := uuid.FromString(mux.Vars(r)["id"])
+	if err != nil {
+		keys.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	info, key, err := keys.service.RotateAPIKey(ctx, id)
+	if err != nil {
+		if console.ErrUnauthorized.Has(err) {
+			keys.serveJSONError(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		if errs.Is(err, sql.ErrNoRows) {
+			keys.serveJSONError(w, http.StatusNotFound, err)
+			return
+		}
+
+		keys.serveJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(struct {
+		Key     string             `json:"key"`
+		KeyInfo console.APIKeyInfo `json:"keyInfo"`
+	}{
+		Key:     key.Serialize(),
+		KeyInfo: *info,
+	})
+	if err != nil {
+		keys.log.Error("failed to write json rotated api key response", zap.Error(ErrAPIKeysAPI.Wrap(err)))
+	}
+}
+
+// GetUsage returns the number of requests the api key authorized on each of
+// the last days.
+func (keys *APIKeys) GetUsage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := uuid.FromString(mux.Vars(r)["id"])
+	if err != nil {
+		keys.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	days, err := parsePositiveUint(r.URL.Query(), "days", console.MaxAPIKeyUsageDays)
+	if err != nil {
+		keys.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	usage, err := keys.service.GetAPIKeyUsage(ctx, id, int(days))
+	if err != nil {
+		if console.ErrUnauthorized.Has(err) {
+			keys.serveJSONError(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		if errs.Is(err, sql.ErrNoRows) {
+			keys.serveJSONError(w, http.StatusNotFound, err)
+			return
+		}
+
+		keys.serveJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(usage)
+	if err != nil {
+		keys.log.Error("failed to write json api key usage response", zap.Error(ErrAPIKeysAPI.Wrap(err)))
+	}
+}
+
+// parsePositiveUint parses the named query parameter as a positive integer,
+// returning def when the parameter is missing.
+func parsePositiveUint(query url.Values, name string, def uint) (uint, error) {
+	value := query.Get(name)
+	if value == "" {
+		return def, nil
+	}
+
+	parsed, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || parsed == 0 {
+		return 0, errs.New("invalid %s: %q", name, value)
+	}
+
+	return uint(parsed), nil
+}
+
 // serveJSONError writes JSON error to response output stream.
 func (keys *APIKeys) serveJSONError(w http.ResponseWriter, status int, err error) {
 	serveJSONError(keys.log, w, status, err)
@@ -4,6 +4,7 @@
 package consoleapi_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
 	"time"
@@ -12,10 +13,14 @@ import (
 	"go.uber.org/zap"
 
 	"storj.io/common/macaroon"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
 	"storj.io/common/testcontext"
+	"storj.io/common/uuid"
 	"storj.io/storj/private/testplanet"
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/console"
+	"storj.io/uplink/private/metaclient"
 )
 
 func Test_DeleteAPIKeyByNameAndProjectID(t *testing.T) {
@@ -90,3 +95,299 @@ func Test_DeleteAPIKeyByNameAndProjectID(t *testing.T) {
 		}()
 	})
 }
+
+func TestAPIKeysListAndDeleteByID(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 2,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Console.RateLimit.Burst = 10
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		upl := planet.Uplinks[0]
+		project := upl.Projects[0]
+		login := upl.User[sat.ID()]
+
+		token, err := sat.API.Console.Service.Token(ctx, console.AuthUser{Email: login.Email, Password: login.Password})
+		require.NoError(t, err)
+
+		doRequest := func(method, urlSuffix string) *http.Response {
+			urlLink := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/api-keys" + urlSuffix
+
+			req, err := http.NewRequestWithContext(ctx, method, urlLink, nil)
+			require.NoError(t, err)
+
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   token,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			return result
+		}
+
+		type keysPage struct {
+			APIKeys    []console.APIKeyInfo `json:"apiKeys"`
+			TotalCount uint64               `json:"totalCount"`
+		}
+
+		listKeys := func() keysPage {
+			result := doRequest(http.MethodGet, "?projectID="+project.ID.String())
+			defer func() { require.NoError(t, result.Body.Close()) }()
+			require.Equal(t, http.StatusOK, result.StatusCode)
+
+			var page keysPage
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&page))
+			return page
+		}
+
+		authCtx, err := sat.AuthenticatedContext(ctx, project.Owner.ID)
+		require.NoError(t, err)
+		unused, _, err := sat.API.Console.Service.CreateAPIKey(authCtx, project.ID, "unused")
+		require.NoError(t, err)
+
+		beforeUse := time.Now().Add(-time.Second)
+		require.NoError(t, upl.CreateBucket(ctx, sat, "testbucket"))
+		sat.API.Metainfo.APIKeyUsage.Flush(ctx)
+
+		page := listKeys()
+		require.EqualValues(t, 2, page.TotalCount)
+		for _, key := range page.APIKeys {
+			switch key.Name {
+			case "root":
+				require.NotNil(t, key.LastUsedAt)
+				require.True(t, key.LastUsedAt.After(beforeUse))
+				require.False(t, key.CreatedAt.IsZero())
+			case unused.Name:
+				require.Nil(t, key.LastUsedAt)
+			default:
+				t.Fatalf("unexpected api key %q", key.Name)
+			}
+		}
+
+		// Expect only members of the project to be able to delete its keys.
+		otherProject := planet.Uplinks[1].Projects[0]
+		otherKeys, err := sat.DB.Console().APIKeys().GetPagedByProjectID(ctx, otherProject.ID, console.APIKeyCursor{Limit: 10, Page: 1})
+		require.NoError(t, err)
+		require.Len(t, otherKeys.APIKeys, 1)
+
+		result := doRequest(http.MethodDelete, "/"+otherKeys.APIKeys[0].ID.String())
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode)
+
+		result = doRequest(http.MethodDelete, "/"+unused.ID.String())
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		_, err = sat.DB.Console().APIKeys().Get(ctx, unused.ID)
+		require.Error(t, err)
+
+		page = listKeys()
+		require.EqualValues(t, 1, page.TotalCount)
+		require.Equal(t, "root", page.APIKeys[0].Name)
+
+		result = doRequest(http.MethodDelete, "/"+unused.ID.String())
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, http.StatusNotFound, result.StatusCode)
+	})
+}
+
+func TestAPIKeyUsage(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 2,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Console.RateLimit.Burst = 10
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		upl := planet.Uplinks[0]
+		project := upl.Projects[0]
+		login := upl.User[sat.ID()]
+
+		token, err := sat.API.Console.Service.Token(ctx, console.AuthUser{Email: login.Email, Password: login.Password})
+		require.NoError(t, err)
+
+		doRequest := func(urlSuffix string) *http.Response {
+			urlLink := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/api-keys" + urlSuffix
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlLink, nil)
+			require.NoError(t, err)
+
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   token,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			return result
+		}
+
+		getUsage := func(keyID uuid.UUID, query string) console.APIKeyUsage {
+			result := doRequest("/" + keyID.String() + "/usage" + query)
+			defer func() { require.NoError(t, result.Body.Close()) }()
+			require.Equal(t, http.StatusOK, result.StatusCode)
+
+			var usage console.APIKeyUsage
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&usage))
+			return usage
+		}
+
+		keys, err := sat.DB.Console().APIKeys().GetPagedByProjectID(ctx, project.ID, console.APIKeyCursor{Limit: 10, Page: 1})
+		require.NoError(t, err)
+		require.Len(t, keys.APIKeys, 1)
+		keyID := keys.APIKeys[0].ID
+
+		// the usage is flushed explicitly to make the test deterministic.
+		sat.API.Metainfo.APIKeyUsage.Loop.Pause()
+		sat.API.Metainfo.APIKeyUsage.Flush(ctx)
+
+		usage := getUsage(keyID, "")
+		require.Equal(t, keyID, usage.APIKeyID)
+		require.Len(t, usage.Days, console.MaxAPIKeyUsageDays)
+		requestsToday := usage.Days[len(usage.Days)-1].Requests
+
+		// every metainfo request authorized by the key is counted.
+		require.NoError(t, upl.CreateBucket(ctx, sat, "testbucket"))
+		for i := 0; i < 3; i++ {
+			_, err = upl.ListBuckets(ctx, sat)
+			require.NoError(t, err)
+		}
+
+		// the requests are only counted once the aggregated usage is flushed.
+		usage = getUsage(keyID, "?days=7")
+		require.Equal(t, requestsToday, usage.Days[len(usage.Days)-1].Requests)
+
+		sat.API.Metainfo.APIKeyUsage.Flush(ctx)
+
+		usage = getUsage(keyID, "?days=7")
+		require.Len(t, usage.Days, 7)
+		today := usage.Days[len(usage.Days)-1]
+		require.Equal(t, time.Now().UTC().Format("2006-01-02"), today.Day.Format("2006-01-02"))
+		require.GreaterOrEqual(t, today.Requests, requestsToday+4)
+		for _, day := range usage.Days[:len(usage.Days)-1] {
+			require.Zero(t, day.Requests)
+		}
+
+		// the lookback window is capped.
+		usage = getUsage(keyID, "?days=1000")
+		require.Len(t, usage.Days, console.MaxAPIKeyUsageDays)
+
+		// only members of the project can see the usage of its keys.
+		otherProject := planet.Uplinks[1].Projects[0]
+		otherKeys, err := sat.DB.Console().APIKeys().GetPagedByProjectID(ctx, otherProject.ID, console.APIKeyCursor{Limit: 10, Page: 1})
+		require.NoError(t, err)
+		require.Len(t, otherKeys.APIKeys, 1)
+
+		result := doRequest("/" + otherKeys.APIKeys[0].ID.String() + "/usage")
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode)
+
+		result = doRequest("/" + keyID.String() + "/usage?days=0")
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+	})
+}
+
+func TestAPIKeyRotate(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 2,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Console.RateLimit.Burst = 10
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		upl := planet.Uplinks[0]
+		project := upl.Projects[0]
+		login := upl.User[sat.ID()]
+
+		token, err := sat.API.Console.Service.Token(ctx, console.AuthUser{Email: login.Email, Password: login.Password})
+		require.NoError(t, err)
+
+		doRequest := func(urlSuffix string) *http.Response {
+			urlLink := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/api-keys" + urlSuffix
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, urlLink, nil)
+			require.NoError(t, err)
+
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   token,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			return result
+		}
+
+		listBuckets := func(key *macaroon.APIKey) error {
+			client, err := upl.DialMetainfo(ctx, sat, key)
+			require.NoError(t, err)
+			defer ctx.Check(client.Close)
+
+			_, err = client.ListBuckets(ctx, metaclient.ListBucketsParams{
+				ListOpts: storj.BucketListOptions{Direction: storj.Forward},
+			})
+			return err
+		}
+
+		oldKey := upl.APIKey[sat.ID()]
+		// a restricted key derived from the old secret.
+		oldRestricted, err := oldKey.Restrict(macaroon.Caveat{DisallowDeletes: true})
+		require.NoError(t, err)
+		require.NoError(t, listBuckets(oldKey))
+		require.NoError(t, listBuckets(oldRestricted))
+
+		keys, err := sat.DB.Console().APIKeys().GetPagedByProjectID(ctx, project.ID, console.APIKeyCursor{Limit: 10, Page: 1})
+		require.NoError(t, err)
+		require.Len(t, keys.APIKeys, 1)
+		keyInfo := keys.APIKeys[0]
+
+		result := doRequest("/" + keyInfo.ID.String() + "/rotate")
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		var rotated struct {
+			Key     string             `json:"key"`
+			KeyInfo console.APIKeyInfo `json:"keyInfo"`
+		}
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&rotated))
+		require.NoError(t, result.Body.Close())
+
+		require.Equal(t, keyInfo.ID, rotated.KeyInfo.ID)
+		require.Equal(t, keyInfo.Name, rotated.KeyInfo.Name)
+		require.Equal(t, keyInfo.ProjectID, rotated.KeyInfo.ProjectID)
+
+		newKey, err := macaroon.ParseAPIKey(rotated.Key)
+		require.NoError(t, err)
+		require.NoError(t, listBuckets(newKey))
+
+		require.Equal(t, rpcstatus.PermissionDenied, rpcstatus.Code(listBuckets(oldKey)))
+		require.Equal(t, rpcstatus.PermissionDenied, rpcstatus.Code(listBuckets(oldRestricted)))
+
+		// only members of the project can rotate its keys.
+		otherProject := planet.Uplinks[1].Projects[0]
+		otherKeys, err := sat.DB.Console().APIKeys().GetPagedByProjectID(ctx, otherProject.ID, console.APIKeyCursor{Limit: 10, Page: 1})
+		require.NoError(t, err)
+		require.Len(t, otherKeys.APIKeys, 1)
+
+		result = doRequest("/" + otherKeys.APIKeys[0].ID.String() + "/rotate")
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode)
+
+		// the key that couldn't be rotated still works.
+		require.NoError(t, planet.Uplinks[1].CreateBucket(ctx, sat, "stillworks"))
+	})
+}
@@ -4,6 +4,7 @@
 package consoleapi
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -51,10 +52,11 @@ type Auth struct {
 	mailService           *mailservice.Service
 	cookieAuth            *consolewebauth.CookieAuth
 	partners              *rewards.PartnersService
+	trustedProxies        web.Networks
 }
 
 // NewAuth is a constructor for api auth controller.
-func NewAuth(log *zap.Logger, service *console.Service, mailService *mailservice.Service, cookieAuth *consolewebauth.CookieAuth, partners *rewards.PartnersService, analytics *analytics.Service, externalAddress string, letUsKnowURL string, termsAndConditionsURL string, contactInfoURL string) *Auth {
+func NewAuth(log *zap.Logger, service *console.Service, mailService *mailservice.Service, cookieAuth *consolewebauth.CookieAuth, partners *rewards.PartnersService, analytics *analytics.Service, externalAddress string, letUsKnowURL string, termsAndConditionsURL string, contactInfoURL string, trustedProxies web.Networks) *Auth {
 	return &Auth{
 		log:                   log,
 		ExternalAddress:       externalAddress,
@@ -66,6 +68,7 @@ func NewAuth(log *zap.Logger, service *console.Service, mailService *mailservice
 		cookieAuth:            cookieAuth,
 		partners:              partners,
 		analytics:             analytics,
+		trustedProxies:        trustedProxies,
 	}
 }
 
@@ -111,6 +114,23 @@ func (a *Auth) Logout(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 }
 
+// LogoutAll invalidates all of the user's tokens and removes auth cookie.
+func (a *Auth) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	err = a.service.LogoutAll(ctx)
+	if err != nil {
+		a.serveJSONError(w, err)
+		return
+	}
+
+	a.cookieAuth.RemoveTokenCookie(w)
+
+	w.Header().Set("Content-Type", "application/json")
+}
+
 // Register creates new user, sends activation e-mail.
 func (a *Auth) Register(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -168,7 +188,7 @@ func (a *Auth) Register(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	ip, err := web.GetRequestIP(r)
+	ip, err := web.GetClientIP(r, a.trustedProxies)
 	if err != nil {
 		a.serveJSONError(w, err)
 		return
@@ -252,15 +272,25 @@ func loadSession(req *http.Request) string {
 	return sessionCookie.Value
 }
 
-// UpdateAccount updates user's full name and short name.
+// UpdateAccount updates user's full name, short name and professional information.
 func (a *Auth) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	var err error
 	defer mon.Task()(&ctx)(&err)
 
-	var updatedInfo struct {
-		FullName  string `json:"fullName"`
-		ShortName string `json:"shortName"`
+	auth, err := console.GetAuth(ctx)
+	if err != nil {
+		a.serveJSONError(w, err)
+		return
+	}
+
+	// professional information which isn't part of the request is kept.
+	updatedInfo := console.UserInfo{
+		IsProfessional:   auth.User.IsProfessional,
+		Position:         auth.User.Position,
+		CompanyName:      auth.User.CompanyName,
+		EmployeeCount:    auth.User.EmployeeCount,
+		HaveSalesContact: auth.User.HaveSalesContact,
 	}
 
 	err = json.NewDecoder(r.Body).Decode(&updatedInfo)
@@ -269,7 +299,7 @@ func (a *Auth) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = a.service.UpdateAccount(ctx, updatedInfo.FullName, updatedInfo.ShortName); err != nil {
+	if err = a.service.UpdateAccount(ctx, updatedInfo); err != nil {
 		a.serveJSONError(w, err)
 	}
 }
@@ -295,6 +325,7 @@ func (a *Auth) GetAccount(w http.ResponseWriter, r *http.Request) {
 		PaidTier             bool      `json:"paidTier"`
 		MFAEnabled           bool      `json:"isMFAEnabled"`
 		MFARecoveryCodeCount int       `json:"mfaRecoveryCodeCount"`
+		SessionExpiration    time.Time `json:"sessionExpiration"`
 	}
 
 	auth, err := console.GetAuth(ctx)
@@ -317,6 +348,7 @@ func (a *Auth) GetAccount(w http.ResponseWriter, r *http.Request) {
 	user.PaidTier = auth.User.PaidTier
 	user.MFAEnabled = auth.User.MFAEnabled
 	user.MFARecoveryCodeCount = len(auth.User.MFARecoveryCodes)
+	user.SessionExpiration = auth.Claims.Expiration
 
 	w.Header().Set("Content-Type", "application/json")
 	err = json.NewEncoder(w).Encode(&user)
@@ -326,13 +358,64 @@ func (a *Auth) GetAccount(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// DeleteAccount authorizes user and deletes account by password.
+// DeleteAccount authorizes user by password and schedules the account for deletion.
 func (a *Auth) DeleteAccount(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	defer mon.Task()(&ctx)(&errNotImplemented)
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	var request struct {
+		Password string `json:"password"`
+	}
+
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		a.serveJSONError(w, err)
+		return
+	}
+
+	deleteAt, err := a.service.RequestAccountDeletion(ctx, request.Password)
+	if err != nil {
+		// the projects or unpaid invoices blocking the deletion are shown to the user.
+		if console.ErrUsage.Has(err) {
+			serveCustomJSONError(a.log, w, http.StatusConflict, err, "The account can not be deleted yet: "+errs.Unwrap(err).Error())
+			return
+		}
+		a.serveJSONError(w, err)
+		return
+	}
+
+	auth, err := console.GetAuth(ctx)
+	if err != nil {
+		a.serveJSONError(w, err)
+		return
+	}
+
+	userName := auth.User.ShortName
+	if auth.User.ShortName == "" {
+		userName = auth.User.FullName
+	}
+
+	a.mailService.SendRenderedAsync(
+		ctx,
+		[]post.Address{{Address: auth.User.Email, Name: userName}},
+		&consoleql.AccountDeletionEmail{
+			Origin:         a.ExternalAddress,
+			UserName:       userName,
+			DeletionDate:   deleteAt.Format("January 2, 2006"),
+			ContactInfoURL: a.ContactInfoURL,
+		},
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
 
-	// We do not want to allow account deletion via API currently.
-	a.serveJSONError(w, errNotImplemented)
+	err = json.NewEncoder(w).Encode(struct {
+		DeleteAt time.Time `json:"deleteAt"`
+	}{deleteAt})
+	if err != nil {
+		a.log.Error("could not encode account deletion date", zap.Error(ErrAuthAPI.Wrap(err)))
+	}
 }
 
 // ChangeEmail auth user, changes users email for a new one.
@@ -351,11 +434,49 @@ func (a *Auth) ChangeEmail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = a.service.ChangeEmail(ctx, emailChange.NewEmail)
+	token, err := a.service.ChangeEmail(ctx, emailChange.NewEmail)
+	if err != nil {
+		a.serveJSONError(w, err)
+		return
+	}
+
+	auth, err := console.GetAuth(ctx)
+	if err != nil {
+		a.serveJSONError(w, err)
+		return
+	}
+
+	link := a.ExternalAddress + "api/v0/auth/account/confirm-email?token=" + token
+	userName := auth.User.ShortName
+	if auth.User.ShortName == "" {
+		userName = auth.User.FullName
+	}
+
+	a.mailService.SendRenderedAsync(
+		ctx,
+		[]post.Address{{Address: emailChange.NewEmail, Name: userName}},
+		&consoleql.EmailChangeEmail{
+			Origin:           a.ExternalAddress,
+			UserName:         userName,
+			ConfirmationLink: link,
+		},
+	)
+}
+
+// ConfirmEmailChange changes the email of the user to the new email the
+// confirmation token was sent to.
+func (a *Auth) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	err = a.service.ConfirmEmailChange(ctx, r.URL.Query().Get("token"))
 	if err != nil {
 		a.serveJSONError(w, err)
 		return
 	}
+
+	http.Redirect(w, r, a.ExternalAddress+"login", http.StatusTemporaryRedirect)
 }
 
 // ChangePassword auth user, changes users password for a new one.
@@ -375,11 +496,15 @@ func (a *Auth) ChangePassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = a.service.ChangePassword(ctx, passwordChange.CurrentPassword, passwordChange.NewPassword)
+	token, err := a.service.ChangePassword(ctx, passwordChange.CurrentPassword, passwordChange.NewPassword)
 	if err != nil {
 		a.serveJSONError(w, err)
 		return
 	}
+
+	// every other session has been logged out, so keep the current one alive
+	// with a freshly issued token.
+	a.cookieAuth.SetTokenCookie(w, token)
 }
 
 // ForgotPassword creates password-reset token and sends email to user.
@@ -396,15 +521,21 @@ func (a *Auth) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// unknown and rate limited accounts are answered the same way as any
+	// other, so the response can't be used to find out whether an account exists.
 	user, err := a.service.GetUserByEmail(ctx, email)
 	if err != nil {
-		a.serveJSONError(w, err)
+		if !errors.Is(err, sql.ErrNoRows) {
+			a.log.Error("could not look up user for password recovery", zap.Error(ErrAuthAPI.Wrap(err)))
+		}
 		return
 	}
 
 	recoveryToken, err := a.service.GeneratePasswordRecoveryToken(ctx, user.ID)
 	if err != nil {
-		a.serveJSONError(w, err)
+		if !console.ErrEmailRateLimit.Has(err) {
+			a.serveJSONError(w, err)
+		}
 		return
 	}
 
@@ -453,15 +584,21 @@ func (a *Auth) ResendEmail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// unknown and rate limited accounts are answered the same way as any
+	// other, so the response can't be used to find out whether an account exists.
 	user, err := a.service.GetUser(ctx, userID)
 	if err != nil {
-		a.serveJSONError(w, err)
+		if !errors.Is(err, sql.ErrNoRows) {
+			a.log.Error("could not look up user for account activation", zap.Error(ErrAuthAPI.Wrap(err)))
+		}
 		return
 	}
 
 	token, err := a.service.GenerateActivationToken(ctx, user.ID, user.Email)
 	if err != nil {
-		a.serveJSONError(w, err)
+		if !console.ErrEmailRateLimit.Has(err) {
+			a.serveJSONError(w, err)
+		}
 		return
 	}
 
@@ -572,6 +709,26 @@ func (a *Auth) GenerateMFARecoveryCodes(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// GetMFARecoveryCodeCount returns the number of unused MFA recovery codes for the user.
+func (a *Auth) GetMFARecoveryCodeCount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	count, err := a.service.GetMFARecoveryCodeCount(ctx)
+	if err != nil {
+		a.serveJSONError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(count)
+	if err != nil {
+		a.log.Error("could not encode MFA recovery code count", zap.Error(ErrAuthAPI.Wrap(err)))
+		return
+	}
+}
+
 // ResetPassword resets user's password using recovery token.
 func (a *Auth) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -603,11 +760,13 @@ func (a *Auth) serveJSONError(w http.ResponseWriter, err error) {
 // getStatusCode returns http.StatusCode depends on console error class.
 func (a *Auth) getStatusCode(err error) int {
 	switch {
+	case console.ErrMFARateLimit.Has(err), console.ErrEmailRateLimit.Has(err):
+		return http.StatusTooManyRequests
 	case console.ErrValidation.Has(err), console.ErrRecaptcha.Has(err):
 		return http.StatusBadRequest
-	case console.ErrUnauthorized.Has(err), console.ErrRecoveryToken.Has(err):
+	case console.ErrUnauthorized.Has(err), console.ErrRecoveryToken.Has(err), console.ErrTokenExpiration.Has(err):
 		return http.StatusUnauthorized
-	case console.ErrEmailUsed.Has(err), console.ErrMFAConflict.Has(err):
+	case console.ErrEmailUsed.Has(err), console.ErrMFAConflict.Has(err), console.ErrUsage.Has(err):
 		return http.StatusConflict
 	case errors.Is(err, errNotImplemented):
 		return http.StatusNotImplemented
@@ -635,6 +794,8 @@ func (a *Auth) getUserErrorMessage(err error) string {
 			return "The recovery token has expired"
 		}
 		return "The recovery token is invalid"
+	case console.ErrTokenExpiration.Has(err):
+		return "The token has expired or is no longer valid"
 	case console.ErrMFAMissing.Has(err):
 		return "A MFA passcode or recovery code is required"
 	case console.ErrMFAConflict.Has(err):
@@ -643,6 +804,10 @@ func (a *Auth) getUserErrorMessage(err error) string {
 		return "The MFA passcode is not valid or has expired"
 	case console.ErrMFARecoveryCode.Has(err):
 		return "The MFA recovery code is not valid or has been previously used"
+	case console.ErrMFARateLimit.Has(err):
+		return "Too many failed MFA passcode attempts, please try again later"
+	case console.ErrEmailRateLimit.Has(err):
+		return "Too many emails have been sent to this account, please try again later"
 	case errors.Is(err, errNotImplemented):
 		return "The server is incapable of fulfilling the request"
 	default:
@@ -5,31 +5,29 @@ package consoleapi_test
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"io"
 	"io/ioutil"
-	"math/rand"
 	"net/http"
-	"net/http/httptest"
-	"net/url"
-	"reflect"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
-	"testing/quick"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/stripe/stripe-go/v72"
 	"go.uber.org/zap"
 
 	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
 	"storj.io/common/uuid"
 	"storj.io/storj/private/testplanet"
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/console"
-	"storj.io/storj/satellite/console/consoleweb/consoleapi"
+	"storj.io/storj/satellite/console/consoleauth"
 )
 
 func TestAuth_Register(t *testing.T) {
@@ -117,127 +115,118 @@ func TestAuth_Register(t *testing.T) {
 }
 
 func TestDeleteAccount(t *testing.T) {
-	ctx := testcontext.New(t)
-
-	// We do a black box testing because currently we don't allow to delete
-	// accounts through the API hence we must always return an error response.
-
-	config := &quick.Config{
-		Values: func(values []reflect.Value, rnd *rand.Rand) {
-			// TODO: use or implement a better and thorough HTTP Request random generator
-
-			var method string
-			switch rnd.Intn(9) {
-			case 0:
-				method = http.MethodGet
-			case 1:
-				method = http.MethodHead
-			case 2:
-				method = http.MethodPost
-			case 3:
-				method = http.MethodPut
-			case 4:
-				method = http.MethodPatch
-			case 5:
-				method = http.MethodDelete
-			case 6:
-				method = http.MethodConnect
-			case 7:
-				method = http.MethodOptions
-			case 8:
-				method = http.MethodTrace
-			default:
-				t.Fatal("unexpected random value for HTTP method selection")
-			}
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		gracePeriod := sat.Config.Console.AccountDeletionGracePeriod
 
-			var path string
-			{
+		deleteAccount := func(user *console.User, password string) (int, map[string]interface{}) {
+			token, err := sat.API.Console.Service.Token(ctx, console.AuthUser{Email: user.Email, Password: user.FullName})
+			require.NoError(t, err)
 
-				val, ok := quick.Value(reflect.TypeOf(""), rnd)
-				require.True(t, ok, "quick.Values generator function couldn't generate a string")
-				path = url.PathEscape(val.String())
-			}
+			urlLink := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/auth/account/delete"
 
-			var query string
-			{
-				nparams := rnd.Intn(27)
-				params := make([]string, nparams)
+			bodyBytes, err := json.Marshal(map[string]string{"password": password})
+			require.NoError(t, err)
 
-				for i := 0; i < nparams; i++ {
-					val, ok := quick.Value(reflect.TypeOf(""), rnd)
-					require.True(t, ok, "quick.Values generator function couldn't generate a string")
-					param := val.String()
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, urlLink, bytes.NewBuffer(bodyBytes))
+			require.NoError(t, err)
 
-					val, ok = quick.Value(reflect.TypeOf(""), rnd)
-					require.True(t, ok, "quick.Values generator function couldn't generate a string")
-					param += "=" + val.String()
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   token,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+			req.Header.Set("Content-Type", "application/json")
 
-					params[i] = param
-				}
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, result.Body.Close()) }()
 
-				query = url.QueryEscape(strings.Join(params, "&"))
-			}
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&body))
 
-			var body io.Reader
-			{
-				val, ok := quick.Value(reflect.TypeOf([]byte(nil)), rnd)
-				require.True(t, ok, "quick.Values generator function couldn't generate a byte slice")
-				body = bytes.NewReader(val.Bytes())
-			}
+			return result.StatusCode, body
+		}
 
-			withQuery := ""
-			if len(query) > 0 {
-				withQuery = "?"
-			}
+		{ // Expect an account without projects or usage to be scheduled for deletion.
+			user, err := sat.AddUser(ctx, console.CreateUser{
+				FullName: "Delete Test User",
+				Email:    "deleteuser@mail.test",
+			}, 1)
+			require.NoError(t, err)
 
-			reqURL, err := url.Parse("//storj.io/" + path + withQuery + query)
-			require.NoError(t, err, "error when generating a random URL")
-			req, err := http.NewRequestWithContext(ctx, method, reqURL.String(), body)
-			require.NoError(t, err, "error when geneating a random request")
-			values[0] = reflect.ValueOf(req)
-		},
-	}
+			status, _ := deleteAccount(user, "wrong password")
+			require.Equal(t, http.StatusUnauthorized, status)
 
-	expectedHandler := func(_ *http.Request) (status int, body []byte) {
-		return http.StatusNotImplemented, []byte("{\"error\":\"The server is incapable of fulfilling the request\"}\n")
-	}
+			before := time.Now()
+			status, body := deleteAccount(user, user.FullName)
+			require.Equal(t, http.StatusAccepted, status)
 
-	actualHandler := func(r *http.Request) (status int, body []byte) {
-		rr := httptest.NewRecorder()
-		authController := consoleapi.NewAuth(zap.L(), nil, nil, nil, nil, nil, "", "", "", "")
-		authController.DeleteAccount(rr, r)
+			deleteAt, err := time.Parse(time.RFC3339Nano, body["deleteAt"].(string))
+			require.NoError(t, err)
+			require.WithinDuration(t, before.Add(gracePeriod), deleteAt, time.Minute)
 
-		//nolint:bodyclose
-		result := rr.Result()
-		defer func() {
-			err := result.Body.Close()
+			user, err = sat.DB.Console().Users().Get(ctx, user.ID)
 			require.NoError(t, err)
-		}()
+			require.NotNil(t, user.DeletionRequestedAt)
+			require.WithinDuration(t, before, *user.DeletionRequestedAt, time.Minute)
 
-		body, err := ioutil.ReadAll(result.Body)
-		require.NoError(t, err)
+			// Expect a repeated request to keep the original deletion date.
+			status, body = deleteAccount(user, user.FullName)
+			require.Equal(t, http.StatusAccepted, status)
+			repeatedDeleteAt, err := time.Parse(time.RFC3339Nano, body["deleteAt"].(string))
+			require.NoError(t, err)
+			require.WithinDuration(t, deleteAt, repeatedDeleteAt, time.Millisecond)
+		}
 
-		return result.StatusCode, body
+		{ // Expect an account that still owns a project to be blocked from deletion.
+			user, err := sat.AddUser(ctx, console.CreateUser{
+				FullName: "Blocked Delete Test User",
+				Email:    "blockeddeleteuser@mail.test",
+			}, 1)
+			require.NoError(t, err)
 
-	}
+			_, err = sat.AddProject(ctx, user.ID, "blocked project")
+			require.NoError(t, err)
 
-	err := quick.CheckEqual(expectedHandler, actualHandler, config)
-	if err != nil {
-		fmt.Printf("%+v\n", err)
-		var cerr *quick.CheckEqualError
-		require.True(t, errors.As(err, &cerr))
-
-		t.Fatalf(`DeleteAccount handler has returned a different response:
-round: %d
-input args: %+v
-expected response:
-	status code: %d
-	response body: %s
-returned response:
-	status code: %d
-	response body: %s
-`, cerr.Count, cerr.In, cerr.Out1[0], cerr.Out1[1], cerr.Out2[0], cerr.Out2[1])
-	}
+			status, body := deleteAccount(user, user.FullName)
+			require.Equal(t, http.StatusConflict, status)
+			require.Contains(t, body["error"], "project")
+
+			user, err = sat.DB.Console().Users().Get(ctx, user.ID)
+			require.NoError(t, err)
+			require.Nil(t, user.DeletionRequestedAt)
+		}
+
+		{ // Expect an account with usage which isn't invoiced yet to be blocked from deletion.
+			user, err := sat.AddUser(ctx, console.CreateUser{
+				FullName: "Unpaid Delete Test User",
+				Email:    "unpaiddeleteuser@mail.test",
+			}, 1)
+			require.NoError(t, err)
+
+			customerID, err := sat.DB.StripeCoinPayments().Customers().GetCustomerID(ctx, user.ID)
+			require.NoError(t, err)
+			_, err = sat.API.Payments.Stripe.InvoiceItems().New(&stripe.InvoiceItemParams{
+				Customer:    stripe.String(customerID),
+				Amount:      stripe.Int64(100),
+				Currency:    stripe.String(string(stripe.CurrencyUSD)),
+				Description: stripe.String("unpaid usage"),
+			})
+			require.NoError(t, err)
+
+			status, body := deleteAccount(user, user.FullName)
+			require.Equal(t, http.StatusConflict, status)
+			require.Contains(t, body["error"], "pending invoice items")
+
+			user, err = sat.DB.Console().Users().Get(ctx, user.ID)
+			require.NoError(t, err)
+			require.Nil(t, user.DeletionRequestedAt)
+		}
+	})
 }
 
 func TestMFAEndpoints(t *testing.T) {
@@ -292,6 +281,30 @@ func TestMFAEndpoints(t *testing.T) {
 			return result
 		}
 
+		getRecoveryCodeCount := func() int {
+			urlLink := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/auth/mfa/recovery-codes/count"
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlLink, nil)
+			require.NoError(t, err)
+
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   token,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, result.StatusCode)
+
+			var count int
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&count))
+			require.NoError(t, result.Body.Close())
+
+			return count
+		}
+
 		// Expect failure because MFA is not enabled.
 		result := doRequest("/generate-recovery-codes", "", "")
 		require.Equal(t, http.StatusUnauthorized, result.StatusCode)
@@ -340,6 +353,7 @@ func TestMFAEndpoints(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, codes, console.MFARecoveryCodeCount)
 		require.NoError(t, result.Body.Close())
+		require.Equal(t, console.MFARecoveryCodeCount, getRecoveryCodeCount())
 
 		// Expect no token due to missing passcode.
 		newToken, err := sat.API.Console.Service.Token(ctx, console.AuthUser{Email: user.Email, Password: user.FullName})
@@ -364,7 +378,7 @@ func TestMFAEndpoints(t *testing.T) {
 		require.True(t, console.ErrUnauthorized.Has(err))
 		require.Empty(t, newToken)
 
-		for _, code := range codes {
+		for i, code := range codes {
 			opts := console.AuthUser{
 				Email:           user.Email,
 				Password:        user.FullName,
@@ -380,6 +394,9 @@ func TestMFAEndpoints(t *testing.T) {
 			newToken, err = sat.API.Console.Service.Token(ctx, opts)
 			require.True(t, console.ErrUnauthorized.Has(err))
 			require.Empty(t, newToken)
+
+			// Expect the consumed recovery code to no longer be counted.
+			require.Equal(t, len(codes)-i-1, getRecoveryCodeCount())
 		}
 
 		// Expect failure due to disabling MFA with no passcode.
@@ -472,3 +489,627 @@ func TestResetPasswordEndpoint(t *testing.T) {
 		require.Equal(t, http.StatusOK, tryReset(tokenStr, newPass))
 	})
 }
+
+func TestPasswordPolicy(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		policy console.PasswordPolicyConfig
+		// expected status code for each password.
+		expected map[string]int
+	}{
+		{
+			name:   "default",
+			policy: console.PasswordPolicyConfig{MinLength: 6},
+			expected: map[string]int{
+				"short":        http.StatusBadRequest,
+				"nodigits":     http.StatusOK,
+				"n0uppercase":  http.StatusOK,
+				"N0symbols":    http.StatusOK,
+				"Complete-p4s": http.StatusOK,
+			},
+		},
+		{
+			name: "all rules",
+			policy: console.PasswordPolicyConfig{
+				MinLength:     10,
+				RequireDigit:  true,
+				RequireUpper:  true,
+				RequireSymbol: true,
+			},
+			expected: map[string]int{
+				"Sh0rt-p4s":        http.StatusBadRequest,
+				"No-digits-at-all": http.StatusBadRequest,
+				"n0-uppercase-at":  http.StatusBadRequest,
+				"N0symbolsatall":   http.StatusBadRequest,
+				"Complete-p4s":     http.StatusOK,
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			testplanet.Run(t, testplanet.Config{
+				SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+				Reconfigure: testplanet.Reconfigure{
+					Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+						config.Console.OpenRegistrationEnabled = true
+						config.Console.RateLimit.Burst = 100
+						config.Console.PasswordPolicy = tt.policy
+					},
+				},
+			}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+				sat := planet.Satellites[0]
+
+				doRequest := func(endpoint string, body interface{}) int {
+					url := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/auth/" + endpoint
+
+					bodyBytes, err := json.Marshal(body)
+					require.NoError(t, err)
+
+					req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(bodyBytes))
+					require.NoError(t, err)
+					req.Header.Set("Content-Type", "application/json")
+
+					result, err := http.DefaultClient.Do(req)
+					require.NoError(t, err)
+					require.NoError(t, result.Body.Close())
+					return result.StatusCode
+				}
+
+				user, err := sat.AddUser(ctx, console.CreateUser{
+					FullName: "Test User",
+					Email:    "reset@mail.test",
+				}, 1)
+				require.NoError(t, err)
+
+				i := 0
+				for password, expected := range tt.expected {
+					i++
+
+					status := doRequest("register", map[string]string{
+						"fullName": "Test User",
+						"email":    "user" + strconv.Itoa(i) + "@mail.test",
+						"password": password,
+					})
+					require.Equal(t, expected, status, "register with %q", password)
+
+					token, err := sat.DB.Console().ResetPasswordTokens().Create(ctx, user.ID)
+					require.NoError(t, err)
+
+					status = doRequest("reset-password", map[string]string{
+						"token":    token.Secret.String(),
+						"password": password,
+					})
+					require.Equal(t, expected, status, "reset password with %q", password)
+
+					if expected != http.StatusOK {
+						// the token is only used up by a successful reset.
+						require.NoError(t, sat.DB.Console().ResetPasswordTokens().Delete(ctx, token.Secret))
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestRegistrationEmailDomains(t *testing.T) {
+	dir, err := ioutil.TempDir("", "email-domains")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, os.RemoveAll(dir)) }()
+
+	blockedFile := filepath.Join(dir, "blocked.txt")
+	require.NoError(t, ioutil.WriteFile(blockedFile, []byte("# disposable domains\n\nTrash.Test\n"), 0644))
+
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Console.OpenRegistrationEnabled = true
+				config.Console.RateLimit.Burst = 100
+				config.Console.RegistrationEmailDomains = console.EmailDomainsConfig{
+					Allowed:     console.EmailDomains{"company.test", "trash.test"},
+					Blocked:     console.EmailDomains{"Blocked.Company.Test"},
+					BlockedFile: blockedFile,
+				}
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		register := func(email string) int {
+			url := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/auth/register"
+
+			bodyBytes, err := json.Marshal(map[string]string{
+				"fullName": "Test User",
+				"email":    email,
+				"password": "123a123",
+			})
+			require.NoError(t, err)
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(bodyBytes))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+			return result.StatusCode
+		}
+
+		for email, expected := range map[string]int{
+			"allowed@company.test":         http.StatusOK,
+			"mixed@Company.TEST":           http.StatusOK,
+			"blocked@blocked.company.test": http.StatusBadRequest,
+			"file@trash.test":              http.StatusBadRequest,
+			"other@mail.test":              http.StatusBadRequest,
+		} {
+			require.Equal(t, expected, register(email), email)
+		}
+
+		// the email can't be changed to a domain which isn't allowed either.
+		user, err := sat.DB.Console().Users().GetByEmail(ctx, "allowed@company.test")
+		require.NoError(t, err)
+		authCtx := console.WithAuth(ctx, console.Authorization{User: *user})
+
+		for _, email := range []string{"blocked@blocked.company.test", "file@trash.test", "other@mail.test"} {
+			_, err := sat.API.Console.Service.ChangeEmail(authCtx, email)
+			require.True(t, console.ErrValidation.Has(err), email)
+		}
+		_, err = sat.API.Console.Service.ChangeEmail(authCtx, "changed@company.test")
+		require.NoError(t, err)
+	})
+}
+
+func TestLogoutAllEndpoint(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		user, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Logout Test User",
+			Email:    "logoutuser@mail.test",
+		}, 1)
+		require.NoError(t, err)
+
+		authUser := console.AuthUser{Email: user.Email, Password: user.FullName}
+
+		doRequest := func(method, urlSuffix, token string) int {
+			urlLink := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/auth" + urlSuffix
+
+			req, err := http.NewRequestWithContext(ctx, method, urlLink, nil)
+			require.NoError(t, err)
+
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   token,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+			return result.StatusCode
+		}
+
+		oldToken, err := sat.API.Console.Service.Token(ctx, authUser)
+		require.NoError(t, err)
+		otherToken, err := sat.API.Console.Service.Token(ctx, authUser)
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusOK, doRequest(http.MethodGet, "/account", oldToken))
+		require.Equal(t, http.StatusOK, doRequest(http.MethodGet, "/account", otherToken))
+
+		require.Equal(t, http.StatusOK, doRequest(http.MethodPost, "/logout-all", oldToken))
+
+		// Expect every token issued before logging out everywhere to be rejected.
+		require.Equal(t, http.StatusUnauthorized, doRequest(http.MethodGet, "/account", oldToken))
+		require.Equal(t, http.StatusUnauthorized, doRequest(http.MethodGet, "/account", otherToken))
+		require.Equal(t, http.StatusUnauthorized, doRequest(http.MethodPost, "/logout-all", oldToken))
+
+		// Expect a freshly issued token to work.
+		newToken, err := sat.API.Console.Service.Token(ctx, authUser)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, doRequest(http.MethodGet, "/account", newToken))
+	})
+}
+
+func TestChangePasswordInvalidatesSessions(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		newPass := "123a123"
+
+		user, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Change Password Test User",
+			Email:    "changepassworduser@mail.test",
+		}, 1)
+		require.NoError(t, err)
+
+		authUser := console.AuthUser{Email: user.Email, Password: user.FullName}
+
+		doRequest := func(method, urlSuffix, token string, body interface{}) *http.Response {
+			urlLink := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/auth" + urlSuffix
+
+			var buf io.Reader
+			if body != nil {
+				bodyBytes, err := json.Marshal(body)
+				require.NoError(t, err)
+				buf = bytes.NewBuffer(bodyBytes)
+			}
+
+			req, err := http.NewRequestWithContext(ctx, method, urlLink, buf)
+			require.NoError(t, err)
+
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   token,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+			req.Header.Set("Content-Type", "application/json")
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+			return result
+		}
+
+		sessionToken, err := sat.API.Console.Service.Token(ctx, authUser)
+		require.NoError(t, err)
+		otherToken, err := sat.API.Console.Service.Token(ctx, authUser)
+		require.NoError(t, err)
+
+		result := doRequest(http.MethodPost, "/account/change-password", sessionToken, map[string]string{
+			"password":    user.FullName,
+			"newPassword": newPass,
+		})
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		var newToken string
+		for _, cookie := range result.Cookies() {
+			if cookie.Name == "_tokenKey" {
+				newToken = cookie.Value
+			}
+		}
+		require.NotEmpty(t, newToken)
+
+		// Expect tokens issued before the password change to be rejected.
+		require.Equal(t, http.StatusUnauthorized, doRequest(http.MethodGet, "/account", otherToken, nil).StatusCode)
+		require.Equal(t, http.StatusUnauthorized, doRequest(http.MethodGet, "/account", sessionToken, nil).StatusCode)
+
+		// Expect the session that changed the password to stay logged in.
+		require.Equal(t, http.StatusOK, doRequest(http.MethodGet, "/account", newToken, nil).StatusCode)
+
+		// Expect the invalidation to be persisted, so that it applies to
+		// every API instance and survives restarts.
+		dbUser, err := sat.DB.Console().Users().Get(ctx, user.ID)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, dbUser.TokenEpoch)
+	})
+}
+
+func TestUpdateAccountProfessionalInfo(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		user, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Update Account Test User",
+			Email:    "updateaccountuser@mail.test",
+		}, 1)
+		require.NoError(t, err)
+
+		token, err := sat.API.Console.Service.Token(ctx, console.AuthUser{Email: user.Email, Password: user.FullName})
+		require.NoError(t, err)
+
+		updateAccount := func(info interface{}) int {
+			urlLink := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/auth/account"
+
+			bodyBytes, err := json.Marshal(info)
+			require.NoError(t, err)
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPatch, urlLink, bytes.NewBuffer(bodyBytes))
+			require.NoError(t, err)
+
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   token,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+			req.Header.Set("Content-Type", "application/json")
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+			return result.StatusCode
+		}
+
+		require.Equal(t, http.StatusOK, updateAccount(console.UserInfo{
+			FullName:         " Professional User ",
+			ShortName:        "Pro",
+			IsProfessional:   true,
+			Position:         " Engineer ",
+			CompanyName:      "Storj Labs",
+			EmployeeCount:    "51-1000",
+			HaveSalesContact: true,
+		}))
+
+		updated, err := sat.DB.Console().Users().Get(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Professional User", updated.FullName)
+		require.Equal(t, "Pro", updated.ShortName)
+		require.True(t, updated.IsProfessional)
+		require.Equal(t, "Engineer", updated.Position)
+		require.Equal(t, "Storj Labs", updated.CompanyName)
+		require.Equal(t, "51-1000", updated.EmployeeCount)
+		require.True(t, updated.HaveSalesContact)
+		// Expect fields which aren't part of the account info to be kept.
+		require.Equal(t, user.ProjectLimit, updated.ProjectLimit)
+		require.Equal(t, console.Active, updated.Status)
+
+		// Expect professional info which isn't sent to be kept.
+		require.Equal(t, http.StatusOK, updateAccount(map[string]string{
+			"fullName":  "Renamed User",
+			"shortName": "Renamed",
+		}))
+
+		updated, err = sat.DB.Console().Users().Get(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Renamed User", updated.FullName)
+		require.True(t, updated.IsProfessional)
+		require.Equal(t, "Engineer", updated.Position)
+		require.Equal(t, "51-1000", updated.EmployeeCount)
+
+		// Expect stored values which aren't changed not to be validated again.
+		updated.EmployeeCount = "legacy range"
+		updated.CompanyName = strings.Repeat("c", 101)
+		require.NoError(t, sat.DB.Console().Users().Update(ctx, updated))
+
+		require.Equal(t, http.StatusOK, updateAccount(map[string]string{
+			"fullName":  "Renamed Again",
+			"shortName": "Again",
+		}))
+		require.Equal(t, http.StatusBadRequest, updateAccount(map[string]string{
+			"fullName":    "Renamed Again",
+			"companyName": strings.Repeat("b", 101),
+		}))
+
+		updated, err = sat.DB.Console().Users().Get(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Renamed Again", updated.FullName)
+		require.Equal(t, "legacy range", updated.EmployeeCount)
+
+		// Expect invalid professional info to be rejected.
+		for _, info := range []console.UserInfo{
+			{FullName: "Professional User", IsProfessional: true, EmployeeCount: "1-1000000"},
+			{FullName: "Professional User", IsProfessional: true, EmployeeCount: "1-50", CompanyName: strings.Repeat("a", 101)},
+			{FullName: "Professional User", IsProfessional: true, EmployeeCount: "1-50", Position: strings.Repeat("a", 101)},
+			{FullName: "   "},
+		} {
+			require.Equal(t, http.StatusBadRequest, updateAccount(info))
+		}
+
+		require.Equal(t, http.StatusOK, updateAccount(console.UserInfo{
+			FullName:  "Personal User",
+			ShortName: "Personal",
+		}))
+
+		updated, err = sat.DB.Console().Users().Get(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Personal User", updated.FullName)
+		require.Equal(t, "Personal", updated.ShortName)
+		require.False(t, updated.IsProfessional)
+		require.Empty(t, updated.Position)
+		require.Empty(t, updated.CompanyName)
+		require.Empty(t, updated.EmployeeCount)
+		require.False(t, updated.HaveSalesContact)
+	})
+}
+
+func TestChangeEmailConfirmation(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		service := sat.API.Console.Service
+
+		user, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Change Email Test User",
+			Email:    "changeemailuser@mail.test",
+		}, 1)
+		require.NoError(t, err)
+
+		otherUser, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Other Email Test User",
+			Email:    "otheremailuser@mail.test",
+		}, 1)
+		require.NoError(t, err)
+
+		sessionToken, err := service.Token(ctx, console.AuthUser{Email: user.Email, Password: user.FullName})
+		require.NoError(t, err)
+
+		client := http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+
+		changeEmail := func(newEmail string) int {
+			urlLink := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/auth/account/change-email"
+
+			bodyBytes, err := json.Marshal(map[string]string{"newEmail": newEmail})
+			require.NoError(t, err)
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, urlLink, bytes.NewBuffer(bodyBytes))
+			require.NoError(t, err)
+
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   sessionToken,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+			req.Header.Set("Content-Type", "application/json")
+
+			result, err := client.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+			return result.StatusCode
+		}
+
+		confirmEmail := func(token string) int {
+			urlLink := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/auth/account/confirm-email?token=" + token
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlLink, nil)
+			require.NoError(t, err)
+
+			result, err := client.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+			return result.StatusCode
+		}
+
+		createToken := func(claims consoleauth.Claims) string {
+			payload, err := claims.JSON()
+			require.NoError(t, err)
+
+			token := consoleauth.Token{Payload: payload}
+			token.Signature, err = service.Signer.Sign([]byte(base64.URLEncoding.EncodeToString(payload)))
+			require.NoError(t, err)
+			return token.String()
+		}
+
+		getEmail := func() string {
+			updated, err := sat.DB.Console().Users().Get(ctx, user.ID)
+			require.NoError(t, err)
+			return updated.Email
+		}
+
+		const newEmail = "changedemailuser@mail.test"
+
+		// Expect the request to leave the old email in use.
+		require.Equal(t, http.StatusOK, changeEmail(newEmail))
+		require.Equal(t, user.Email, getEmail())
+
+		// Expect already used emails to be rejected.
+		require.Equal(t, http.StatusConflict, changeEmail(otherUser.Email))
+		require.Equal(t, http.StatusBadRequest, changeEmail("not an email"))
+
+		// Expect expired tokens to be rejected.
+		expired := createToken(consoleauth.Claims{
+			ID:         user.ID,
+			Email:      user.Email,
+			NewEmail:   newEmail,
+			Expiration: time.Now().Add(-time.Minute),
+		})
+		require.Equal(t, http.StatusUnauthorized, confirmEmail(expired))
+		require.Equal(t, user.Email, getEmail())
+
+		// Expect tokens which aren't email change tokens to be rejected.
+		require.Equal(t, http.StatusBadRequest, confirmEmail(sessionToken))
+		require.Equal(t, http.StatusBadRequest, confirmEmail("invalid"))
+
+		// Expect confirming a change to an email that was taken in the meantime to fail.
+		taken := createToken(consoleauth.Claims{
+			ID:         user.ID,
+			Email:      user.Email,
+			NewEmail:   otherUser.Email,
+			Expiration: time.Now().Add(time.Hour),
+		})
+		require.Equal(t, http.StatusConflict, confirmEmail(taken))
+		require.Equal(t, user.Email, getEmail())
+
+		// Expect an email change token to not be usable for logging in.
+		token, err := service.ChangeEmail(console.WithAuth(ctx, console.Authorization{User: *user}), newEmail)
+		require.NoError(t, err)
+		_, err = service.Authorize(consoleauth.WithAPIKey(ctx, []byte(token)))
+		require.Error(t, err)
+
+		require.Equal(t, http.StatusTemporaryRedirect, confirmEmail(token))
+		require.Equal(t, newEmail, getEmail())
+
+		// Expect the token to be unusable once the email has been changed.
+		require.Equal(t, http.StatusUnauthorized, confirmEmail(token))
+	})
+}
+
+func TestAccountEmailRateLimit(t *testing.T) {
+	const maxEmails = 3
+
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Console.EmailRateLimit.MaxEmails = maxEmails
+				config.Console.EmailRateLimit.Window = time.Hour
+				// the requests vary X-Real-IP, which is only honored for trusted proxies.
+				require.NoError(t, config.Console.RateLimit.TrustedProxies.Set("127.0.0.1"))
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		// adding the user sends the first activation email.
+		user, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Email Rate Limit Test User",
+			Email:    "emailratelimituser@mail.test",
+		}, 1)
+		require.NoError(t, err)
+
+		otherUser, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Other Rate Limit Test User",
+			Email:    "otherratelimituser@mail.test",
+		}, 1)
+		require.NoError(t, err)
+
+		var requests int
+		doRequest := func(urlSuffix string) int {
+			urlLink := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/auth" + urlSuffix
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, urlLink, nil)
+			require.NoError(t, err)
+
+			// every request comes from a different IP to avoid the IP rate limiter.
+			requests++
+			req.Header.Set("X-Real-IP", "10.0.0."+strconv.Itoa(requests))
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+			return result.StatusCode
+		}
+
+		recoveryToken := func(userID uuid.UUID) console.ResetPasswordSecret {
+			token, err := sat.DB.Console().ResetPasswordTokens().GetByOwnerID(ctx, userID)
+			require.NoError(t, err)
+			return token.Secret
+		}
+
+		require.Equal(t, http.StatusOK, doRequest("/forgot-password/"+user.Email))
+		first := recoveryToken(user.ID)
+
+		require.Equal(t, http.StatusOK, doRequest("/forgot-password/"+user.Email))
+		second := recoveryToken(user.ID)
+		require.NotEqual(t, first, second)
+
+		// Expect further emails to be dropped while still responding successfully.
+		require.Equal(t, http.StatusOK, doRequest("/forgot-password/"+user.Email))
+		require.Equal(t, second, recoveryToken(user.ID))
+		require.Equal(t, http.StatusOK, doRequest("/resend-email/"+user.ID.String()))
+
+		_, err = sat.API.Console.Service.GenerateActivationToken(ctx, user.ID, user.Email)
+		require.True(t, console.ErrEmailRateLimit.Has(err))
+
+		// Expect unknown accounts to get the same response.
+		require.Equal(t, http.StatusOK, doRequest("/forgot-password/unknownuser@mail.test"))
+		require.Equal(t, http.StatusOK, doRequest("/resend-email/"+testrand.UUID().String()))
+
+		// Expect other accounts to be unaffected.
+		require.Equal(t, http.StatusOK, doRequest("/forgot-password/"+otherUser.Email))
+		recoveryToken(otherUser.ID)
+	})
+}
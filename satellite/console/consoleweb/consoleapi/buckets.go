@@ -4,14 +4,22 @@
 package consoleapi
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 
+	"storj.io/common/storj"
 	"storj.io/common/uuid"
 	"storj.io/storj/satellite/console"
+	"storj.io/storj/satellite/metabase"
 )
 
 var (
@@ -66,6 +74,289 @@ func (b *Buckets) AllBucketNames(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// CreateBucket creates a new bucket in a specific project.
+func (b *Buckets) CreateBucket(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var request struct {
+		ProjectID string `json:"projectID"`
+		Name      string `json:"name"`
+	}
+
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		b.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	projectID, err := uuid.FromString(request.ProjectID)
+	if err != nil {
+		b.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	bucket, err := b.service.CreateBucket(ctx, projectID, request.Name)
+	if err != nil {
+		b.serveJSONError(w, bucketStatusCode(err), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	err = json.NewEncoder(w).Encode(struct {
+		Name      string    `json:"name"`
+		CreatedAt time.Time `json:"createdAt"`
+	}{
+		Name:      bucket.Name,
+		CreatedAt: bucket.Created,
+	})
+	if err != nil {
+		b.log.Error("failed to write json create bucket response", zap.Error(ErrBucketsAPI.Wrap(err)))
+	}
+}
+
+// DeleteBucket deletes a bucket from a specific project. Buckets that still
+// have objects are only deleted when the force query parameter is set.
+func (b *Buckets) DeleteBucket(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	query := r.URL.Query()
+
+	projectID, err := uuid.FromString(query.Get("projectID"))
+	if err != nil {
+		b.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var force bool
+	if value := query.Get("force"); value != "" {
+		force, err = strconv.ParseBool(value)
+		if err != nil {
+			b.serveJSONError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	err = b.service.DeleteBucket(ctx, projectID, mux.Vars(r)["name"], force)
+	if err != nil {
+		b.serveJSONError(w, bucketStatusCode(err), err)
+		return
+	}
+}
+
+// ListObjects returns a page of the objects and prefixes of a bucket. The keys
+// are returned as stored by the satellite, i.e. encrypted unless path encryption
+// is disabled, and relative to the prefix. The cursor of the next page is returned
+// when there are more objects. The keys, the prefix and the cursor are base64url
+// encoded, see encodeObjectKey.
+func (b *Buckets) ListObjects(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query()
+
+	projectID, err := uuid.FromString(query.Get("projectID"))
+	if err != nil {
+		b.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var limit int
+	if value := query.Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			b.serveJSONError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	prefix, err := decodeObjectKey(query.Get("prefix"))
+	if err != nil {
+		b.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	cursor, err := decodeObjectKey(query.Get("cursor"))
+	if err != nil {
+		b.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	entries, more, err := b.service.ListBucketObjects(ctx, projectID, mux.Vars(r)["name"], string(prefix), string(cursor), limit)
+	if err != nil {
+		b.serveJSONError(w, bucketStatusCode(err), err)
+		return
+	}
+
+	type object struct {
+		Key          string     `json:"key"`
+		IsPrefix     bool       `json:"isPrefix"`
+		CreatedAt    time.Time  `json:"createdAt"`
+		ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+		SegmentCount int32      `json:"segmentCount"`
+		Size         int64      `json:"size"`
+	}
+	var response struct {
+		Objects    []object `json:"objects"`
+		NextCursor string   `json:"nextCursor,omitempty"`
+	}
+
+	response.Objects = make([]object, 0, len(entries))
+	for _, entry := range entries {
+		response.Objects = append(response.Objects, object{
+			Key:          encodeObjectKey(entry.ObjectKey),
+			IsPrefix:     entry.IsPrefix,
+			CreatedAt:    entry.CreatedAt,
+			ExpiresAt:    entry.ExpiresAt,
+			SegmentCount: entry.SegmentCount,
+			Size:         entry.TotalEncryptedSize,
+		})
+	}
+	if more && len(entries) > 0 {
+		response.NextCursor = encodeObjectKey(entries[len(entries)-1].ObjectKey)
+	}
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		b.log.Error("failed to write json list objects response", zap.Error(ErrBucketsAPI.Wrap(err)))
+	}
+}
+
+// ObjectMetadata returns the metadata of the latest version of an object. It
+// supports conditional requests with If-None-Match and If-Modified-Since, so
+// that clients can cheaply poll for changes of the object. The key is base64url
+// encoded like the keys returned by ListObjects.
+func (b *Buckets) ObjectMetadata(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	projectID, err := uuid.FromString(r.URL.Query().Get("projectID"))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		b.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	vars := mux.Vars(r)
+	key, err := decodeObjectKey(vars["key"])
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		b.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	object, err := b.service.GetBucketObject(ctx, projectID, vars["name"], string(key))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		b.serveJSONError(w, bucketStatusCode(err), err)
+		return
+	}
+
+	etag := objectETag(object)
+	header := w.Header()
+	header.Set("ETag", etag)
+	header.Set("Last-Modified", object.CreatedAt.UTC().Format(http.TimeFormat))
+	header.Set("Cache-Control", "private, no-cache")
+
+	if !objectModified(r, etag, object.CreatedAt) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	header.Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(struct {
+		Key          string     `json:"key"`
+		CreatedAt    time.Time  `json:"createdAt"`
+		ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+		SegmentCount int32      `json:"segmentCount"`
+		Size         int64      `json:"size"`
+	}{
+		Key:          encodeObjectKey(object.ObjectKey),
+		CreatedAt:    object.CreatedAt,
+		ExpiresAt:    object.ExpiresAt,
+		SegmentCount: object.SegmentCount,
+		Size:         object.TotalEncryptedSize,
+	})
+	if err != nil {
+		b.log.Error("failed to write json object metadata response", zap.Error(ErrBucketsAPI.Wrap(err)))
+	}
+}
+
+// encodeObjectKey encodes an object key with unpadded base64url. The keys are
+// encrypted unless path encryption is disabled, so they aren't valid UTF-8 in
+// general and can't be returned in JSON as they are.
+func encodeObjectKey(key metabase.ObjectKey) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+// decodeObjectKey decodes an object key, prefix or cursor encoded with
+// encodeObjectKey.
+func decodeObjectKey(value string) (metabase.ObjectKey, error) {
+	key, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return "", ErrBucketsAPI.New("invalid base64url encoded key: %v", err)
+	}
+	return metabase.ObjectKey(key), nil
+}
+
+// objectETag derives the ETag of the object. It changes whenever the object is
+// overwritten, because the stream ID of the new object is different.
+func objectETag(object metabase.Object) string {
+	return `"` + hex.EncodeToString(object.StreamID[:]) + "-" + strconv.FormatInt(int64(object.Version), 10) + "-" + strconv.FormatInt(object.TotalEncryptedSize, 10) + `"`
+}
+
+// objectModified evaluates the conditional headers of the request. As in
+// RFC 7232, If-Modified-Since is ignored when If-None-Match is present.
+func objectModified(r *http.Request, etag string, createdAt time.Time) bool {
+	if noneMatch := r.Header.Get("If-None-Match"); noneMatch != "" {
+		for _, value := range strings.Split(noneMatch, ",") {
+			value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+			if value == "*" || value == etag {
+				return false
+			}
+		}
+		return true
+	}
+
+	if modifiedSince := r.Header.Get("If-Modified-Since"); modifiedSince != "" {
+		since, err := http.ParseTime(modifiedSince)
+		if err != nil {
+			return true
+		}
+		// Last-Modified has a precision of a second.
+		return createdAt.Truncate(time.Second).After(since)
+	}
+
+	return true
+}
+
+// bucketStatusCode returns the http status code matching a bucket operation error.
+func bucketStatusCode(err error) int {
+	switch {
+	case console.ErrValidation.Has(err):
+		return http.StatusBadRequest
+	case console.ErrUnauthorized.Has(err), console.ErrNoMembership.Has(err):
+		return http.StatusUnauthorized
+	case console.ErrBucketLimit.Has(err):
+		return http.StatusForbidden
+	case storj.ErrBucketNotFound.Has(err), storj.ErrObjectNotFound.Has(err):
+		return http.StatusNotFound
+	case console.ErrBucketExists.Has(err), console.ErrBucketNotEmpty.Has(err):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // serveJSONError writes JSON error to response output stream.
 func (b *Buckets) serveJSONError(w http.ResponseWriter, status int, err error) {
 	serveJSONError(b.log, w, status, err)
@@ -4,6 +4,8 @@
 package consoleapi_test
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -19,6 +21,8 @@ import (
 	"storj.io/storj/private/testplanet"
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/console"
+	"storj.io/storj/satellite/metabase"
+	"storj.io/storj/satellite/metabase/metabasetest"
 )
 
 func Test_AllBucketNames(t *testing.T) {
@@ -103,3 +107,392 @@ func Test_AllBucketNames(t *testing.T) {
 		}()
 	})
 }
+
+func TestCreateAndDeleteBucket(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		user, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Bucket Creator",
+			Email:    "bucketcreator@test.test",
+		}, 1)
+		require.NoError(t, err)
+
+		project, err := sat.AddProject(ctx, user.ID, "buckets")
+		require.NoError(t, err)
+
+		// we are using full name as a password
+		token, err := sat.API.Console.Service.Token(ctx, console.AuthUser{Email: user.Email, Password: user.FullName})
+		require.NoError(t, err)
+
+		bucketsURL := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/buckets"
+
+		doRequest := func(method, url string, body interface{}) int {
+			var reqBody bytes.Buffer
+			if body != nil {
+				require.NoError(t, json.NewEncoder(&reqBody).Encode(body))
+			}
+
+			req, err := http.NewRequestWithContext(ctx, method, url, &reqBody)
+			require.NoError(t, err)
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   token,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+
+			return result.StatusCode
+		}
+
+		createBucket := func(name string) int {
+			return doRequest(http.MethodPost, bucketsURL, map[string]string{
+				"projectID": project.ID.String(),
+				"name":      name,
+			})
+		}
+
+		deleteBucket := func(name string, force bool) int {
+			url := bucketsURL + "/" + name + "?projectID=" + project.ID.String()
+			if force {
+				url += "&force=true"
+			}
+			return doRequest(http.MethodDelete, url, nil)
+		}
+
+		t.Run("create", func(t *testing.T) {
+			require.Equal(t, http.StatusCreated, createBucket("empty-bucket"))
+
+			bucket, err := sat.DB.Buckets().GetBucket(ctx, []byte("empty-bucket"), project.ID)
+			require.NoError(t, err)
+			require.Equal(t, project.ID, bucket.ProjectID)
+		})
+
+		t.Run("invalid name", func(t *testing.T) {
+			for _, name := range []string{"ab", "UpperCase", "-hyphen", "hyphen-", "a..b", "192.168.0.1"} {
+				require.Equal(t, http.StatusBadRequest, createBucket(name), name)
+			}
+		})
+
+		t.Run("duplicate", func(t *testing.T) {
+			require.Equal(t, http.StatusConflict, createBucket("empty-bucket"))
+		})
+
+		t.Run("delete empty", func(t *testing.T) {
+			require.Equal(t, http.StatusOK, deleteBucket("empty-bucket", false))
+
+			_, err := sat.DB.Buckets().GetBucket(ctx, []byte("empty-bucket"), project.ID)
+			require.True(t, storj.ErrBucketNotFound.Has(err))
+
+			require.Equal(t, http.StatusNotFound, deleteBucket("empty-bucket", false))
+		})
+
+		t.Run("delete non-empty", func(t *testing.T) {
+			require.Equal(t, http.StatusCreated, createBucket("full-bucket"))
+
+			obj := metabasetest.RandObjectStream()
+			obj.ProjectID = project.ID
+			obj.BucketName = "full-bucket"
+			metabasetest.CreateObject(ctx, t, sat.Metainfo.Metabase, obj, 1)
+
+			require.Equal(t, http.StatusConflict, deleteBucket("full-bucket", false))
+
+			_, err := sat.DB.Buckets().GetBucket(ctx, []byte("full-bucket"), project.ID)
+			require.NoError(t, err)
+
+			require.Equal(t, http.StatusOK, deleteBucket("full-bucket", true))
+
+			_, err = sat.DB.Buckets().GetBucket(ctx, []byte("full-bucket"), project.ID)
+			require.True(t, storj.ErrBucketNotFound.Has(err))
+
+			empty, err := sat.Metainfo.Service.IsBucketEmpty(ctx, project.ID, []byte("full-bucket"))
+			require.NoError(t, err)
+			require.True(t, empty)
+		})
+	})
+}
+
+func TestListBucketObjects(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		user, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Object Lister",
+			Email:    "objectlister@test.test",
+		}, 1)
+		require.NoError(t, err)
+
+		project, err := sat.AddProject(ctx, user.ID, "objects")
+		require.NoError(t, err)
+
+		_, err = sat.DB.Buckets().CreateBucket(ctx, storj.Bucket{
+			ID:        testrand.UUID(),
+			Name:      "photos",
+			ProjectID: project.ID,
+		})
+		require.NoError(t, err)
+
+		_, err = sat.DB.Buckets().CreateBucket(ctx, storj.Bucket{
+			ID:        testrand.UUID(),
+			Name:      "encrypted",
+			ProjectID: project.ID,
+		})
+		require.NoError(t, err)
+
+		for bucket, keys := range map[string][]string{
+			"photos": {"album/a.jpg", "album/b.jpg", "album/c.jpg", "album/2021/d.jpg", "other.txt"},
+			// encrypted keys are arbitrary bytes which aren't valid UTF-8.
+			"encrypted": {"\xff\xfe/\x80a", "\xff\xfe/\x80b", "\xff\xfe/\xc3"},
+		} {
+			for _, key := range keys {
+				obj := metabasetest.RandObjectStream()
+				obj.ProjectID = project.ID
+				obj.BucketName = bucket
+				obj.ObjectKey = metabase.ObjectKey(key)
+				metabasetest.CreateObject(ctx, t, sat.Metainfo.Metabase, obj, 1)
+			}
+		}
+
+		// we are using full name as a password
+		token, err := sat.API.Console.Service.Token(ctx, console.AuthUser{Email: user.Email, Password: user.FullName})
+		require.NoError(t, err)
+
+		type object struct {
+			Key      string `json:"key"`
+			IsPrefix bool   `json:"isPrefix"`
+		}
+		type page struct {
+			Objects    []object `json:"objects"`
+			NextCursor string   `json:"nextCursor"`
+		}
+
+		encode := base64.RawURLEncoding.EncodeToString
+		decode := func(value string) string {
+			data, err := base64.RawURLEncoding.DecodeString(value)
+			require.NoError(t, err)
+			return string(data)
+		}
+
+		// listObjects returns the page with the keys and the cursor decoded.
+		listObjects := func(bucket, query string) (int, page) {
+			url := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/buckets/" + bucket + "/objects?projectID=" + project.ID.String() + query
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+			require.NoError(t, err)
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   token,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			body, err := ioutil.ReadAll(result.Body)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+
+			var output page
+			if result.StatusCode == http.StatusOK {
+				require.NoError(t, json.Unmarshal(body, &output))
+				for i := range output.Objects {
+					output.Objects[i].Key = decode(output.Objects[i].Key)
+				}
+				output.NextCursor = decode(output.NextCursor)
+			}
+			return result.StatusCode, output
+		}
+
+		t.Run("paging under prefix", func(t *testing.T) {
+			code, first := listObjects("photos", "&prefix="+encode([]byte("album/"))+"&limit=2")
+			require.Equal(t, http.StatusOK, code)
+			require.Equal(t, []object{
+				{Key: "2021/", IsPrefix: true},
+				{Key: "a.jpg"},
+			}, first.Objects)
+			require.Equal(t, "a.jpg", first.NextCursor)
+
+			code, second := listObjects("photos", "&prefix="+encode([]byte("album/"))+"&limit=2&cursor="+encode([]byte(first.NextCursor)))
+			require.Equal(t, http.StatusOK, code)
+			require.Equal(t, []object{
+				{Key: "b.jpg"},
+				{Key: "c.jpg"},
+			}, second.Objects)
+			require.Empty(t, second.NextCursor)
+		})
+
+		t.Run("prefix without delimiter", func(t *testing.T) {
+			code, output := listObjects("photos", "&prefix="+encode([]byte("album")))
+			require.Equal(t, http.StatusOK, code)
+			require.Len(t, output.Objects, 4)
+			require.Empty(t, output.NextCursor)
+		})
+
+		t.Run("root", func(t *testing.T) {
+			code, output := listObjects("photos", "")
+			require.Equal(t, http.StatusOK, code)
+			require.Equal(t, []object{
+				{Key: "album/", IsPrefix: true},
+				{Key: "other.txt"},
+			}, output.Objects)
+		})
+
+		t.Run("limit above max", func(t *testing.T) {
+			code, output := listObjects("photos", "&prefix="+encode([]byte("album/"))+"&limit=100000")
+			require.Equal(t, http.StatusOK, code)
+			require.Len(t, output.Objects, 4)
+		})
+
+		t.Run("invalid limit", func(t *testing.T) {
+			code, _ := listObjects("photos", "&limit=-1")
+			require.Equal(t, http.StatusBadRequest, code)
+
+			code, _ = listObjects("photos", "&limit=abc")
+			require.Equal(t, http.StatusBadRequest, code)
+		})
+
+		t.Run("non UTF-8 keys", func(t *testing.T) {
+			prefix := encode([]byte("\xff\xfe/"))
+
+			var keys []string
+			code, output := listObjects("encrypted", "&prefix="+prefix+"&limit=1")
+			for {
+				require.Equal(t, http.StatusOK, code)
+				require.Len(t, output.Objects, 1)
+				keys = append(keys, output.Objects[0].Key)
+				if output.NextCursor == "" {
+					break
+				}
+				require.Equal(t, output.Objects[0].Key, output.NextCursor)
+				code, output = listObjects("encrypted", "&prefix="+prefix+"&limit=1&cursor="+encode([]byte(output.NextCursor)))
+			}
+			require.Equal(t, []string{"\x80a", "\x80b", "\xc3"}, keys)
+		})
+
+		t.Run("invalid encoding", func(t *testing.T) {
+			code, _ := listObjects("photos", "&prefix=album/")
+			require.Equal(t, http.StatusBadRequest, code)
+
+			code, _ = listObjects("photos", "&cursor=a.jpg")
+			require.Equal(t, http.StatusBadRequest, code)
+		})
+
+		t.Run("missing bucket", func(t *testing.T) {
+			code, _ := listObjects("missing", "")
+			require.Equal(t, http.StatusNotFound, code)
+		})
+	})
+}
+
+func TestObjectMetadataConditional(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		user, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Object Poller",
+			Email:    "objectpoller@test.test",
+		}, 1)
+		require.NoError(t, err)
+
+		project, err := sat.AddProject(ctx, user.ID, "objects")
+		require.NoError(t, err)
+
+		_, err = sat.DB.Buckets().CreateBucket(ctx, storj.Bucket{
+			ID:        testrand.UUID(),
+			Name:      "docs",
+			ProjectID: project.ID,
+		})
+		require.NoError(t, err)
+
+		obj := metabasetest.RandObjectStream()
+		obj.ProjectID = project.ID
+		obj.BucketName = "docs"
+		obj.ObjectKey = "reports/2021.pdf"
+		metabasetest.CreateObject(ctx, t, sat.Metainfo.Metabase, obj, 1)
+
+		// we are using full name as a password
+		token, err := sat.API.Console.Service.Token(ctx, console.AuthUser{Email: user.Email, Password: user.FullName})
+		require.NoError(t, err)
+
+		getMetadata := func(key string, header http.Header) *http.Response {
+			url := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/buckets/docs/objects/" + base64.RawURLEncoding.EncodeToString([]byte(key)) + "?projectID=" + project.ID.String()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+			require.NoError(t, err)
+			for name, values := range header {
+				req.Header[name] = values
+			}
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   token,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+			return result
+		}
+
+		result := getMetadata("reports/2021.pdf", nil)
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		etag := result.Header.Get("ETag")
+		lastModified := result.Header.Get("Last-Modified")
+		require.NotEmpty(t, etag)
+		require.NotEmpty(t, lastModified)
+
+		t.Run("unchanged", func(t *testing.T) {
+			result := getMetadata("reports/2021.pdf", http.Header{"If-None-Match": {etag}})
+			require.Equal(t, http.StatusNotModified, result.StatusCode)
+			require.Equal(t, etag, result.Header.Get("ETag"))
+
+			result = getMetadata("reports/2021.pdf", http.Header{"If-Modified-Since": {lastModified}})
+			require.Equal(t, http.StatusNotModified, result.StatusCode)
+
+			result = getMetadata("reports/2021.pdf", http.Header{"If-None-Match": {`"other", W/` + etag}})
+			require.Equal(t, http.StatusNotModified, result.StatusCode)
+		})
+
+		t.Run("unknown etag", func(t *testing.T) {
+			result := getMetadata("reports/2021.pdf", http.Header{"If-None-Match": {`"other"`}})
+			require.Equal(t, http.StatusOK, result.StatusCode)
+
+			// If-Modified-Since is ignored when If-None-Match is present.
+			result = getMetadata("reports/2021.pdf", http.Header{
+				"If-None-Match":     {`"other"`},
+				"If-Modified-Since": {lastModified},
+			})
+			require.Equal(t, http.StatusOK, result.StatusCode)
+		})
+
+		t.Run("changed", func(t *testing.T) {
+			overwrite := obj
+			overwrite.Version = obj.Version + 1
+			overwrite.StreamID = testrand.UUID()
+			metabasetest.CreateObject(ctx, t, sat.Metainfo.Metabase, overwrite, 1)
+
+			result := getMetadata("reports/2021.pdf", http.Header{"If-None-Match": {etag}})
+			require.Equal(t, http.StatusOK, result.StatusCode)
+			require.NotEqual(t, etag, result.Header.Get("ETag"))
+
+			result = getMetadata("reports/2021.pdf", http.Header{"If-Modified-Since": {time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)}})
+			require.Equal(t, http.StatusOK, result.StatusCode)
+		})
+
+		t.Run("missing object", func(t *testing.T) {
+			result := getMetadata("reports/missing.pdf", nil)
+			require.Equal(t, http.StatusNotFound, result.StatusCode)
+		})
+	})
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
+
+	"storj.io/storj/satellite/console"
 )
 
 var (
@@ -28,6 +30,10 @@ func serveCustomJSONError(log *zap.Logger, w http.ResponseWriter, status int, er
 		zap.String("message", msg),
 		zap.Error(err),
 	}
+	// the request ID is echoed in the response headers before any handler runs.
+	if requestID := w.Header().Get(console.RequestIDHeader); requestID != "" {
+		fields = append(fields, zap.String("requestID", requestID))
+	}
 	switch status {
 	case http.StatusNoContent:
 		return
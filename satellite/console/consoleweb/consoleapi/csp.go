@@ -0,0 +1,96 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package consoleapi
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+)
+
+// maxCSPReportSize is the maximum size of a CSP violation report body.
+const maxCSPReportSize = 64 * 1024
+
+var (
+	// ErrCSPAPI - console CSP api error type.
+	ErrCSPAPI = errs.Class("consoleapi csp")
+)
+
+// CSP is an api controller that receives Content Security Policy violation reports.
+type CSP struct {
+	log *zap.Logger
+}
+
+// NewCSP is a constructor for api CSP controller.
+func NewCSP(log *zap.Logger) *CSP {
+	return &CSP{
+		log: log,
+	}
+}
+
+// cspReport is the body of an application/csp-report request sent by browsers.
+type cspReport struct {
+	Report struct {
+		DocumentURI        string `json:"document-uri"`
+		Referrer           string `json:"referrer"`
+		BlockedURI         string `json:"blocked-uri"`
+		ViolatedDirective  string `json:"violated-directive"`
+		EffectiveDirective string `json:"effective-directive"`
+		OriginalPolicy     string `json:"original-policy"`
+		Disposition        string `json:"disposition"`
+		SourceFile         string `json:"source-file"`
+		LineNumber         int    `json:"line-number"`
+		ColumnNumber       int    `json:"column-number"`
+		StatusCode         int    `json:"status-code"`
+	} `json:"csp-report"`
+}
+
+// Report logs a Content Security Policy violation report.
+func (c *CSP) Report(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxCSPReportSize+1))
+	if err != nil {
+		c.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(body) > maxCSPReportSize {
+		err = ErrCSPAPI.New("report is too large")
+		c.serveJSONError(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+
+	var report cspReport
+	if err = json.Unmarshal(body, &report); err != nil {
+		c.serveJSONError(w, http.StatusBadRequest, ErrCSPAPI.Wrap(err))
+		return
+	}
+
+	violation := report.Report
+	c.log.Warn("content security policy violation",
+		zap.String("document-uri", violation.DocumentURI),
+		zap.String("referrer", violation.Referrer),
+		zap.String("blocked-uri", violation.BlockedURI),
+		zap.String("violated-directive", violation.ViolatedDirective),
+		zap.String("effective-directive", violation.EffectiveDirective),
+		zap.String("disposition", violation.Disposition),
+		zap.String("source-file", violation.SourceFile),
+		zap.Int("line-number", violation.LineNumber),
+		zap.Int("column-number", violation.ColumnNumber),
+		zap.Int("status-code", violation.StatusCode),
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveJSONError writes JSON error to response output stream.
+func (c *CSP) serveJSONError(w http.ResponseWriter, status int, err error) {
+	serveJSONError(c.log, w, status, err)
+}
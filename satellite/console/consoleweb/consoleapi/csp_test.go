@@ -0,0 +1,63 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package consoleapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"storj.io/storj/satellite/console/consoleweb/consoleapi"
+)
+
+func TestCSPReport(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	csp := consoleapi.NewCSP(zap.New(core))
+
+	report := `{
+		"csp-report": {
+			"document-uri": "https://satellite.test/projects",
+			"referrer": "",
+			"violated-directive": "script-src-elem",
+			"effective-directive": "script-src-elem",
+			"original-policy": "default-src 'self'; report-uri /api/v0/csp-report",
+			"disposition": "enforce",
+			"blocked-uri": "https://evil.test/script.js",
+			"line-number": 12,
+			"column-number": 3,
+			"source-file": "https://satellite.test/static/app.js",
+			"status-code": 200
+		}
+	}`
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/csp-report", strings.NewReader(report))
+	req.Header.Set("Content-Type", "application/csp-report")
+	rec := httptest.NewRecorder()
+	csp.Report(rec, req)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	entries := logs.TakeAll()
+	require.Len(t, entries, 1)
+	require.Equal(t, "content security policy violation", entries[0].Message)
+	require.Equal(t, zap.WarnLevel, entries[0].Level)
+
+	fields := entries[0].ContextMap()
+	require.Equal(t, "https://satellite.test/projects", fields["document-uri"])
+	require.Equal(t, "https://evil.test/script.js", fields["blocked-uri"])
+	require.Equal(t, "script-src-elem", fields["violated-directive"])
+	require.Equal(t, "https://satellite.test/static/app.js", fields["source-file"])
+	require.Equal(t, int64(12), fields["line-number"])
+
+	// malformed reports are rejected without being logged as violations
+	req = httptest.NewRequest(http.MethodPost, "/api/v0/csp-report", strings.NewReader("{"))
+	rec = httptest.NewRecorder()
+	csp.Report(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Zero(t, logs.FilterMessage("content security policy violation").Len())
+}
@@ -17,6 +17,7 @@ import (
 	"go.uber.org/zap"
 
 	"storj.io/storj/satellite/console"
+	"storj.io/storj/satellite/payments"
 )
 
 var (
@@ -27,15 +28,17 @@ var (
 
 // Payments is an api controller that exposes all payment related functionality.
 type Payments struct {
-	log     *zap.Logger
-	service *console.Service
+	log      *zap.Logger
+	service  *console.Service
+	webhooks payments.Webhooks
 }
 
 // NewPayments is a constructor for api payments controller.
-func NewPayments(log *zap.Logger, service *console.Service) *Payments {
+func NewPayments(log *zap.Logger, service *console.Service, webhooks payments.Webhooks) *Payments {
 	return &Payments{
-		log:     log,
-		service: service,
+		log:      log,
+		service:  service,
+		webhooks: webhooks,
 	}
 }
 
@@ -106,15 +109,22 @@ func (p *Payments) ProjectsCharges(w http.ResponseWriter, r *http.Request) {
 
 	charges, err := p.service.Payments().ProjectsCharges(ctx, since, before)
 	if err != nil {
-		if console.ErrUnauthorized.Has(err) {
+		switch {
+		case console.ErrUnauthorized.Has(err):
 			p.serveJSONError(w, http.StatusUnauthorized, err)
-			return
+		case payments.ErrUnavailable.Has(err):
+			serveCustomJSONError(p.log, w, http.StatusServiceUnavailable, err, "billing is temporarily unavailable, please try again later")
+		default:
+			p.serveJSONError(w, http.StatusInternalServerError, err)
 		}
-
-		p.serveJSONError(w, http.StatusInternalServerError, err)
 		return
 	}
 
+	// a month without usage is reported as an empty list rather than null.
+	if charges == nil {
+		charges = []payments.ProjectCharge{}
+	}
+
 	err = json.NewEncoder(w).Encode(charges)
 	if err != nil {
 		p.log.Error("failed to write json response", zap.Error(ErrPaymentsAPI.Wrap(err)))
@@ -135,13 +145,18 @@ func (p *Payments) AddCreditCard(w http.ResponseWriter, r *http.Request) {
 
 	token := string(bodyBytes)
 
-	err = p.service.Payments().AddCreditCard(ctx, token)
+	err = p.service.Payments().AddCreditCard(ctx, token, r.Header.Get("Idempotency-Key"))
 	if err != nil {
 		if console.ErrUnauthorized.Has(err) {
 			p.serveJSONError(w, http.StatusUnauthorized, err)
 			return
 		}
 
+		if console.ErrValidation.Has(err) {
+			p.serveJSONError(w, http.StatusBadRequest, err)
+			return
+		}
+
 		p.serveJSONError(w, http.StatusInternalServerError, err)
 		return
 	}
@@ -286,6 +301,7 @@ func (p *Payments) TokenDeposit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var responseData struct {
+		ID          string    `json:"id"`
 		Address     string    `json:"address"`
 		Amount      float64   `json:"amount"`
 		TokenAmount string    `json:"tokenAmount"`
@@ -295,6 +311,7 @@ func (p *Payments) TokenDeposit(w http.ResponseWriter, r *http.Request) {
 		ExpiresAt   time.Time `json:"expires"`
 	}
 
+	responseData.ID = tx.ID.String()
 	responseData.Address = tx.Address
 	responseData.Amount = float64(requestData.Amount) / 100
 	responseData.TokenAmount = tx.Amount.String()
@@ -309,6 +326,97 @@ func (p *Payments) TokenDeposit(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// TokenDepositStatus returns whether a deposit transaction is still pending,
+// has been confirmed or has failed, together with its confirmation count.
+func (p *Payments) TokenDepositStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok || id == "" {
+		p.serveJSONError(w, http.StatusBadRequest, errs.New("missing deposit id route param"))
+		return
+	}
+
+	tx, err := p.service.Payments().GetTokenDeposit(ctx, id)
+	if err != nil {
+		if console.ErrUnauthorized.Has(err) {
+			p.serveJSONError(w, http.StatusUnauthorized, err)
+			return
+		}
+
+		if payments.ErrTransactionNotFound.Has(err) {
+			p.serveJSONError(w, http.StatusNotFound, err)
+			return
+		}
+
+		p.serveJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var responseData struct {
+		ID            string    `json:"id"`
+		Status        string    `json:"status"`
+		Confirmations int       `json:"confirmations"`
+		TokenAmount   string    `json:"tokenAmount"`
+		Received      string    `json:"received"`
+		ExpiresAt     time.Time `json:"expires"`
+	}
+
+	responseData.ID = tx.ID.String()
+	responseData.Status = depositStatus(tx.Status)
+	responseData.Confirmations = tx.Confirmations
+	responseData.TokenAmount = tx.Amount.String()
+	responseData.Received = tx.Received.String()
+	responseData.ExpiresAt = tx.ExpiresAt
+
+	err = json.NewEncoder(w).Encode(responseData)
+	if err != nil {
+		p.log.Error("failed to write json token deposit status response", zap.Error(ErrPaymentsAPI.Wrap(err)))
+	}
+}
+
+// depositStatus returns the status reported to the client for a deposit transaction.
+func depositStatus(status payments.TransactionStatus) string {
+	switch status {
+	case payments.TransactionStatusPending:
+		return "pending"
+	case payments.TransactionStatusPaid, payments.TransactionStatusCompleted:
+		return "confirmed"
+	default:
+		return "failed"
+	}
+}
+
+// Webhook handles the events sent by the payment provider.
+// Events whose signature doesn't match the configured secret are rejected.
+func (p *Payments) Webhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	// limit the size of the body to prevent excessive memory usage
+	payload, err := ioutil.ReadAll(io.LimitReader(r.Body, 1*1024*1024))
+	if err != nil {
+		p.serveJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = p.webhooks.HandleEvent(ctx, payload, r.Header.Get("Stripe-Signature"))
+	if err != nil {
+		if payments.ErrInvalidWebhook.Has(err) {
+			p.serveJSONError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		p.serveJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+}
+
 // ApplyCouponCode applies a coupon code to the user's account.
 func (p *Payments) ApplyCouponCode(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -0,0 +1,357 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package consoleapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stripe/stripe-go/v72/webhook"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/testcontext"
+	"storj.io/common/uuid"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/accounting"
+	"storj.io/storj/satellite/console"
+	"storj.io/storj/satellite/payments/coinpayments"
+	"storj.io/storj/satellite/payments/stripecoinpayments"
+)
+
+func TestAddCreditCardIdempotency(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		user, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Card Holder",
+			Email:    "cardholder@test.test",
+		}, 1)
+		require.NoError(t, err)
+
+		// we are using full name as a password
+		token, err := sat.API.Console.Service.Token(ctx, console.AuthUser{Email: user.Email, Password: user.FullName})
+		require.NoError(t, err)
+
+		addCard := func(cardToken, idempotencyKey string) int {
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+				"http://"+sat.API.Console.Listener.Addr().String()+"/api/v0/payments/cards",
+				strings.NewReader(cardToken))
+			require.NoError(t, err)
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   token,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+			req.Header.Set("Idempotency-Key", idempotencyKey)
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+
+			return result.StatusCode
+		}
+
+		countCards := func() int {
+			authCtx, err := sat.AuthenticatedContext(ctx, user.ID)
+			require.NoError(t, err)
+
+			cards, err := sat.API.Console.Service.Payments().ListCreditCards(authCtx)
+			require.NoError(t, err)
+			return len(cards)
+		}
+
+		// retrying with the same key doesn't attach the card again.
+		require.Equal(t, http.StatusOK, addCard("test-cc-token", "key-1"))
+		require.Equal(t, http.StatusOK, addCard("test-cc-token", "key-1"))
+		require.Equal(t, 1, countCards())
+
+		// a different key is a different request.
+		require.Equal(t, http.StatusOK, addCard("test-cc-token", "key-2"))
+		require.Equal(t, 2, countCards())
+
+		// reusing a key for a different card is rejected.
+		require.Equal(t, http.StatusBadRequest, addCard("other-cc-token", "key-1"))
+		require.Equal(t, 2, countCards())
+	})
+}
+
+func TestTokenDepositStatus(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		transactions := sat.DB.StripeCoinPayments().Transactions()
+
+		user, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Token Depositor",
+			Email:    "depositor@test.test",
+		}, 1)
+		require.NoError(t, err)
+
+		// we are using full name as a password
+		token, err := sat.API.Console.Service.Token(ctx, console.AuthUser{Email: user.Email, Password: user.FullName})
+		require.NoError(t, err)
+
+		// the deposit as it is stored when created through coinpayments.
+		amount, ok := new(big.Float).SetPrec(1000).SetString("10")
+		require.True(t, ok)
+		_, err = transactions.Insert(ctx, stripecoinpayments.Transaction{
+			ID:        "deposit-id",
+			AccountID: user.ID,
+			Address:   "address",
+			Amount:    *amount,
+			Status:    coinpayments.StatusPending,
+			Key:       "key",
+			Timeout:   time.Hour,
+		})
+		require.NoError(t, err)
+		require.NoError(t, transactions.LockRate(ctx, "deposit-id", big.NewFloat(1)))
+
+		type depositStatus struct {
+			ID            string `json:"id"`
+			Status        string `json:"status"`
+			Confirmations int    `json:"confirmations"`
+		}
+
+		getStatus := func(id string) (int, depositStatus) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+				"http://"+sat.API.Console.Listener.Addr().String()+"/api/v0/payments/tokens/deposit/"+id, nil)
+			require.NoError(t, err)
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   token,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, result.Body.Close()) }()
+
+			var status depositStatus
+			if result.StatusCode == http.StatusOK {
+				require.NoError(t, json.NewDecoder(result.Body).Decode(&status))
+			}
+			return result.StatusCode, status
+		}
+
+		// simulates the update loop receiving the transaction info from coinpayments.
+		confirm := func(status coinpayments.Status, confirmations int) {
+			err := transactions.Update(ctx, []stripecoinpayments.TransactionUpdate{{
+				TransactionID: "deposit-id",
+				Status:        status,
+				Received:      *amount,
+				Confirmations: confirmations,
+			}}, nil)
+			require.NoError(t, err)
+		}
+
+		code, status := getStatus("deposit-id")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, depositStatus{ID: "deposit-id", Status: "pending"}, status)
+
+		confirm(coinpayments.StatusPending, 2)
+		code, status = getStatus("deposit-id")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, depositStatus{ID: "deposit-id", Status: "pending", Confirmations: 2}, status)
+
+		confirm(coinpayments.StatusReceived, 5)
+		code, status = getStatus("deposit-id")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, depositStatus{ID: "deposit-id", Status: "confirmed", Confirmations: 5}, status)
+
+		code, _ = getStatus("unknown-id")
+		require.Equal(t, http.StatusNotFound, code)
+	})
+}
+
+func TestWebhookSignature(t *testing.T) {
+	const secret = "whsec_test"
+
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Payments.StripeCoinPayments.StripeWebhookSecret = secret
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		sendEvent := func(payload []byte, signature string) int {
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+				"http://"+sat.API.Console.Listener.Addr().String()+"/api/v0/payments/webhook",
+				bytes.NewReader(payload))
+			require.NoError(t, err)
+			if signature != "" {
+				req.Header.Set("Stripe-Signature", signature)
+			}
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+
+			return result.StatusCode
+		}
+
+		sign := func(payload []byte, secret string) string {
+			now := time.Now()
+			return fmt.Sprintf("t=%d,v1=%x", now.Unix(), webhook.ComputeSignature(now, payload, secret))
+		}
+
+		user, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Paying User",
+			Email:    "paying@test.test",
+		}, 1)
+		require.NoError(t, err)
+		require.False(t, user.PaidTier)
+
+		customerID, err := sat.DB.StripeCoinPayments().Customers().GetCustomerID(ctx, user.ID)
+		require.NoError(t, err)
+
+		payload := []byte(`{"id":"evt_1","object":"event","type":"invoice.paid","data":{"object":{"id":"in_1","object":"invoice","amount_paid":1000,"customer":"` + customerID + `"}}}`)
+		detached := []byte(`{"id":"evt_2","object":"event","type":"payment_method.detached","data":{"object":{"id":"pm_1","object":"payment_method"},"previous_attributes":{"customer":"` + customerID + `"}}}`)
+		noCustomer := []byte(`{"id":"evt_3","object":"event","type":"invoice.paid","data":{"object":{"id":"in_2","object":"invoice","amount_paid":1000}}}`)
+		unknown := []byte(`{"id":"evt_4","object":"event","type":"customer.created","data":{"object":{"id":"cus_1","object":"customer"}}}`)
+		tampered := bytes.Replace(payload, []byte("1000"), []byte("9000"), 1)
+
+		require.Equal(t, http.StatusOK, sendEvent(payload, sign(payload, secret)))
+
+		user, err = sat.DB.Console().Users().Get(ctx, user.ID)
+		require.NoError(t, err)
+		require.True(t, user.PaidTier)
+
+		require.Equal(t, http.StatusOK, sendEvent(detached, sign(detached, secret)))
+		require.Equal(t, http.StatusOK, sendEvent(unknown, sign(unknown, secret)))
+		require.Equal(t, http.StatusBadRequest, sendEvent(noCustomer, sign(noCustomer, secret)))
+
+		require.Equal(t, http.StatusBadRequest, sendEvent(tampered, sign(payload, secret)))
+		require.Equal(t, http.StatusBadRequest, sendEvent(payload, sign(payload, "whsec_other")))
+		require.Equal(t, http.StatusBadRequest, sendEvent(payload, ""))
+	})
+}
+
+// failingUsageDB is a satellite.DB whose project accounting fails while failing is set.
+type failingUsageDB struct {
+	satellite.DB
+	failing *int32
+}
+
+func (db failingUsageDB) ProjectAccounting() accounting.ProjectAccounting {
+	return failingProjectAccounting{ProjectAccounting: db.DB.ProjectAccounting(), failing: db.failing}
+}
+
+type failingProjectAccounting struct {
+	accounting.ProjectAccounting
+	failing *int32
+}
+
+func (usage failingProjectAccounting) GetProjectTotal(ctx context.Context, projectID uuid.UUID, since, before time.Time) (*accounting.ProjectUsage, error) {
+	if atomic.LoadInt32(usage.failing) != 0 {
+		return nil, errs.New("connection refused")
+	}
+	return usage.ProjectAccounting.GetProjectTotal(ctx, projectID, since, before)
+}
+
+func TestProjectsCharges(t *testing.T) {
+	var failing int32
+
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			SatelliteDB: func(log *zap.Logger, index int, db satellite.DB) (satellite.DB, error) {
+				return failingUsageDB{DB: db, failing: &failing}, nil
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		user, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Charged User",
+			Email:    "charged@test.test",
+		}, 1)
+		require.NoError(t, err)
+
+		// we are using full name as a password
+		token, err := sat.API.Console.Service.Token(ctx, console.AuthUser{Email: user.Email, Password: user.FullName})
+		require.NoError(t, err)
+
+		getCharges := func() (int, []byte) {
+			now := time.Now()
+			since := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+				"http://"+sat.API.Console.Listener.Addr().String()+"/api/v0/payments/account/charges"+
+					"?from="+strconv.FormatInt(since.Unix(), 10)+"&to="+strconv.FormatInt(now.Unix(), 10), nil)
+			require.NoError(t, err)
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   token,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, result.Body.Close()) }()
+
+			var body bytes.Buffer
+			_, err = body.ReadFrom(result.Body)
+			require.NoError(t, err)
+			return result.StatusCode, body.Bytes()
+		}
+
+		t.Run("no projects", func(t *testing.T) {
+			code, body := getCharges()
+			require.Equal(t, http.StatusOK, code)
+			require.JSONEq(t, `[]`, string(body))
+		})
+
+		_, err = sat.AddProject(ctx, user.ID, "charged project")
+		require.NoError(t, err)
+
+		t.Run("no usage this month", func(t *testing.T) {
+			code, body := getCharges()
+			require.Equal(t, http.StatusOK, code)
+
+			var charges []map[string]interface{}
+			require.NoError(t, json.Unmarshal(body, &charges))
+			require.Len(t, charges, 1)
+			require.EqualValues(t, 0, charges[0]["storagePrice"])
+			require.EqualValues(t, 0, charges[0]["egressPrice"])
+			require.EqualValues(t, 0, charges[0]["objectPrice"])
+		})
+
+		t.Run("usage unavailable", func(t *testing.T) {
+			atomic.StoreInt32(&failing, 1)
+			defer atomic.StoreInt32(&failing, 0)
+
+			// the usage is read from the satellite database, so failing to
+			// read it is an internal error, not an outage of the billing backend.
+			code, body := getCharges()
+			require.Equal(t, http.StatusInternalServerError, code)
+
+			var response struct {
+				Error string `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(body, &response))
+			require.NotEmpty(t, response.Error)
+		})
+	})
+}
@@ -6,6 +6,8 @@ package consoleapi
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/zeebo/errs"
@@ -25,13 +27,16 @@ var (
 type UsageLimits struct {
 	log     *zap.Logger
 	service *console.Service
+	cache   *usageLimitsCache
 }
 
 // NewUsageLimits is a constructor for api usage and limits controller.
-func NewUsageLimits(log *zap.Logger, service *console.Service) *UsageLimits {
+// Usage and limits are cached for cacheTTL, zero disables the cache.
+func NewUsageLimits(log *zap.Logger, service *console.Service, cacheTTL time.Duration) *UsageLimits {
 	return &UsageLimits{
 		log:     log,
 		service: service,
+		cache:   newUsageLimitsCache(cacheTTL),
 	}
 }
 
@@ -57,7 +62,59 @@ func (ul *UsageLimits) ProjectUsageLimits(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	usageLimits, err := ul.service.GetProjectUsageLimits(ctx, projectID)
+	// the limits are always checked, so that updated limits invalidate the cache.
+	limits, err := ul.service.GetProjectLimits(ctx, projectID)
+	if err != nil {
+		ul.serveProjectUsageLimitsError(w, err)
+		return
+	}
+
+	auth, err := console.GetAuth(ctx)
+	if err != nil {
+		ul.serveProjectUsageLimitsError(w, err)
+		return
+	}
+
+	cacheKey := usageLimitsCacheKey{UserID: auth.User.ID, ProjectID: projectID}
+	usageLimits, ok := ul.cache.Get(cacheKey, limits)
+	if !ok {
+		usageLimits, err = ul.service.GetProjectUsageLimits(ctx, projectID)
+		if err != nil {
+			ul.serveProjectUsageLimitsError(w, err)
+			return
+		}
+		ul.cache.Set(cacheKey, usageLimits)
+	}
+
+	err = json.NewEncoder(w).Encode(usageLimits)
+	if err != nil {
+		ul.log.Error("error encoding project usage limits", zap.Error(ErrUsageLimitsAPI.Wrap(err)))
+	}
+}
+
+// ProjectUsageAlerts returns the usage limits of the project that are close to being hit.
+func (ul *UsageLimits) ProjectUsageAlerts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var ok bool
+	var idParam string
+
+	if idParam, ok = mux.Vars(r)["id"]; !ok {
+		ul.serveJSONError(w, http.StatusBadRequest, errs.New("missing project id route param"))
+		return
+	}
+
+	projectID, err := uuid.FromString(idParam)
+	if err != nil {
+		ul.serveJSONError(w, http.StatusBadRequest, errs.New("invalid project id: %v", err))
+		return
+	}
+
+	alerts, err := ul.service.GetProjectUsageAlerts(ctx, projectID)
 	if err != nil {
 		switch {
 		case console.ErrUnauthorized.Has(err):
@@ -72,9 +129,9 @@ func (ul *UsageLimits) ProjectUsageLimits(w http.ResponseWriter, r *http.Request
 		}
 	}
 
-	err = json.NewEncoder(w).Encode(usageLimits)
+	err = json.NewEncoder(w).Encode(alerts)
 	if err != nil {
-		ul.log.Error("error encoding project usage limits", zap.Error(ErrUsageLimitsAPI.Wrap(err)))
+		ul.log.Error("error encoding project usage alerts", zap.Error(ErrUsageLimitsAPI.Wrap(err)))
 	}
 }
 
@@ -84,20 +141,132 @@ func (ul *UsageLimits) TotalUsageLimits(w http.ResponseWriter, r *http.Request)
 	var err error
 	defer mon.Task()(&ctx)(&err)
 
-	usageLimits, err := ul.service.GetTotalUsageLimits(ctx)
+	// the limits are always checked, so that updated limits invalidate the cache.
+	limits, err := ul.service.GetTotalLimits(ctx)
 	if err != nil {
-		if console.ErrUnauthorized.Has(err) {
-			ul.serveJSONError(w, http.StatusUnauthorized, err)
+		ul.serveTotalUsageLimitsError(w, err)
+		return
+	}
+
+	auth, err := console.GetAuth(ctx)
+	if err != nil {
+		ul.serveTotalUsageLimitsError(w, err)
+		return
+	}
+
+	cacheKey := usageLimitsCacheKey{UserID: auth.User.ID}
+	usageLimits, ok := ul.cache.Get(cacheKey, limits)
+	if !ok {
+		usageLimits, err = ul.service.GetTotalUsageLimits(ctx)
+		if err != nil {
+			ul.serveTotalUsageLimitsError(w, err)
 			return
 		}
+		ul.cache.Set(cacheKey, usageLimits)
+	}
+
+	err = json.NewEncoder(w).Encode(usageLimits)
+	if err != nil {
+		ul.log.Error("error encoding project usage limits", zap.Error(ErrUsageLimitsAPI.Wrap(err)))
+	}
+}
 
+// serveProjectUsageLimitsError writes the error of getting the usage and limits of a project.
+func (ul *UsageLimits) serveProjectUsageLimitsError(w http.ResponseWriter, err error) {
+	switch {
+	case console.ErrUnauthorized.Has(err), console.ErrNoMembership.Has(err):
+		ul.serveJSONError(w, http.StatusUnauthorized, err)
+	case accounting.ErrInvalidArgument.Has(err):
+		ul.serveJSONError(w, http.StatusBadRequest, err)
+	default:
 		ul.serveJSONError(w, http.StatusInternalServerError, err)
+	}
+}
+
+// serveTotalUsageLimitsError writes the error of getting the total usage and limits.
+func (ul *UsageLimits) serveTotalUsageLimitsError(w http.ResponseWriter, err error) {
+	if console.ErrUnauthorized.Has(err) {
+		ul.serveJSONError(w, http.StatusUnauthorized, err)
 		return
 	}
 
-	err = json.NewEncoder(w).Encode(usageLimits)
+	ul.serveJSONError(w, http.StatusInternalServerError, err)
+}
+
+// usageSeriesIntervals are the allowed intervals of a project usage series.
+var usageSeriesIntervals = map[string]time.Duration{
+	"hour": time.Hour,
+	"day":  24 * time.Hour,
+}
+
+// ProjectUsageSeries returns storage and egress of a project for every
+// interval between the since and before unix timestamps.
+func (ul *UsageLimits) ProjectUsageSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var ok bool
+	var idParam string
+
+	if idParam, ok = mux.Vars(r)["id"]; !ok {
+		ul.serveJSONError(w, http.StatusBadRequest, errs.New("missing project id route param"))
+		return
+	}
+
+	projectID, err := uuid.FromString(idParam)
 	if err != nil {
-		ul.log.Error("error encoding project usage limits", zap.Error(ErrUsageLimitsAPI.Wrap(err)))
+		ul.serveJSONError(w, http.StatusBadRequest, errs.New("invalid project id: %v", err))
+		return
+	}
+
+	query := r.URL.Query()
+
+	sinceStamp, err := strconv.ParseInt(query.Get("since"), 10, 64)
+	if err != nil {
+		ul.serveJSONError(w, http.StatusBadRequest, errs.New("invalid since: %v", err))
+		return
+	}
+
+	beforeStamp, err := strconv.ParseInt(query.Get("before"), 10, 64)
+	if err != nil {
+		ul.serveJSONError(w, http.StatusBadRequest, errs.New("invalid before: %v", err))
+		return
+	}
+
+	intervalParam := query.Get("interval")
+	if intervalParam == "" {
+		intervalParam = "day"
+	}
+	interval, ok := usageSeriesIntervals[intervalParam]
+	if !ok {
+		ul.serveJSONError(w, http.StatusBadRequest, errs.New("invalid interval: %q", intervalParam))
+		return
+	}
+
+	since := time.Unix(sinceStamp, 0).UTC()
+	before := time.Unix(beforeStamp, 0).UTC()
+
+	series, err := ul.service.GetProjectUsageSeries(ctx, projectID, since, before, interval)
+	if err != nil {
+		switch {
+		case console.ErrUnauthorized.Has(err), console.ErrNoMembership.Has(err):
+			ul.serveJSONError(w, http.StatusUnauthorized, err)
+			return
+		case console.ErrValidation.Has(err):
+			ul.serveJSONError(w, http.StatusBadRequest, err)
+			return
+		default:
+			ul.serveJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	err = json.NewEncoder(w).Encode(series)
+	if err != nil {
+		ul.log.Error("error encoding project usage series", zap.Error(ErrUsageLimitsAPI.Wrap(err)))
 	}
 }
 
@@ -4,18 +4,24 @@
 package consoleapi_test
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"storj.io/common/pb"
 	"storj.io/common/testcontext"
+	"storj.io/common/uuid"
 	"storj.io/storj/private/testplanet"
 	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/accounting"
 	"storj.io/storj/satellite/console"
 )
 
@@ -113,3 +119,328 @@ func Test_TotalUsageLimits(t *testing.T) {
 		}()
 	})
 }
+
+func TestProjectUsageSeries(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		user, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Usage Series Test",
+			Email:    "series@test.test",
+		}, 1)
+		require.NoError(t, err)
+
+		project, err := sat.AddProject(ctx, user.ID, "series")
+		require.NoError(t, err)
+
+		day := 24 * time.Hour
+		start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+		tallies := []accounting.BucketStorageTally{
+			{BucketName: "alpha", IntervalStart: start.Add(time.Hour), TotalBytes: 10},
+			{BucketName: "beta", IntervalStart: start.Add(3 * time.Hour), TotalBytes: 5},
+			{BucketName: "alpha", IntervalStart: start.Add(5 * time.Hour), TotalBytes: 20},
+			{BucketName: "alpha", IntervalStart: start.Add(2*day + time.Hour), TotalBytes: 30},
+		}
+		for _, tally := range tallies {
+			tally.ProjectID = project.ID
+			require.NoError(t, sat.DB.ProjectAccounting().CreateStorageTally(ctx, tally))
+		}
+
+		require.NoError(t, sat.DB.Orders().UpdateBucketBandwidthSettle(ctx, project.ID, []byte("alpha"), pb.PieceAction_GET, 100, start.Add(2*time.Hour)))
+		require.NoError(t, sat.DB.Orders().UpdateBucketBandwidthSettle(ctx, project.ID, []byte("beta"), pb.PieceAction_GET, 50, start.Add(2*day)))
+		require.NoError(t, sat.DB.Orders().UpdateBucketBandwidthSettle(ctx, project.ID, []byte("alpha"), pb.PieceAction_PUT, 1000, start.Add(2*day)))
+
+		// we are using full name as a password
+		token, err := sat.API.Console.Service.Token(ctx, console.AuthUser{Email: user.Email, Password: user.FullName})
+		require.NoError(t, err)
+
+		getSeries := func(since, before time.Time, interval string) (int, *accounting.ProjectUsageSeries) {
+			url := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/projects/" + project.ID.String() + "/usage-series" +
+				"?since=" + strconv.FormatInt(since.Unix(), 10) +
+				"&before=" + strconv.FormatInt(before.Unix(), 10) +
+				"&interval=" + interval
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			require.NoError(t, err)
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   token,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, result.Body.Close()) }()
+
+			if result.StatusCode != http.StatusOK {
+				return result.StatusCode, nil
+			}
+
+			var series accounting.ProjectUsageSeries
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&series))
+			return result.StatusCode, &series
+		}
+
+		t.Run("daily", func(t *testing.T) {
+			// since is not aligned to the interval, the series starts at the beginning of its day.
+			status, series := getSeries(start.Add(12*time.Hour), start.Add(3*day), "day")
+			require.Equal(t, http.StatusOK, status)
+
+			require.Len(t, series.Storage, 3)
+			require.Len(t, series.Egress, 3)
+			for i, expected := range []int64{25, 0, 30} {
+				require.True(t, start.Add(time.Duration(i)*day).Equal(series.Storage[i].Timestamp))
+				require.Equal(t, expected, series.Storage[i].Value, i)
+			}
+			for i, expected := range []int64{100, 0, 50} {
+				require.True(t, start.Add(time.Duration(i)*day).Equal(series.Egress[i].Timestamp))
+				require.Equal(t, expected, series.Egress[i].Value, i)
+			}
+		})
+
+		t.Run("hourly", func(t *testing.T) {
+			status, series := getSeries(start, start.Add(6*time.Hour), "hour")
+			require.Equal(t, http.StatusOK, status)
+
+			require.Len(t, series.Storage, 6)
+			require.Equal(t, []int64{0, 10, 0, 5, 0, 20}, []int64{
+				series.Storage[0].Value, series.Storage[1].Value, series.Storage[2].Value,
+				series.Storage[3].Value, series.Storage[4].Value, series.Storage[5].Value,
+			})
+			require.EqualValues(t, 100, series.Egress[2].Value)
+		})
+
+		t.Run("capped", func(t *testing.T) {
+			status, series := getSeries(start.Add(-1000*day), start.Add(3*day), "day")
+			require.Equal(t, http.StatusOK, status)
+			require.Len(t, series.Storage, console.MaxUsageSeriesPoints)
+			require.Len(t, series.Egress, console.MaxUsageSeriesPoints)
+			require.EqualValues(t, 30, series.Storage[console.MaxUsageSeriesPoints-1].Value)
+		})
+
+		t.Run("invalid", func(t *testing.T) {
+			status, _ := getSeries(start, start.Add(3*day), "week")
+			require.Equal(t, http.StatusBadRequest, status)
+
+			status, _ = getSeries(start.Add(3*day), start, "day")
+			require.Equal(t, http.StatusBadRequest, status)
+
+			status, _ = getSeries(start, start, "day")
+			require.Equal(t, http.StatusBadRequest, status)
+		})
+	})
+}
+
+func TestProjectUsageAlerts(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Console.UsageLimits.AlertThreshold = 80
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		user, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Usage Alerts Test",
+			Email:    "alerts@test.test",
+		}, 1)
+		require.NoError(t, err)
+
+		project, err := sat.AddProject(ctx, user.ID, "alerts")
+		require.NoError(t, err)
+
+		const limit = 100
+
+		err = sat.DB.ProjectAccounting().UpdateProjectUsageLimit(ctx, project.ID, limit)
+		require.NoError(t, err)
+		err = sat.DB.ProjectAccounting().UpdateProjectBandwidthLimit(ctx, project.ID, limit)
+		require.NoError(t, err)
+
+		// we are using full name as a password
+		token, err := sat.API.Console.Service.Token(ctx, console.AuthUser{Email: user.Email, Password: user.FullName})
+		require.NoError(t, err)
+
+		getAlerts := func() []console.ProjectUsageAlert {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+				"http://"+sat.API.Console.Listener.Addr().String()+"/api/v0/projects/"+project.ID.String()+"/usage-alerts", nil)
+			require.NoError(t, err)
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   token,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, result.Body.Close()) }()
+			require.Equal(t, http.StatusOK, result.StatusCode)
+
+			var alerts []console.ProjectUsageAlert
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&alerts))
+			return alerts
+		}
+
+		require.Empty(t, getAlerts())
+
+		// just below the threshold.
+		err = sat.API.Accounting.ProjectUsage.AddProjectStorageUsage(ctx, project.ID, 79)
+		require.NoError(t, err)
+		err = sat.DB.Orders().UpdateBucketBandwidthAllocation(ctx, project.ID, []byte("bucket"), pb.PieceAction_GET, 79, time.Now())
+		require.NoError(t, err)
+		require.Empty(t, getAlerts())
+
+		// storage reaches the threshold.
+		err = sat.API.Accounting.ProjectUsage.AddProjectStorageUsage(ctx, project.ID, 1)
+		require.NoError(t, err)
+		require.Equal(t, []console.ProjectUsageAlert{
+			{Limit: "storage", Used: 80, Maximum: limit, Percent: 80},
+		}, getAlerts())
+
+		// bandwidth goes over the threshold as well.
+		err = sat.DB.Orders().UpdateBucketBandwidthAllocation(ctx, project.ID, []byte("bucket"), pb.PieceAction_GET, 11, time.Now())
+		require.NoError(t, err)
+		require.Equal(t, []console.ProjectUsageAlert{
+			{Limit: "storage", Used: 80, Maximum: limit, Percent: 80},
+			{Limit: "bandwidth", Used: 90, Maximum: limit, Percent: 90},
+		}, getAlerts())
+	})
+}
+
+// countingUsageDB is a satellite.DB which counts the bandwidth usage queries of every project.
+type countingUsageDB struct {
+	satellite.DB
+	counts *usageQueryCounts
+}
+
+func (db countingUsageDB) ProjectAccounting() accounting.ProjectAccounting {
+	return countingProjectAccounting{ProjectAccounting: db.DB.ProjectAccounting(), counts: db.counts}
+}
+
+type countingProjectAccounting struct {
+	accounting.ProjectAccounting
+	counts *usageQueryCounts
+}
+
+func (usage countingProjectAccounting) GetAllocatedBandwidthTotal(ctx context.Context, projectID uuid.UUID, from time.Time) (int64, error) {
+	usage.counts.inc(projectID)
+	return usage.ProjectAccounting.GetAllocatedBandwidthTotal(ctx, projectID, from)
+}
+
+type usageQueryCounts struct {
+	mu     sync.Mutex
+	counts map[uuid.UUID]int
+}
+
+func (counts *usageQueryCounts) inc(projectID uuid.UUID) {
+	counts.mu.Lock()
+	defer counts.mu.Unlock()
+	counts.counts[projectID]++
+}
+
+func (counts *usageQueryCounts) get(projectID uuid.UUID) int {
+	counts.mu.Lock()
+	defer counts.mu.Unlock()
+	return counts.counts[projectID]
+}
+
+func TestProjectUsageLimitsCache(t *testing.T) {
+	const ttl = 2 * time.Second
+	counts := &usageQueryCounts{counts: make(map[uuid.UUID]int)}
+
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			SatelliteDB: func(log *zap.Logger, index int, db satellite.DB) (satellite.DB, error) {
+				return countingUsageDB{DB: db, counts: counts}, nil
+			},
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Console.UsageLimitsCacheTTL = ttl
+				// the project limits are cached as in production.
+				config.ProjectLimit.CacheCapacity = 10000
+				config.ProjectLimit.CacheExpiration = 10 * time.Minute
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		user, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Usage Cache Test",
+			Email:    "cache@test.test",
+		}, 1)
+		require.NoError(t, err)
+
+		other, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Usage Cache Other",
+			Email:    "cache-other@test.test",
+		}, 1)
+		require.NoError(t, err)
+
+		project, err := sat.AddProject(ctx, user.ID, "cached")
+		require.NoError(t, err)
+
+		// we are using full name as a password
+		token, err := sat.API.Console.Service.Token(ctx, console.AuthUser{Email: user.Email, Password: user.FullName})
+		require.NoError(t, err)
+		otherToken, err := sat.API.Console.Service.Token(ctx, console.AuthUser{Email: other.Email, Password: other.FullName})
+		require.NoError(t, err)
+
+		doRequest := func(token string) *http.Response {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+				"http://"+sat.API.Console.Listener.Addr().String()+"/api/v0/projects/"+project.ID.String()+"/usage-limits", nil)
+			require.NoError(t, err)
+			req.AddCookie(&http.Cookie{
+				Name:    "_tokenKey",
+				Path:    "/",
+				Value:   token,
+				Expires: time.Now().AddDate(0, 0, 1),
+			})
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			return result
+		}
+
+		getUsageLimits := func() console.ProjectUsageLimits {
+			result := doRequest(token)
+			defer func() { require.NoError(t, result.Body.Close()) }()
+			require.Equal(t, http.StatusOK, result.StatusCode)
+
+			var usageLimits console.ProjectUsageLimits
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&usageLimits))
+			return usageLimits
+		}
+
+		getUsageLimits()
+		require.Equal(t, 1, counts.get(project.ID))
+
+		// a second request within the ttl is served from the cache.
+		getUsageLimits()
+		require.Equal(t, 1, counts.get(project.ID))
+
+		// a user who isn't a member of the project isn't served from the cache.
+		result := doRequest(otherToken)
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, http.StatusUnauthorized, result.StatusCode)
+		require.Equal(t, 1, counts.get(project.ID))
+
+		// updating a limit, as the admin API does, invalidates the cache
+		// even though the project limits are still cached.
+		err = sat.DB.ProjectAccounting().UpdateProjectUsageLimit(ctx, project.ID, 1234)
+		require.NoError(t, err)
+		usageLimits := getUsageLimits()
+		require.Equal(t, int64(1234), usageLimits.StorageLimit)
+		require.Equal(t, 2, counts.get(project.ID))
+
+		// the usage is queried again once the entry has expired.
+		time.Sleep(ttl)
+		getUsageLimits()
+		require.Equal(t, 3, counts.get(project.ID))
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package consoleapi
+
+import (
+	"sync"
+	"time"
+
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/console"
+)
+
+// usageLimitsCache briefly remembers the usage and limits returned to the
+// client, so that frequent polling doesn't query the current usage each time.
+//
+// Entries are keyed by the authorized user, so they are only looked up after
+// the user was authorized for the project. The limits are read from the
+// database on every request, and an entry computed with other limits is
+// dropped. That way a limit update invalidates the cache, whether it is made
+// by the console or by the admin API which runs in another process.
+type usageLimitsCache struct {
+	ttl   time.Duration
+	nowFn func() time.Time
+
+	mu      sync.Mutex
+	entries map[usageLimitsCacheKey]usageLimitsCacheEntry
+}
+
+// usageLimitsCacheKey identifies the usage and limits of a project, or the
+// total ones when ProjectID is zero, as returned to a user.
+type usageLimitsCacheKey struct {
+	UserID    uuid.UUID
+	ProjectID uuid.UUID
+}
+
+// usageLimitsCacheEntry is the usage and limits cached until expiresAt.
+type usageLimitsCacheEntry struct {
+	usageLimits console.ProjectUsageLimits
+	expiresAt   time.Time
+}
+
+// newUsageLimitsCache returns a new usage and limits cache. A ttl of zero or
+// less disables caching.
+func newUsageLimitsCache(ttl time.Duration) *usageLimitsCache {
+	return &usageLimitsCache{
+		ttl:     ttl,
+		nowFn:   time.Now,
+		entries: make(map[usageLimitsCacheKey]usageLimitsCacheEntry),
+	}
+}
+
+// Get returns the cached usage and limits for key if they haven't expired and
+// were cached with the given current limits. An entry cached with other
+// limits is invalidated.
+func (cache *usageLimitsCache) Get(key usageLimitsCacheKey, limits *console.ProjectUsageLimits) (*console.ProjectUsageLimits, bool) {
+	if cache.ttl <= 0 {
+		return nil, false
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[key]
+	if !ok || !cache.nowFn().Before(entry.expiresAt) {
+		return nil, false
+	}
+	if entry.usageLimits.StorageLimit != limits.StorageLimit || entry.usageLimits.BandwidthLimit != limits.BandwidthLimit {
+		delete(cache.entries, key)
+		return nil, false
+	}
+
+	usageLimits := entry.usageLimits
+	return &usageLimits, true
+}
+
+// Set caches the usage and limits for key.
+func (cache *usageLimitsCache) Set(key usageLimitsCacheKey, usageLimits *console.ProjectUsageLimits) {
+	if cache.ttl <= 0 {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	now := cache.nowFn()
+	for entryKey, entry := range cache.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(cache.entries, entryKey)
+		}
+	}
+
+	cache.entries[key] = usageLimitsCacheEntry{
+		usageLimits: *usageLimits,
+		expiresAt:   now.Add(cache.ttl),
+	}
+}
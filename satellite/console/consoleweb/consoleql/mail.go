@@ -70,3 +70,30 @@ func (*ProjectInvitationEmail) Template() string { return "Invite" }
 func (email *ProjectInvitationEmail) Subject() string {
 	return "You were invited to join the Project " + email.ProjectName
 }
+
+// AccountDeletionEmail is mailservice template with account deletion data.
+type AccountDeletionEmail struct {
+	Origin         string
+	UserName       string
+	DeletionDate   string
+	ContactInfoURL string
+}
+
+// Template returns email template name.
+func (*AccountDeletionEmail) Template() string { return "AccountDeletion" }
+
+// Subject gets email subject.
+func (*AccountDeletionEmail) Subject() string { return "Your account is scheduled for deletion" }
+
+// EmailChangeEmail is mailservice template with email change confirmation data.
+type EmailChangeEmail struct {
+	Origin           string
+	UserName         string
+	ConfirmationLink string
+}
+
+// Template returns email template name.
+func (*EmailChangeEmail) Template() string { return "EmailChange" }
+
+// Subject gets email subject.
+func (*EmailChangeEmail) Subject() string { return "Confirm your new email" }
@@ -87,11 +87,13 @@ func TestGraphqlQuery(t *testing.T) {
 			db.ProjectAccounting(),
 			projectUsage,
 			db.Buckets(),
+			nil,
 			partnersService,
 			paymentsService.Accounts(),
 			analyticsService,
 			console.Config{PasswordCost: console.TestPasswordCost, DefaultProjectLimit: 5},
 			5000,
+			10,
 		)
 		require.NoError(t, err)
 
@@ -17,6 +17,7 @@ import (
 
 	"storj.io/common/testcontext"
 	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite/console"
 )
 
 func TestAuth(t *testing.T) {
@@ -79,6 +80,21 @@ func TestAuth(t *testing.T) {
 			require.NotEmpty(test.t, userIdentifier.ID)
 		}
 
+		{ // Get_AccountInfo_SessionExpiration
+			resp, body := test.request(http.MethodGet, "/auth/account", nil)
+			require.Equal(test.t, http.StatusOK, resp.StatusCode)
+
+			var session struct {
+				SessionExpiration time.Time `json:"sessionExpiration"`
+			}
+			require.NoError(test.t, json.Unmarshal([]byte(body), &session))
+
+			now := time.Now()
+			require.True(test.t, session.SessionExpiration.After(now))
+			require.False(test.t, session.SessionExpiration.After(now.Add(console.TokenExpirationTime)))
+			require.True(test.t, session.SessionExpiration.After(now.Add(console.TokenExpirationTime-time.Minute)))
+		}
+
 		{ // Logout
 			resp, _ := test.request(http.MethodPost, "/auth/logout", nil)
 			cookie := findCookie(resp, "_tokenKey")
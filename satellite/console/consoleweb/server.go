@@ -32,6 +32,7 @@ import (
 	"storj.io/common/errs2"
 	"storj.io/common/storj"
 	"storj.io/common/uuid"
+	"storj.io/private/version"
 	"storj.io/storj/private/web"
 	"storj.io/storj/satellite/analytics"
 	"storj.io/storj/satellite/console"
@@ -40,6 +41,7 @@ import (
 	"storj.io/storj/satellite/console/consoleweb/consoleql"
 	"storj.io/storj/satellite/console/consoleweb/consolewebauth"
 	"storj.io/storj/satellite/mailservice"
+	"storj.io/storj/satellite/payments"
 	"storj.io/storj/satellite/payments/paymentsconfig"
 	"storj.io/storj/satellite/rewards"
 )
@@ -64,6 +66,13 @@ type Config struct {
 	StaticDir       string `help:"path to static resources" default:""`
 	ExternalAddress string `help:"external endpoint of the satellite if hosted" default:""`
 
+	// The write timeout has to be long enough for the slowest handler, the usage report,
+	// which queries the bucket usage rollups before writing the response.
+	ReadHeaderTimeout time.Duration `help:"how long the server waits for the request headers (0=disabled)" default:"10s"`
+	ReadTimeout       time.Duration `help:"how long the server waits for the whole request, including the body (0=disabled)" default:"1m"`
+	WriteTimeout      time.Duration `help:"how long the server waits for writing the response after reading the request headers (0=disabled)" default:"5m"`
+	IdleTimeout       time.Duration `help:"how long the server keeps idle keep-alive connections open (0=disabled)" default:"2m"`
+
 	// TODO: remove after Vanguard release
 	AuthToken       string `help:"auth token needed for access to registration token creation endpoint" default:"" testDefault:"very-secret-token"`
 	AuthTokenSecret string `help:"secret used to sign auth tokens" releaseDefault:"" devDefault:"my-suppa-secret-key"`
@@ -88,15 +97,40 @@ type Config struct {
 	CouponCodeSignupUIEnabled       bool    `help:"indicates if user is allowed to add coupon codes to account from signup" default:"false"`
 	FileBrowserFlowDisabled         bool    `help:"indicates if file browser flow is disabled" default:"false"`
 	CSPEnabled                      bool    `help:"indicates if Content Security Policy is enabled" devDefault:"false" releaseDefault:"true"`
+	CSPReportURI                    string  `help:"uri Content Security Policy violation reports are sent to, reports sent to /api/v0/csp-report are logged (empty=disabled)" default:""`
 	LinksharingURL                  string  `help:"url link for linksharing requests" default:"https://link.us1.storjshare.io"`
 	PathwayOverviewEnabled          bool    `help:"indicates if the overview onboarding step should render with pathways" default:"true"`
 
+	// AlternateNodeURLList contains the addresses of the same satellite that clients can fall back to.
+	AlternateNodeURLList NodeURLList `help:"comma separated node urls of the satellite clients can fall back to when its main node url is unreachable" default:""`
+
+	// PricingRegionHeader is the request header containing the region of the client, e.g. set by a CDN.
+	PricingRegionHeader string `help:"request header containing the region of the client used to select the pricing when the request has no region query parameter (empty=disabled)" default:""`
+
+	// UsageLimitsCacheTTL is how long the usage and limits returned to the client are cached.
+	UsageLimitsCacheTTL time.Duration `help:"how long the usage and limits of a project are cached for the client (0=disabled)" default:"10s"`
+
 	// RateLimit defines the configuration for the IP and userID rate limiters.
 	RateLimit web.RateLimiterConfig
 
+	// QueryRateLimit defines the configuration for the IP rate limiter of the GraphQL endpoint.
+	QueryRateLimit QueryRateLimitConfig
+
 	console.Config
 }
 
+// QueryRateLimitConfig configures the IP rate limiter of the GraphQL endpoint.
+// It's separate from RateLimit because the web app sends many more GraphQL
+// requests than e.g. login requests. The trusted proxies are taken from RateLimit,
+// so the limiter is disabled by default: behind a load balancer without trusted
+// proxies all clients would share one limit.
+type QueryRateLimitConfig struct {
+	Enabled   bool          `help:"whether GraphQL requests are rate limited per client IP. Behind a load balancer console.rate-limit.trusted-proxies must be set as well, otherwise all clients share the limit of the load balancer address" default:"false"`
+	Duration  time.Duration `help:"the rate at which GraphQL requests are allowed" default:"100ms"`
+	Burst     int           `help:"number of GraphQL requests before the limit kicks in" default:"100"`
+	NumLimits int           `help:"number of clients whose GraphQL rate limits we store" default:"1000" testDefault:"10"`
+}
+
 // SatList is a configuration value that contains a list of satellite names and addresses.
 // Format should be [[name,address],[name,address],...] in valid JSON format.
 //
@@ -130,6 +164,37 @@ func (sl *SatList) Set(s string) error {
 	return nil
 }
 
+// NodeURLList is a configuration value that contains a comma separated list of satellite node URLs.
+// Each node URL must contain both the node ID and the address.
+//
+// Can be used as a flag.
+type NodeURLList storj.NodeURLs
+
+// Type implements pflag.Value.
+func (NodeURLList) Type() string { return "consoleweb.NodeURLList" }
+
+// String is required for pflag.Value.
+func (list NodeURLList) String() string {
+	return storj.NodeURLs(list).String()
+}
+
+// Set parses and validates the node URLs.
+func (list *NodeURLList) Set(s string) error {
+	urls, err := storj.ParseNodeURLs(s)
+	if err != nil {
+		return err
+	}
+
+	for _, url := range urls {
+		if url.ID.IsZero() || url.Address == "" {
+			return errs.New("Could not parse node url list config. Each node url must contain a node id and an address: %q", url.String())
+		}
+	}
+
+	*list = NodeURLList(urls)
+	return nil
+}
+
 // Server represents console web server.
 //
 // architecture: Endpoint
@@ -147,9 +212,12 @@ type Server struct {
 	cookieAuth        *consolewebauth.CookieAuth
 	ipRateLimiter     *web.RateLimiter
 	userIDRateLimiter *web.RateLimiter
+	queryRateLimiter  *web.RateLimiter
 	nodeURL           storj.NodeURL
+	versionInfo       version.Info
 
 	stripePublicKey string
+	webhooks        payments.Webhooks
 
 	pricing paymentsconfig.PricingValues
 
@@ -163,7 +231,7 @@ type Server struct {
 }
 
 // NewServer creates new instance of console server.
-func NewServer(logger *zap.Logger, config Config, service *console.Service, mailService *mailservice.Service, partners *rewards.PartnersService, analytics *analytics.Service, listener net.Listener, stripePublicKey string, pricing paymentsconfig.PricingValues, nodeURL storj.NodeURL) *Server {
+func NewServer(logger *zap.Logger, config Config, service *console.Service, mailService *mailservice.Service, partners *rewards.PartnersService, analytics *analytics.Service, listener net.Listener, stripePublicKey string, webhooks payments.Webhooks, pricing paymentsconfig.PricingValues, nodeURL storj.NodeURL, versionInfo version.Info) *Server {
 	server := Server{
 		log:               logger,
 		config:            config,
@@ -173,12 +241,23 @@ func NewServer(logger *zap.Logger, config Config, service *console.Service, mail
 		partners:          partners,
 		analytics:         analytics,
 		stripePublicKey:   stripePublicKey,
+		webhooks:          webhooks,
 		ipRateLimiter:     web.NewIPRateLimiter(config.RateLimit),
 		userIDRateLimiter: NewUserIDRateLimiter(config.RateLimit),
 		nodeURL:           nodeURL,
+		versionInfo:       versionInfo,
 		pricing:           pricing,
 	}
 
+	if config.QueryRateLimit.Enabled {
+		server.queryRateLimiter = web.NewIPRateLimiter(web.RateLimiterConfig{
+			Duration:       config.QueryRateLimit.Duration,
+			Burst:          config.QueryRateLimit.Burst,
+			NumLimits:      config.QueryRateLimit.NumLimits,
+			TrustedProxies: config.RateLimit.TrustedProxies,
+		})
+	}
+
 	logger.Debug("Starting Satellite UI.", zap.Stringer("Address", server.listener.Addr()))
 
 	server.cookieAuth = consolewebauth.NewCookieAuth(consolewebauth.CookieSettings{
@@ -203,38 +282,51 @@ func NewServer(logger *zap.Logger, config Config, service *console.Service, mail
 
 	router.HandleFunc("/registrationToken/", server.createRegistrationTokenHandler)
 	router.HandleFunc("/robots.txt", server.seoHandler)
+	router.HandleFunc("/api/v0/version", server.versionHandler).Methods(http.MethodGet)
 
-	router.Handle("/api/v0/graphql", server.withAuth(http.HandlerFunc(server.graphqlHandler)))
+	router.Handle("/api/v0/graphql", server.withQueryRateLimit(server.withAuth(http.HandlerFunc(server.graphqlHandler))))
 
-	usageLimitsController := consoleapi.NewUsageLimits(logger, service)
+	usageLimitsController := consoleapi.NewUsageLimits(logger, service, config.UsageLimitsCacheTTL)
 	router.Handle(
 		"/api/v0/projects/{id}/usage-limits",
 		server.withAuth(http.HandlerFunc(usageLimitsController.ProjectUsageLimits)),
 	).Methods(http.MethodGet)
+	router.Handle(
+		"/api/v0/projects/{id}/usage-series",
+		server.withAuth(http.HandlerFunc(usageLimitsController.ProjectUsageSeries)),
+	).Methods(http.MethodGet)
+	router.Handle(
+		"/api/v0/projects/{id}/usage-alerts",
+		server.withAuth(http.HandlerFunc(usageLimitsController.ProjectUsageAlerts)),
+	).Methods(http.MethodGet)
 	router.Handle(
 		"/api/v0/projects/usage-limits",
 		server.withAuth(http.HandlerFunc(usageLimitsController.TotalUsageLimits)),
 	).Methods(http.MethodGet)
 
-	authController := consoleapi.NewAuth(logger, service, mailService, server.cookieAuth, partners, server.analytics, server.config.ExternalAddress, config.LetUsKnowURL, config.TermsAndConditionsURL, config.ContactInfoURL)
+	authController := consoleapi.NewAuth(logger, service, mailService, server.cookieAuth, partners, server.analytics, server.config.ExternalAddress, config.LetUsKnowURL, config.TermsAndConditionsURL, config.ContactInfoURL, config.RateLimit.TrustedProxies)
 	authRouter := router.PathPrefix("/api/v0/auth").Subrouter()
 	authRouter.Handle("/account", server.withAuth(http.HandlerFunc(authController.GetAccount))).Methods(http.MethodGet)
 	authRouter.Handle("/account", server.withAuth(http.HandlerFunc(authController.UpdateAccount))).Methods(http.MethodPatch)
 	authRouter.Handle("/account/change-email", server.withAuth(http.HandlerFunc(authController.ChangeEmail))).Methods(http.MethodPost)
+	authRouter.Handle("/account/confirm-email", server.ipRateLimiter.Limit(http.HandlerFunc(authController.ConfirmEmailChange))).Methods(http.MethodGet)
 	authRouter.Handle("/account/change-password", server.withAuth(http.HandlerFunc(authController.ChangePassword))).Methods(http.MethodPost)
 	authRouter.Handle("/account/delete", server.withAuth(http.HandlerFunc(authController.DeleteAccount))).Methods(http.MethodPost)
 	authRouter.Handle("/mfa/enable", server.withAuth(http.HandlerFunc(authController.EnableUserMFA))).Methods(http.MethodPost)
 	authRouter.Handle("/mfa/disable", server.withAuth(http.HandlerFunc(authController.DisableUserMFA))).Methods(http.MethodPost)
 	authRouter.Handle("/mfa/generate-secret-key", server.withAuth(http.HandlerFunc(authController.GenerateMFASecretKey))).Methods(http.MethodPost)
 	authRouter.Handle("/mfa/generate-recovery-codes", server.withAuth(http.HandlerFunc(authController.GenerateMFARecoveryCodes))).Methods(http.MethodPost)
+	authRouter.Handle("/mfa/recovery-codes/count", server.withAuth(http.HandlerFunc(authController.GetMFARecoveryCodeCount))).Methods(http.MethodGet)
 	authRouter.HandleFunc("/logout", authController.Logout).Methods(http.MethodPost)
+	authRouter.Handle("/logout-all", server.withAuth(http.HandlerFunc(authController.LogoutAll))).Methods(http.MethodPost)
 	authRouter.Handle("/token", server.ipRateLimiter.Limit(http.HandlerFunc(authController.Token))).Methods(http.MethodPost)
 	authRouter.Handle("/register", server.ipRateLimiter.Limit(http.HandlerFunc(authController.Register))).Methods(http.MethodPost, http.MethodOptions)
 	authRouter.Handle("/forgot-password/{email}", server.ipRateLimiter.Limit(http.HandlerFunc(authController.ForgotPassword))).Methods(http.MethodPost)
 	authRouter.Handle("/resend-email/{id}", server.ipRateLimiter.Limit(http.HandlerFunc(authController.ResendEmail))).Methods(http.MethodPost)
 	authRouter.Handle("/reset-password", server.ipRateLimiter.Limit(http.HandlerFunc(authController.ResetPassword))).Methods(http.MethodPost)
 
-	paymentController := consoleapi.NewPayments(logger, service)
+	paymentController := consoleapi.NewPayments(logger, service, server.webhooks)
+	router.HandleFunc("/api/v0/payments/webhook", paymentController.Webhook).Methods(http.MethodPost)
 	paymentsRouter := router.PathPrefix("/api/v0/payments").Subrouter()
 	paymentsRouter.Use(server.withAuth)
 	paymentsRouter.HandleFunc("/cards", paymentController.AddCreditCard).Methods(http.MethodPost)
@@ -246,6 +338,7 @@ func NewServer(logger *zap.Logger, config Config, service *console.Service, mail
 	paymentsRouter.HandleFunc("/account", paymentController.SetupAccount).Methods(http.MethodPost)
 	paymentsRouter.HandleFunc("/billing-history", paymentController.BillingHistory).Methods(http.MethodGet)
 	paymentsRouter.HandleFunc("/tokens/deposit", paymentController.TokenDeposit).Methods(http.MethodPost)
+	paymentsRouter.HandleFunc("/tokens/deposit/{id}", paymentController.TokenDepositStatus).Methods(http.MethodGet)
 	paymentsRouter.Handle("/coupon/apply", server.userIDRateLimiter.Limit(http.HandlerFunc(paymentController.ApplyCouponCode))).Methods(http.MethodPatch)
 	paymentsRouter.HandleFunc("/coupon", paymentController.GetCoupon).Methods(http.MethodGet)
 
@@ -253,17 +346,30 @@ func NewServer(logger *zap.Logger, config Config, service *console.Service, mail
 	bucketsRouter := router.PathPrefix("/api/v0/buckets").Subrouter()
 	bucketsRouter.Use(server.withAuth)
 	bucketsRouter.HandleFunc("/bucket-names", bucketsController.AllBucketNames).Methods(http.MethodGet)
+	bucketsRouter.HandleFunc("", bucketsController.CreateBucket).Methods(http.MethodPost)
+	bucketsRouter.HandleFunc("/{name}", bucketsController.DeleteBucket).Methods(http.MethodDelete)
+	bucketsRouter.HandleFunc("/{name}/objects", bucketsController.ListObjects).Methods(http.MethodGet)
+	bucketsRouter.HandleFunc("/{name}/objects/{key:.+}", bucketsController.ObjectMetadata).Methods(http.MethodGet)
 
 	apiKeysController := consoleapi.NewAPIKeys(logger, service)
 	apiKeysRouter := router.PathPrefix("/api/v0/api-keys").Subrouter()
 	apiKeysRouter.Use(server.withAuth)
+	apiKeysRouter.HandleFunc("", apiKeysController.GetProjectAPIKeys).Methods(http.MethodGet)
 	apiKeysRouter.HandleFunc("/delete-by-name", apiKeysController.DeleteByNameAndProjectID).Methods(http.MethodDelete)
+	apiKeysRouter.HandleFunc("/{id}", apiKeysController.DeleteByID).Methods(http.MethodDelete)
+	apiKeysRouter.HandleFunc("/{id}/usage", apiKeysController.GetUsage).Methods(http.MethodGet)
+	apiKeysRouter.HandleFunc("/{id}/rotate", apiKeysController.Rotate).Methods(http.MethodPost)
 
 	analyticsController := consoleapi.NewAnalytics(logger, service, server.analytics)
 	analyticsRouter := router.PathPrefix("/api/v0/analytics").Subrouter()
 	analyticsRouter.Use(server.withAuth)
 	analyticsRouter.HandleFunc("/event", analyticsController.EventTriggered).Methods(http.MethodPost)
 
+	if server.config.CSPReportURI != "" {
+		cspController := consoleapi.NewCSP(logger)
+		router.Handle("/api/v0/csp-report", server.ipRateLimiter.Limit(http.HandlerFunc(cspController.Report))).Methods(http.MethodPost)
+	}
+
 	if server.config.StaticDir != "" {
 		router.HandleFunc("/activation/", server.accountActivationHandler)
 		router.HandleFunc("/cancel-password-recovery/", server.cancelPasswordRecoveryHandler)
@@ -273,8 +379,12 @@ func NewServer(logger *zap.Logger, config Config, service *console.Service, mail
 	}
 
 	server.server = http.Server{
-		Handler:        server.withRequest(router),
-		MaxHeaderBytes: ContentLengthLimit.Int(),
+		Handler:           server.withRequest(router),
+		MaxHeaderBytes:    ContentLengthLimit.Int(),
+		ReadHeaderTimeout: config.ReadHeaderTimeout,
+		ReadTimeout:       config.ReadTimeout,
+		WriteTimeout:      config.WriteTimeout,
+		IdleTimeout:       config.IdleTimeout,
 	}
 
 	return &server
@@ -305,6 +415,12 @@ func (server *Server) Run(ctx context.Context) (err error) {
 		server.ipRateLimiter.Run(ctx)
 		return nil
 	})
+	if server.queryRateLimiter != nil {
+		group.Go(func() error {
+			server.queryRateLimiter.Run(ctx)
+			return nil
+		})
+	}
 	group.Go(func() error {
 		defer cancel()
 		err := server.server.Serve(server.listener)
@@ -336,6 +452,9 @@ func (server *Server) appHandler(w http.ResponseWriter, r *http.Request) {
 			"media-src 'self' *.tardigradeshare.io *.storjshare.io",
 			"script-src 'sha256-wAqYV6m2PHGd1WDyFBnZmSoyfCK0jxFAns0vGbdiWUA=' 'self' *.stripe.com https://www.google.com/recaptcha/ https://www.gstatic.com/recaptcha/",
 		}
+		if server.config.CSPReportURI != "" {
+			cspValues = append(cspValues, "report-uri "+server.config.CSPReportURI)
+		}
 
 		header.Set("Content-Security-Policy", strings.Join(cspValues, "; "))
 	}
@@ -348,6 +467,7 @@ func (server *Server) appHandler(w http.ResponseWriter, r *http.Request) {
 		ExternalAddress                 string
 		SatelliteName                   string
 		SatelliteNodeURL                string
+		SatelliteNodeURLs               string
 		StripePublicKey                 string
 		PartneredSatellites             string
 		DefaultProjectLimit             int
@@ -366,6 +486,7 @@ func (server *Server) appHandler(w http.ResponseWriter, r *http.Request) {
 		StorageTBPrice                  string
 		EgressTBPrice                   string
 		ObjectPrice                     string
+		PricingRegion                   string
 		RecaptchaEnabled                bool
 		RecaptchaSiteKey                string
 	}
@@ -373,6 +494,7 @@ func (server *Server) appHandler(w http.ResponseWriter, r *http.Request) {
 	data.ExternalAddress = server.config.ExternalAddress
 	data.SatelliteName = server.config.SatelliteName
 	data.SatelliteNodeURL = server.nodeURL.String()
+	data.SatelliteNodeURLs = server.nodeURLs().String()
 	data.StripePublicKey = server.stripePublicKey
 	data.PartneredSatellites = string(server.config.PartneredSatellites)
 	data.DefaultProjectLimit = server.config.DefaultProjectLimit
@@ -388,9 +510,19 @@ func (server *Server) appHandler(w http.ResponseWriter, r *http.Request) {
 	data.FileBrowserFlowDisabled = server.config.FileBrowserFlowDisabled
 	data.LinksharingURL = server.config.LinksharingURL
 	data.PathwayOverviewEnabled = server.config.PathwayOverviewEnabled
-	data.StorageTBPrice = server.pricing.StorageTBPrice
-	data.EgressTBPrice = server.pricing.EgressTBPrice
-	data.ObjectPrice = server.pricing.ObjectPrice
+
+	region := r.URL.Query().Get("region")
+	if region == "" && server.config.PricingRegionHeader != "" {
+		region = r.Header.Get(server.config.PricingRegionHeader)
+	}
+	pricing, ok := server.pricing.ForRegion(region)
+	if ok {
+		data.PricingRegion = strings.ToLower(region)
+	}
+	data.StorageTBPrice = pricing.StorageTBPrice
+	data.EgressTBPrice = pricing.EgressTBPrice
+	data.ObjectPrice = pricing.ObjectPrice
+
 	data.RecaptchaEnabled = server.config.Recaptcha.Enabled
 	data.RecaptchaSiteKey = server.config.Recaptcha.SiteKey
 
@@ -405,6 +537,51 @@ func (server *Server) appHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// nodeURLs returns the node URL of the satellite followed by the alternate ones,
+// without duplicates.
+func (server *Server) nodeURLs() storj.NodeURLs {
+	urls := storj.NodeURLs{server.nodeURL}
+	for _, url := range server.config.AlternateNodeURLList {
+		if url != server.nodeURL {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// withQueryRateLimit rate limits the GraphQL requests per client IP, when the
+// limiter is enabled. Rejected requests get the same JSON error response as
+// the errors of graphqlHandler.
+func (server *Server) withQueryRateLimit(handler http.Handler) http.Handler {
+	if server.queryRateLimiter == nil {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed, err := server.queryRateLimiter.Allow(r)
+		if err == nil && allowed {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		code := http.StatusTooManyRequests
+		if err != nil {
+			server.log.Error("unable to rate limit GraphQL request", zap.Error(err))
+			code = http.StatusInternalServerError
+		}
+
+		var jsonError struct {
+			Error string `json:"error"`
+		}
+		jsonError.Error = http.StatusText(code)
+
+		w.Header().Set(contentType, applicationJSON)
+		w.WriteHeader(code)
+		if err := json.NewEncoder(w).Encode(jsonError); err != nil {
+			server.log.Error("failed to write json error response", zap.Error(err))
+		}
+	})
+}
+
 // authMiddlewareHandler performs initial authorization before every request.
 func (server *Server) withAuth(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -435,13 +612,53 @@ func (server *Server) withAuth(handler http.Handler) http.Handler {
 	})
 }
 
-// withRequest ensures the http request itself is reachable from the context.
+// withRequest ensures the http request itself and its ID are reachable from the context.
+// The ID supplied by the client is used when it is valid, otherwise a new one is generated.
+// The ID is sent back to the client in the response headers.
 func (server *Server) withRequest(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		handler.ServeHTTP(w, r.Clone(console.WithRequest(r.Context(), r)))
+		requestID := r.Header.Get(console.RequestIDHeader)
+		if !validRequestID(requestID) {
+			id, err := uuid.New()
+			if err != nil {
+				server.log.Error("failed to generate request id", zap.Error(err))
+				server.serveError(w, http.StatusInternalServerError)
+				return
+			}
+			requestID = id.String()
+		}
+		w.Header().Set(console.RequestIDHeader, requestID)
+
+		server.log.Debug("handling request",
+			zap.String("requestID", requestID),
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path))
+
+		ctx := console.WithRequestID(r.Context(), requestID)
+		handler.ServeHTTP(w, r.Clone(console.WithRequest(ctx, r)))
 	})
 }
 
+// maxRequestIDLength is the longest request ID accepted from clients.
+const maxRequestIDLength = 128
+
+// validRequestID returns whether the request ID supplied by a client can be used.
+// Only IDs which are safe to include in logs and response headers are accepted.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 // bucketUsageReportHandler generate bucket usage report page for project.
 func (server *Server) bucketUsageReportHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -590,6 +807,8 @@ func (server *Server) graphqlHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	defer mon.Task()(&ctx)(nil)
 
+	log := server.log.With(zap.String("requestID", console.GetRequestID(ctx)))
+
 	handleError := func(code int, err error) {
 		w.WriteHeader(code)
 
@@ -600,7 +819,7 @@ func (server *Server) graphqlHandler(w http.ResponseWriter, r *http.Request) {
 		jsonError.Error = err.Error()
 
 		if err := json.NewEncoder(w).Encode(jsonError); err != nil {
-			server.log.Error("error graphql error", zap.Error(err))
+			log.Error("error graphql error", zap.Error(err))
 		}
 	}
 
@@ -663,7 +882,7 @@ func (server *Server) graphqlHandler(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if err := json.NewEncoder(w).Encode(jsonError); err != nil {
-			server.log.Error("error graphql error", zap.Error(err))
+			log.Error("error graphql error", zap.Error(err))
 		}
 	}
 
@@ -691,11 +910,11 @@ func (server *Server) graphqlHandler(w http.ResponseWriter, r *http.Request) {
 
 	err = json.NewEncoder(w).Encode(result)
 	if err != nil {
-		server.log.Error("error encoding grapql result", zap.Error(err))
+		log.Error("error encoding grapql result", zap.Error(err))
 		return
 	}
 
-	server.log.Debug(fmt.Sprintf("%s", result))
+	log.Debug(fmt.Sprintf("%s", result))
 }
 
 // serveError serves error static pages.
@@ -729,6 +948,30 @@ func (server *Server) seoHandler(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// versionHandler returns the build version information of the running satellite.
+func (server *Server) versionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	defer mon.Task()(&ctx)(nil)
+	w.Header().Set(contentType, applicationJSON)
+
+	var response struct {
+		Version        string    `json:"version"`
+		CommitHash     string    `json:"commitHash"`
+		BuildTimestamp time.Time `json:"buildTimestamp"`
+		Release        bool      `json:"release"`
+	}
+
+	response.Version = server.versionInfo.Version.String()
+	response.CommitHash = server.versionInfo.CommitHash
+	response.BuildTimestamp = server.versionInfo.Timestamp
+	response.Release = server.versionInfo.Release
+
+	err := json.NewEncoder(w).Encode(&response)
+	if err != nil {
+		server.log.Error("failed to write json version response", zap.Error(Error.Wrap(err)))
+	}
+}
+
 // brotliMiddleware is used to compress static content using brotli to minify resources if browser support such decoding.
 func (server *Server) brotliMiddleware(fn http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -5,8 +5,15 @@ package consoleweb_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,9 +21,11 @@ import (
 	"go.uber.org/zap"
 
 	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
 	"storj.io/storj/private/testplanet"
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/console"
+	"storj.io/storj/satellite/console/consoleweb"
 )
 
 func TestActivationRouting(t *testing.T) {
@@ -130,3 +139,318 @@ func TestUserIDRateLimiter(t *testing.T) {
 		require.Equal(t, http.StatusTooManyRequests, applyCouponStatus(firstToken))
 	})
 }
+
+func TestVersionEndpoint(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		url := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/version"
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+		require.NoError(t, err)
+
+		// the endpoint does not require authentication.
+		result, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, result.Body.Close()) }()
+
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		var info struct {
+			Version        string    `json:"version"`
+			CommitHash     string    `json:"commitHash"`
+			BuildTimestamp time.Time `json:"buildTimestamp"`
+			Release        bool      `json:"release"`
+		}
+		require.NoError(t, json.NewDecoder(result.Body).Decode(&info))
+
+		expected := planet.NewVersionInfo()
+		require.Equal(t, expected.Version.String(), info.Version)
+		require.Equal(t, expected.CommitHash, info.CommitHash)
+		require.Equal(t, expected.Release, info.Release)
+		require.False(t, info.BuildTimestamp.IsZero())
+	})
+}
+
+func TestCSPReportEndpoint(t *testing.T) {
+	const reportURI = "/api/v0/csp-report"
+
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Console.CSPEnabled = true
+				config.Console.CSPReportURI = reportURI
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		baseURL := "http://" + sat.API.Console.Listener.Addr().String()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/", http.NoBody)
+		require.NoError(t, err)
+
+		result, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Contains(t, result.Header.Get("Content-Security-Policy"), "report-uri "+reportURI)
+		require.NoError(t, result.Body.Close())
+
+		report := []byte(`{"csp-report":{"document-uri":"` + baseURL + `/","violated-directive":"img-src","blocked-uri":"https://example.test/image.png"}}`)
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, baseURL+reportURI, bytes.NewReader(report))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/csp-report")
+
+		// the endpoint does not require authentication.
+		result, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
+
+func TestRequestID(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		getRequestID := func(path, requestID string) string {
+			url := "http://" + sat.API.Console.Listener.Addr().String() + path
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+			require.NoError(t, err)
+			if requestID != "" {
+				req.Header.Set(console.RequestIDHeader, requestID)
+			}
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+			return result.Header.Get(console.RequestIDHeader)
+		}
+
+		for _, path := range []string{"/api/v0/version", "/api/v0/graphql", "/api/v0/auth/account"} {
+			generated := getRequestID(path, "")
+			require.NotEmpty(t, generated, path)
+			require.NotEqual(t, generated, getRequestID(path, ""), path)
+
+			require.Equal(t, "support-1234", getRequestID(path, "support-1234"), path)
+
+			replaced := getRequestID(path, "invalid request id")
+			require.NotEmpty(t, replaced, path)
+			require.NotEqual(t, "invalid request id", replaced, path)
+		}
+	})
+}
+
+func TestNodeURLListConfig(t *testing.T) {
+	var list consoleweb.NodeURLList
+	require.NoError(t, list.Set(""))
+	require.Empty(t, list)
+
+	id1, id2 := testrand.NodeID(), testrand.NodeID()
+	require.NoError(t, list.Set(id1.String()+"@sat1.test:7777,"+id2.String()+"@sat2.test:7777"))
+	require.Equal(t, consoleweb.NodeURLList{
+		{ID: id1, Address: "sat1.test:7777"},
+		{ID: id2, Address: "sat2.test:7777"},
+	}, list)
+
+	require.Error(t, list.Set("sat1.test:7777"))
+	require.Error(t, list.Set(id1.String()+"@"))
+	require.Error(t, list.Set("invalid@sat1.test:7777"))
+}
+
+func TestAlternateNodeURLs(t *testing.T) {
+	staticDir, err := ioutil.TempDir("", "console-static")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, os.RemoveAll(staticDir)) }()
+
+	for file, content := range map[string]string{
+		"dist/index.html":                 "{{ .SatelliteNodeURL }}|{{ .SatelliteNodeURLs }}",
+		"static/reports/usageReport.html": "",
+		"static/errors/404.html":          "",
+		"static/errors/500.html":          "",
+	} {
+		path := filepath.Join(staticDir, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	alternates := consoleweb.NodeURLList{
+		{ID: testrand.NodeID(), Address: "sat1.test:7777"},
+		{ID: testrand.NodeID(), Address: "sat2.test:7777"},
+	}
+
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Console.StaticDir = staticDir
+				config.Console.AlternateNodeURLList = alternates
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		url := "http://" + sat.API.Console.Listener.Addr().String() + "/"
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+		require.NoError(t, err)
+
+		result, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(result.Body)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+
+		nodeURL := sat.NodeURL().String()
+		expected := nodeURL + "|" + strings.Join([]string{nodeURL, alternates[0].String(), alternates[1].String()}, ",")
+		require.Equal(t, expected, string(body))
+	})
+}
+
+func TestRegionalPricing(t *testing.T) {
+	staticDir, err := ioutil.TempDir("", "console-static")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, os.RemoveAll(staticDir)) }()
+
+	for file, content := range map[string]string{
+		"dist/index.html":                 "{{ .PricingRegion }}|{{ .StorageTBPrice }}|{{ .EgressTBPrice }}|{{ .ObjectPrice }}",
+		"static/reports/usageReport.html": "",
+		"static/errors/404.html":          "",
+		"static/errors/500.html":          "",
+	} {
+		path := filepath.Join(staticDir, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Console.StaticDir = staticDir
+				config.Console.PricingRegionHeader = "X-Region"
+				config.Payments.StorageTBPrice = "10"
+				config.Payments.EgressTBPrice = "45"
+				config.Payments.ObjectPrice = "0.0000022"
+				require.NoError(t, config.Payments.RegionalPricing.Set("eu:12/50/0.0000025,ap:15/60/0"))
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		getIndex := func(query, region string) string {
+			url := "http://" + sat.API.Console.Listener.Addr().String() + "/" + query
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+			require.NoError(t, err)
+			if region != "" {
+				req.Header.Set("X-Region", region)
+			}
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			body, err := ioutil.ReadAll(result.Body)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+
+			return string(body)
+		}
+
+		// default pricing
+		require.Equal(t, "|10|45|0.0000022", getIndex("", ""))
+		require.Equal(t, "|10|45|0.0000022", getIndex("?region=us", ""))
+		require.Equal(t, "|10|45|0.0000022", getIndex("", "US"))
+
+		// pricing selected by query parameter
+		require.Equal(t, "eu|12|50|0.0000025", getIndex("?region=eu", ""))
+		require.Equal(t, "ap|15|60|0", getIndex("?region=AP", ""))
+
+		// pricing selected by header
+		require.Equal(t, "eu|12|50|0.0000025", getIndex("", "EU"))
+
+		// query parameter takes precedence over the header
+		require.Equal(t, "ap|15|60|0", getIndex("?region=ap", "eu"))
+	})
+}
+
+func TestQueryRateLimit(t *testing.T) {
+	burst := 3
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Console.QueryRateLimit.Enabled = true
+				config.Console.QueryRateLimit.Burst = burst
+				config.Console.QueryRateLimit.Duration = time.Hour
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		url := "http://" + sat.API.Console.Listener.Addr().String() + "/api/v0/graphql"
+
+		query := func() (int, string, []byte) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBufferString(`{"query":"{ myProjects { id } }"}`))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+
+			result, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			body, err := ioutil.ReadAll(result.Body)
+			require.NoError(t, err)
+			require.NoError(t, result.Body.Close())
+
+			return result.StatusCode, result.Header.Get("Content-Type"), body
+		}
+
+		for i := 0; i < burst; i++ {
+			code, _, _ := query()
+			require.NotEqual(t, http.StatusTooManyRequests, code, i)
+		}
+
+		code, contentType, body := query()
+		require.Equal(t, http.StatusTooManyRequests, code)
+		require.Equal(t, "application/json", contentType)
+
+		var jsonError struct {
+			Error string `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(body, &jsonError))
+		require.Equal(t, http.StatusText(http.StatusTooManyRequests), jsonError.Error)
+	})
+}
+
+func TestReadHeaderTimeout(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Console.ReadHeaderTimeout = 500 * time.Millisecond
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+
+		conn, err := net.Dial("tcp", sat.API.Console.Listener.Addr().String())
+		require.NoError(t, err)
+		defer ctx.Check(conn.Close)
+
+		// send only a part of the headers and never finish them.
+		_, err = conn.Write([]byte("GET /api/v0/version HTTP/1.1\r\nHost: localhost\r\n"))
+		require.NoError(t, err)
+
+		start := time.Now()
+		require.NoError(t, conn.SetReadDeadline(start.Add(10*time.Second)))
+
+		// the server closes the connection once the timeout is reached.
+		_, err = ioutil.ReadAll(conn)
+		var netErr net.Error
+		if errors.As(err, &netErr) {
+			require.False(t, netErr.Timeout(), "connection wasn't closed by the server")
+		}
+	})
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"strings"
+
+	"github.com/zeebo/errs"
+)
+
+// EmailDomainsConfig contains the email domains users are allowed or not
+// allowed to register with. Domains are matched exactly and case-insensitively,
+// so blocking a domain doesn't block its subdomains.
+type EmailDomainsConfig struct {
+	Allowed     EmailDomains `help:"comma separated email domains users are allowed to register with, all domains are allowed when empty" default:""`
+	Blocked     EmailDomains `help:"comma separated email domains users are not allowed to register with" default:""`
+	BlockedFile string       `help:"path to a file with additional email domains users are not allowed to register with, one per line" default:""`
+}
+
+// EmailDomains is a list of email domains.
+type EmailDomains []string
+
+// String formats the email domains.
+func (domains EmailDomains) String() string {
+	return strings.Join(domains, ",")
+}
+
+// Set implements pflag.Value by parsing a comma separated list of email domains.
+func (domains *EmailDomains) Set(value string) error {
+	var toSet EmailDomains
+	for _, domain := range strings.Split(value, ",") {
+		domain = strings.TrimSpace(domain)
+		if domain == "" {
+			continue
+		}
+		toSet = append(toSet, domain)
+	}
+
+	*domains = toSet
+	return nil
+}
+
+// Type returns the type of the pflag.Value.
+func (domains EmailDomains) Type() string {
+	return "email-domains"
+}
+
+// emailDomainFilter checks the email address domains users register with.
+type emailDomainFilter struct {
+	allowed map[string]struct{}
+	blocked map[string]struct{}
+}
+
+// newEmailDomainFilter creates a filter from the config, reading the blocked
+// domains file when one is configured. Empty lines and lines starting with #
+// are ignored in the file.
+func newEmailDomainFilter(config EmailDomainsConfig) (*emailDomainFilter, error) {
+	filter := &emailDomainFilter{
+		allowed: make(map[string]struct{}),
+		blocked: make(map[string]struct{}),
+	}
+
+	for _, domain := range config.Allowed {
+		filter.allowed[strings.ToLower(domain)] = struct{}{}
+	}
+	for _, domain := range config.Blocked {
+		filter.blocked[strings.ToLower(domain)] = struct{}{}
+	}
+
+	if config.BlockedFile != "" {
+		data, err := ioutil.ReadFile(config.BlockedFile)
+		if err != nil {
+			return nil, errs.New("unable to read blocked email domains: %v", err)
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			domain := strings.TrimSpace(scanner.Text())
+			if domain == "" || strings.HasPrefix(domain, "#") {
+				continue
+			}
+			filter.blocked[strings.ToLower(domain)] = struct{}{}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, errs.New("unable to read blocked email domains: %v", err)
+		}
+	}
+
+	return filter, nil
+}
+
+// Check returns an ErrValidation error when users aren't allowed to register
+// with the email address.
+func (filter *emailDomainFilter) Check(email string) error {
+	if len(filter.allowed) == 0 && len(filter.blocked) == 0 {
+		return nil
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return ErrValidation.New("invalid email address")
+	}
+	domain := strings.ToLower(email[at+1:])
+
+	if _, blocked := filter.blocked[domain]; blocked {
+		return ErrValidation.New(emailDomainBlockedErrMsg)
+	}
+	if _, allowed := filter.allowed[domain]; len(filter.allowed) > 0 && !allowed {
+		return ErrValidation.New(emailDomainNotAllowedErrMsg)
+	}
+
+	return nil
+}
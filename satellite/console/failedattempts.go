@@ -0,0 +1,86 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"sync"
+	"time"
+
+	"storj.io/common/uuid"
+)
+
+// failedAttempts tracks failed attempts of some operation per user. Once a user
+// has failed too many times within the window starting at their first failure,
+// they are limited until the window has passed.
+//
+// The attempts are only kept in memory, so they don't survive a restart and
+// aren't shared between multiple satellite API processes.
+type failedAttempts struct {
+	maxAttempts int
+	window      time.Duration
+
+	mu       sync.Mutex
+	attempts map[uuid.UUID]failedAttempt
+}
+
+// failedAttempt is the number of failures within the window starting at start.
+type failedAttempt struct {
+	failures int
+	start    time.Time
+}
+
+// newFailedAttempts returns a new failed attempt tracker. A maxAttempts of zero
+// or less disables limiting.
+func newFailedAttempts(maxAttempts int, window time.Duration) *failedAttempts {
+	return &failedAttempts{
+		maxAttempts: maxAttempts,
+		window:      window,
+		attempts:    make(map[uuid.UUID]failedAttempt),
+	}
+}
+
+// Limited returns whether the user has failed too many attempts within the window.
+func (f *failedAttempts) Limited(userID uuid.UUID, now time.Time) bool {
+	if f.maxAttempts <= 0 {
+		return false
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	attempt, ok := f.attempts[userID]
+	if !ok {
+		return false
+	}
+	if now.Sub(attempt.start) >= f.window {
+		delete(f.attempts, userID)
+		return false
+	}
+	return attempt.failures >= f.maxAttempts
+}
+
+// Fail records a failed attempt for the user.
+func (f *failedAttempts) Fail(userID uuid.UUID, now time.Time) {
+	if f.maxAttempts <= 0 {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	attempt, ok := f.attempts[userID]
+	if !ok || now.Sub(attempt.start) >= f.window {
+		attempt = failedAttempt{start: now}
+	}
+	attempt.failures++
+	f.attempts[userID] = attempt
+}
+
+// Reset clears the failed attempts for the user.
+func (f *failedAttempts) Reset(userID uuid.UUID) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.attempts, userID)
+}
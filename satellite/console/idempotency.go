@@ -0,0 +1,104 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"storj.io/common/uuid"
+)
+
+// idempotencyKeys remembers the requests a user made with an idempotency key,
+// so that a retried request returns the result of the original one instead of
+// performing the operation again. Keys are forgotten once the window starting
+// at their first use has passed.
+type idempotencyKeys struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	entries map[idempotencyKey]*idempotentRequest
+}
+
+// idempotencyKey is an idempotency key scoped to the user who sent it.
+type idempotencyKey struct {
+	userID uuid.UUID
+	key    string
+}
+
+// idempotentRequest is a request made with an idempotency key. done is closed
+// once the request has finished and err is set.
+type idempotentRequest struct {
+	fingerprint string
+	start       time.Time
+
+	done chan struct{}
+	err  error
+}
+
+// newIdempotencyKeys returns a new idempotency key tracker. A window of zero
+// or less disables deduplication.
+func newIdempotencyKeys(window time.Duration) *idempotencyKeys {
+	return &idempotencyKeys{
+		window:  window,
+		entries: make(map[idempotencyKey]*idempotentRequest),
+	}
+}
+
+// Do calls fn unless the user already made a request with the same key within
+// the window, in which case it waits for that request and returns its result.
+// The fingerprint identifies the parameters of the request, reusing a key with
+// a different fingerprint is a validation error. Failed requests are forgotten
+// so that they can be retried with the same key.
+func (k *idempotencyKeys) Do(ctx context.Context, userID uuid.UUID, key, fingerprint string, now time.Time, fn func() error) error {
+	if k.window <= 0 || key == "" {
+		return fn()
+	}
+
+	id := idempotencyKey{userID: userID, key: key}
+
+	k.mu.Lock()
+	for entryID, entry := range k.entries {
+		if now.Sub(entry.start) >= k.window {
+			delete(k.entries, entryID)
+		}
+	}
+
+	if entry, ok := k.entries[id]; ok {
+		k.mu.Unlock()
+
+		if entry.fingerprint != fingerprint {
+			return ErrValidation.New("idempotency key has already been used for a different request")
+		}
+
+		select {
+		case <-entry.done:
+			return entry.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	entry := &idempotentRequest{
+		fingerprint: fingerprint,
+		start:       now,
+		done:        make(chan struct{}),
+	}
+	k.entries[id] = entry
+	k.mu.Unlock()
+
+	entry.err = fn()
+
+	if entry.err != nil {
+		k.mu.Lock()
+		if k.entries[id] == entry {
+			delete(k.entries, id)
+		}
+		k.mu.Unlock()
+	}
+	close(entry.done)
+
+	return entry.err
+}
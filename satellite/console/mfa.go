@@ -12,6 +12,8 @@ import (
 	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
 	"github.com/zeebo/errs"
+
+	"storj.io/common/uuid"
 )
 
 const (
@@ -26,6 +28,7 @@ const (
 	mfaRecoveryInvalidErrMsg    = "The MFA recovery code is not valid or has been previously used"
 	mfaRecoveryGenerationErrMsg = "MFA recovery codes cannot be generated while MFA is disabled."
 	mfaConflictErrMsg           = "Expected either passcode or recovery code, but got both"
+	mfaRateLimitErrMsg          = "Too many failed MFA passcode attempts, please try again later"
 )
 
 var (
@@ -44,6 +47,9 @@ var (
 
 	// ErrMFAPasscode is error type that represents usage of invalid MFA passcode.
 	ErrMFAPasscode = errs.Class("MFA passcode")
+
+	// ErrMFARateLimit is error type that occurs when a user has failed too many MFA passcode attempts.
+	ErrMFARateLimit = errs.Class("MFA rate limit")
 )
 
 // NewMFAValidationOpts returns the options used to validate TOTP passcodes.
@@ -63,6 +69,21 @@ func ValidateMFAPasscode(passcode string, secretKey string, t time.Time) (bool,
 	return valid, Error.Wrap(err)
 }
 
+// validateMFAPasscode returns whether the TOTP passcode is valid for the secret key at the given time,
+// allowing for the amount of clock skew specified in the service config. Failed validations are
+// counted against the user, and a successful validation resets the count.
+func (s *Service) validateMFAPasscode(userID uuid.UUID, passcode string, secretKey string, t time.Time) (bool, error) {
+	opts := NewMFAValidationOpts()
+	opts.Skew = s.config.MFAPasscodeSkew
+	valid, err := totp.ValidateCustom(passcode, secretKey, t, opts)
+	if err != nil || !valid {
+		s.mfaAttempts.Fail(userID, t)
+	} else {
+		s.mfaAttempts.Reset(userID)
+	}
+	return valid, Error.Wrap(err)
+}
+
 // NewMFAPasscode derives a TOTP passcode from a secret key using a timestamp.
 func NewMFAPasscode(secretKey string, t time.Time) (string, error) {
 	code, err := totp.GenerateCodeCustom(secretKey, t, NewMFAValidationOpts())
@@ -94,7 +115,11 @@ func (s *Service) EnableUserMFA(ctx context.Context, passcode string, t time.Tim
 		return Error.Wrap(err)
 	}
 
-	valid, err := ValidateMFAPasscode(passcode, auth.User.MFASecretKey, t)
+	if s.mfaAttempts.Limited(auth.User.ID, t) {
+		return ErrMFARateLimit.New(mfaRateLimitErrMsg)
+	}
+
+	valid, err := s.validateMFAPasscode(auth.User.ID, passcode, auth.User.MFASecretKey, t)
 	if err != nil {
 		return ErrValidation.Wrap(ErrMFAPasscode.Wrap(err))
 	}
@@ -142,7 +167,11 @@ func (s *Service) DisableUserMFA(ctx context.Context, passcode string, t time.Ti
 			return ErrUnauthorized.Wrap(ErrMFARecoveryCode.New(mfaRecoveryInvalidErrMsg))
 		}
 	} else if passcode != "" {
-		valid, err := ValidateMFAPasscode(passcode, auth.User.MFASecretKey, t)
+		if s.mfaAttempts.Limited(auth.User.ID, t) {
+			return ErrMFARateLimit.New(mfaRateLimitErrMsg)
+		}
+
+		valid, err := s.validateMFAPasscode(auth.User.ID, passcode, auth.User.MFASecretKey, t)
 		if err != nil {
 			return ErrValidation.Wrap(ErrMFAPasscode.Wrap(err))
 		}
@@ -237,3 +266,17 @@ func (s *Service) ResetMFARecoveryCodes(ctx context.Context) (codes []string, er
 
 	return codes, nil
 }
+
+// GetMFARecoveryCodeCount returns the number of unused MFA recovery codes the user has remaining.
+func (s *Service) GetMFARecoveryCodeCount(ctx context.Context) (count int, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := s.getAuthAndAuditLog(ctx, "get MFA recovery code count")
+	if err != nil {
+		return 0, Error.Wrap(err)
+	}
+
+	// used recovery codes are removed from the user when they are consumed,
+	// so any remaining codes are unused.
+	return len(auth.User.MFARecoveryCodes), nil
+}
@@ -48,8 +48,9 @@ type Projects interface {
 
 // UsageLimitsConfig is a configuration struct for default per-project usage limits.
 type UsageLimitsConfig struct {
-	Storage   StorageLimitConfig
-	Bandwidth BandwidthLimitConfig
+	Storage        StorageLimitConfig
+	Bandwidth      BandwidthLimitConfig
+	AlertThreshold int64 `help:"percentage of a project usage limit at or above which the user is alerted (0=disabled)" default:"80"`
 }
 
 // StorageLimitConfig is a configuration struct for default storage per-project usage limits.
@@ -10,3 +10,13 @@ type ProjectUsageLimits struct {
 	StorageUsed    int64 `json:"storageUsed"`
 	BandwidthUsed  int64 `json:"bandwidthUsed"`
 }
+
+// ProjectUsageAlert describes a project usage limit that is about to be hit.
+type ProjectUsageAlert struct {
+	// Limit is the kind of limit, either "storage" or "bandwidth".
+	Limit   string `json:"limit"`
+	Used    int64  `json:"used"`
+	Maximum int64  `json:"maximum"`
+	// Percent is the integer percentage of the limit that has been used.
+	Percent int64 `json:"percent"`
+}
@@ -8,6 +8,10 @@ import (
 	"net/http"
 )
 
+// RequestIDHeader is the header used to pass the ID of a request between the
+// client and the server.
+const RequestIDHeader = "X-Request-ID"
+
 // WithRequest creates new context with *http.Request.
 func WithRequest(ctx context.Context, req *http.Request) context.Context {
 	return context.WithValue(ctx, requestKey, req)
@@ -20,3 +24,16 @@ func GetRequest(ctx context.Context) *http.Request {
 	}
 	return nil
 }
+
+// WithRequestID creates new context with the ID of the request.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// GetRequestID gets the ID of the request from context.
+func GetRequestID(ctx context.Context) string {
+	if requestID, ok := ctx.Value(requestIDKey).(string); ok {
+		return requestID
+	}
+	return ""
+}
@@ -28,6 +28,7 @@ import (
 	"storj.io/storj/satellite/accounting"
 	"storj.io/storj/satellite/analytics"
 	"storj.io/storj/satellite/console/consoleauth"
+	"storj.io/storj/satellite/metabase"
 	"storj.io/storj/satellite/payments"
 	"storj.io/storj/satellite/rewards"
 )
@@ -39,9 +40,16 @@ const (
 	maxLimit = 50
 
 	// TokenExpirationTime specifies the expiration time for
-	// auth tokens, account recovery tokens, and activation tokens.
+	// auth tokens, account recovery tokens, activation tokens
+	// and email change tokens.
 	TokenExpirationTime = 24 * time.Hour
 
+	// MaxUsageSeriesPoints is the maximum number of points returned in a project usage series.
+	MaxUsageSeriesPoints = 366
+
+	// MaxAPIKeyUsageDays is the maximum number of days returned in an api key usage summary.
+	MaxAPIKeyUsageDays = 30
+
 	// TestPasswordCost is the hashing complexity to use for testing.
 	TestPasswordCost = bcrypt.MinCost
 )
@@ -52,7 +60,13 @@ const (
 	emailUsedErrMsg                      = "This email is already in use, try another"
 	passwordRecoveryTokenIsExpiredErrMsg = "Your password recovery link has expired, please request another one"
 	credentialsErrMsg                    = "Your email or password was incorrect, please try again"
+	emailRateLimitErrMsg                 = "Too many emails have been sent to this account, please try again later"
 	passwordIncorrectErrMsg              = "Your password needs at least %d characters long"
+	passwordDigitErrMsg                  = "Your password needs at least one digit"
+	passwordUpperErrMsg                  = "Your password needs at least one uppercase letter"
+	passwordSymbolErrMsg                 = "Your password needs at least one symbol"
+	emailDomainBlockedErrMsg             = "Registration with this email domain is not allowed, please use another email address"
+	emailDomainNotAllowedErrMsg          = "Registration is restricted to specific email domains, please use another email address"
 	projectOwnerDeletionForbiddenErrMsg  = "%s is a project owner and can not be deleted"
 	apiKeyWithNameExistsErrMsg           = "An API Key with this name already exists in this project, please use a different name"
 	apiKeyWithNameDoesntExistErrMsg      = "An API Key with this name doesn't exist in this project."
@@ -61,6 +75,11 @@ const (
 
 	usedRegTokenErrMsg = "This registration token has already been used"
 	projLimitErrMsg    = "Sorry, project creation is limited for your account. Please contact support!"
+	maxProjLimitErrMsg = "Sorry, the maximum number of projects per account has been reached"
+
+	bucketExistsErrMsg   = "A bucket with this name already exists in this project, please use a different name"
+	bucketNotEmptyErrMsg = "The bucket is not empty, delete its objects first"
+	bucketLimitErrMsg    = "Sorry, this project has reached its bucket limit. Please contact support!"
 )
 
 var (
@@ -93,6 +112,19 @@ var (
 
 	// ErrRecoveryToken describes account recovery token errors.
 	ErrRecoveryToken = errs.Class("recovery token")
+
+	// ErrEmailRateLimit is error type that occurs when an account has received
+	// too many password reset or activation emails.
+	ErrEmailRateLimit = errs.Class("email rate limit")
+
+	// ErrBucketExists is error type that occurs when creating a bucket whose name is already used in the project.
+	ErrBucketExists = errs.Class("bucket exists")
+
+	// ErrBucketNotEmpty is error type that occurs when deleting a bucket that still has objects.
+	ErrBucketNotEmpty = errs.Class("bucket not empty")
+
+	// ErrBucketLimit is error type of bucket limit.
+	ErrBucketLimit = errs.Class("bucket limit")
 )
 
 // Service is handling accounts related logic.
@@ -106,14 +138,21 @@ type Service struct {
 	projectAccounting accounting.ProjectAccounting
 	projectUsage      *accounting.Service
 	buckets           Buckets
+	bucketObjects     BucketObjects
 	partners          *rewards.PartnersService
 	accounts          payments.Accounts
 	recaptchaHandler  RecaptchaHandler
 	analytics         *analytics.Service
+	mfaAttempts       *failedAttempts
+	loginAttempts     *failedAttempts
+	accountEmails     *failedAttempts
+	idempotencyKeys   *idempotencyKeys
+	emailDomains      *emailDomainFilter
 
 	config Config
 
-	minCoinPayment int64
+	minCoinPayment    int64
+	defaultMaxBuckets int
 }
 
 func init() {
@@ -130,11 +169,43 @@ func init() {
 
 // Config keeps track of core console service configuration parameters.
 type Config struct {
-	PasswordCost            int  `help:"password hashing cost (0=automatic)" testDefault:"4" default:"0"`
-	OpenRegistrationEnabled bool `help:"enable open registration" default:"false" testDefault:"true"`
-	DefaultProjectLimit     int  `help:"default project limits for users" default:"3" testDefault:"5"`
-	UsageLimits             UsageLimitsConfig
-	Recaptcha               RecaptchaConfig
+	PasswordCost               int           `help:"password hashing cost (0=automatic)" testDefault:"4" default:"0"`
+	OpenRegistrationEnabled    bool          `help:"enable open registration" default:"false" testDefault:"true"`
+	DefaultProjectLimit        int           `help:"default project limits for users" default:"3" testDefault:"5"`
+	MaxProjectLimit            int           `help:"maximum number of projects of a user, regardless of their project limit (0=unlimited)" default:"0"`
+	MFAPasscodeSkew            uint          `help:"number of time steps before and after the current one in which MFA passcodes are accepted" default:"1"`
+	AccountDeletionGracePeriod time.Duration `help:"how long after a deletion request an account is deleted" default:"720h"`
+	IdempotencyKeyWindow       time.Duration `help:"how long idempotency keys of payment requests are remembered (0=disabled)" default:"10m"`
+	MFARateLimit               MFARateLimitConfig
+	LoginLockout               LoginLockoutConfig
+	EmailRateLimit             EmailRateLimitConfig
+	UsageLimits                UsageLimitsConfig
+	Recaptcha                  RecaptchaConfig
+	PasswordPolicy             PasswordPolicyConfig
+	RegistrationEmailDomains   EmailDomainsConfig
+}
+
+// LoginLockoutConfig contains configurations for locking accounts after failed login attempts.
+//
+// The failed attempts, like the emails and MFA attempts limited below, are only
+// counted in the memory of each API process. They are lost on restart and every
+// replica counts them separately.
+type LoginLockoutConfig struct {
+	MaxAttempts int           `help:"number of failed login attempts within the window after which the account is locked (0=unlimited). attempts are counted in memory by each API process and reset on restart" default:"10"`
+	Window      time.Duration `help:"duration of the window in which failed login attempts are counted and the account stays locked" default:"15m"`
+}
+
+// EmailRateLimitConfig contains configurations for limiting the password reset
+// and activation emails sent to a single account.
+type EmailRateLimitConfig struct {
+	MaxEmails int           `help:"number of password reset and activation emails an account can receive within the window (0=unlimited). emails are counted in memory by each API process and reset on restart" default:"5"`
+	Window    time.Duration `help:"duration of the window in which the password reset and activation emails sent to an account are counted" default:"1h"`
+}
+
+// MFARateLimitConfig contains configurations for limiting failed MFA passcode attempts.
+type MFARateLimitConfig struct {
+	MaxAttempts int           `help:"number of failed MFA passcode attempts within the window after which further attempts are rejected (0=unlimited). attempts are counted in memory by each API process and reset on restart" default:"5"`
+	Window      time.Duration `help:"duration of the window in which failed MFA passcode attempts are counted" default:"15m"`
 }
 
 // RecaptchaConfig contains configurations for the reCAPTCHA system.
@@ -144,13 +215,22 @@ type RecaptchaConfig struct {
 	SecretKey string `help:"reCAPTCHA secret key"`
 }
 
+// PasswordPolicyConfig contains the requirements for passwords set on
+// registration, password reset and password change.
+type PasswordPolicyConfig struct {
+	MinLength     int  `help:"minimum number of characters of user passwords, can't be lower than 6" default:"6"`
+	RequireDigit  bool `help:"whether user passwords must contain a digit" default:"false"`
+	RequireUpper  bool `help:"whether user passwords must contain an uppercase letter" default:"false"`
+	RequireSymbol bool `help:"whether user passwords must contain a symbol or punctuation character" default:"false"`
+}
+
 // PaymentsService separates all payment related functionality.
 type PaymentsService struct {
 	service *Service
 }
 
 // NewService returns new instance of Service.
-func NewService(log *zap.Logger, signer Signer, store DB, projectAccounting accounting.ProjectAccounting, projectUsage *accounting.Service, buckets Buckets, partners *rewards.PartnersService, accounts payments.Accounts, analytics *analytics.Service, config Config, minCoinPayment int64) (*Service, error) {
+func NewService(log *zap.Logger, signer Signer, store DB, projectAccounting accounting.ProjectAccounting, projectUsage *accounting.Service, buckets Buckets, bucketObjects BucketObjects, partners *rewards.PartnersService, accounts payments.Accounts, analytics *analytics.Service, config Config, minCoinPayment int64, defaultMaxBuckets int) (*Service, error) {
 	if signer == nil {
 		return nil, errs.New("signer can't be nil")
 	}
@@ -164,6 +244,11 @@ func NewService(log *zap.Logger, signer Signer, store DB, projectAccounting acco
 		config.PasswordCost = bcrypt.DefaultCost
 	}
 
+	emailDomains, err := newEmailDomainFilter(config.RegistrationEmailDomains)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
 	return &Service{
 		log:               log,
 		auditLogger:       log.Named("auditlog"),
@@ -172,12 +257,19 @@ func NewService(log *zap.Logger, signer Signer, store DB, projectAccounting acco
 		projectAccounting: projectAccounting,
 		projectUsage:      projectUsage,
 		buckets:           buckets,
+		bucketObjects:     bucketObjects,
 		partners:          partners,
 		accounts:          accounts,
 		recaptchaHandler:  NewDefaultRecaptcha(config.Recaptcha.SecretKey),
 		analytics:         analytics,
+		mfaAttempts:       newFailedAttempts(config.MFARateLimit.MaxAttempts, config.MFARateLimit.Window),
+		loginAttempts:     newFailedAttempts(config.LoginLockout.MaxAttempts, config.LoginLockout.Window),
+		accountEmails:     newFailedAttempts(config.EmailRateLimit.MaxEmails, config.EmailRateLimit.Window),
+		idempotencyKeys:   newIdempotencyKeys(config.IdempotencyKeyWindow),
+		emailDomains:      emailDomains,
 		config:            config,
 		minCoinPayment:    minCoinPayment,
+		defaultMaxBuckets: defaultMaxBuckets,
 	}, nil
 }
 
@@ -254,7 +346,9 @@ func (paymentService PaymentsService) AccountBalance(ctx context.Context) (balan
 }
 
 // AddCreditCard is used to save new credit card and attach it to payment account.
-func (paymentService PaymentsService) AddCreditCard(ctx context.Context, creditCardToken string) (err error) {
+// Requests with the same non-empty idempotency key are only performed once
+// within the configured window, retries get the result of the original request.
+func (paymentService PaymentsService) AddCreditCard(ctx context.Context, creditCardToken, idempotencyKey string) (err error) {
 	defer mon.Task()(&ctx, creditCardToken)(&err)
 
 	auth, err := paymentService.service.getAuthAndAuditLog(ctx, "add credit card")
@@ -262,36 +356,71 @@ func (paymentService PaymentsService) AddCreditCard(ctx context.Context, creditC
 		return Error.Wrap(err)
 	}
 
+	return paymentService.service.idempotencyKeys.Do(ctx, auth.User.ID, idempotencyKey, creditCardToken, time.Now(), func() error {
+		return paymentService.addCreditCard(ctx, auth, creditCardToken)
+	})
+}
+
+// addCreditCard attaches the credit card to the payment account of the user
+// and puts them into the paid tier.
+func (paymentService PaymentsService) addCreditCard(ctx context.Context, auth Authorization, creditCardToken string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
 	err = paymentService.service.accounts.CreditCards().Add(ctx, auth.User.ID, creditCardToken)
 	if err != nil {
 		return Error.Wrap(err)
 	}
 
 	if !auth.User.PaidTier {
-		// put this user into the paid tier and convert projects to upgraded limits.
-		err = paymentService.service.store.Users().UpdatePaidTier(ctx, auth.User.ID, true)
-		if err != nil {
-			return Error.Wrap(err)
-		}
+		return paymentService.upgradeToPaidTier(ctx, auth.User.ID)
+	}
+
+	return nil
+}
+
+// InvoicePaid puts the user into the paid tier once one of their invoices has been paid.
+// It's called by the payments webhooks, so it doesn't require an authorized user.
+func (paymentService PaymentsService) InvoicePaid(ctx context.Context, userID uuid.UUID) (err error) {
+	defer mon.Task()(&ctx, userID)(&err)
+
+	user, err := paymentService.service.store.Users().Get(ctx, userID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	if !user.PaidTier {
+		return paymentService.upgradeToPaidTier(ctx, userID)
+	}
 
-		projects, err := paymentService.service.store.Projects().GetOwn(ctx, auth.User.ID)
+	return nil
+}
+
+// upgradeToPaidTier puts the user into the paid tier and converts their projects to upgraded limits.
+func (paymentService PaymentsService) upgradeToPaidTier(ctx context.Context, userID uuid.UUID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = paymentService.service.store.Users().UpdatePaidTier(ctx, userID, true)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	projects, err := paymentService.service.store.Projects().GetOwn(ctx, userID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	for _, project := range projects {
+		if project.StorageLimit == nil || *project.StorageLimit < paymentService.service.config.UsageLimits.Storage.Paid {
+			project.StorageLimit = new(memory.Size)
+			*project.StorageLimit = paymentService.service.config.UsageLimits.Storage.Paid
+		}
+		if project.BandwidthLimit == nil || *project.BandwidthLimit < paymentService.service.config.UsageLimits.Bandwidth.Paid {
+			project.BandwidthLimit = new(memory.Size)
+			*project.BandwidthLimit = paymentService.service.config.UsageLimits.Bandwidth.Paid
+		}
+		err = paymentService.service.store.Projects().Update(ctx, &project)
 		if err != nil {
 			return Error.Wrap(err)
 		}
-		for _, project := range projects {
-			if project.StorageLimit == nil || *project.StorageLimit < paymentService.service.config.UsageLimits.Storage.Paid {
-				project.StorageLimit = new(memory.Size)
-				*project.StorageLimit = paymentService.service.config.UsageLimits.Storage.Paid
-			}
-			if project.BandwidthLimit == nil || *project.BandwidthLimit < paymentService.service.config.UsageLimits.Bandwidth.Paid {
-				project.BandwidthLimit = new(memory.Size)
-				*project.BandwidthLimit = paymentService.service.config.UsageLimits.Bandwidth.Paid
-			}
-			err = paymentService.service.store.Projects().Update(ctx, &project)
-			if err != nil {
-				return Error.Wrap(err)
-			}
-		}
 	}
 
 	return nil
@@ -491,6 +620,20 @@ func (paymentService PaymentsService) TokenDeposit(ctx context.Context, amount i
 	return tx, Error.Wrap(err)
 }
 
+// GetTokenDeposit returns the deposit transaction of the user with the given id.
+func (paymentService PaymentsService) GetTokenDeposit(ctx context.Context, id string) (_ *payments.TransactionInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := paymentService.service.getAuthAndAuditLog(ctx, "get token deposit", zap.String("transactionID", id))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	tx, err := paymentService.service.accounts.StorjTokens().GetTransactionInfo(ctx, auth.User.ID, payments.TransactionID(id))
+
+	return tx, Error.Wrap(err)
+}
+
 // checkOutstandingInvoice returns if the payment account has any unpaid/outstanding invoices or/and invoice items.
 func (paymentService PaymentsService) checkOutstandingInvoice(ctx context.Context) (err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -611,6 +754,14 @@ func (s *Service) CreateUser(ctx context.Context, user CreateUser, tokenSecret R
 		return nil, Error.Wrap(err)
 	}
 
+	if err := s.config.PasswordPolicy.Validate(user.Password); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	if err := s.emailDomains.Check(user.Email); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
 	registrationToken, err := s.checkRegistrationSecret(ctx, tokenSecret)
 	if err != nil {
 		return nil, ErrRegToken.Wrap(err)
@@ -699,6 +850,10 @@ func (s *Service) TestSwapRecaptchaHandler(h RecaptchaHandler) {
 func (s *Service) GenerateActivationToken(ctx context.Context, id uuid.UUID, email string) (token string, err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	if err := s.limitAccountEmails(id); err != nil {
+		return "", err
+	}
+
 	// TODO: activation token should differ from auth token
 	claims := &consoleauth.Claims{
 		ID:         id,
@@ -713,6 +868,10 @@ func (s *Service) GenerateActivationToken(ctx context.Context, id uuid.UUID, ema
 func (s *Service) GeneratePasswordRecoveryToken(ctx context.Context, id uuid.UUID) (token string, err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	if err := s.limitAccountEmails(id); err != nil {
+		return "", err
+	}
+
 	resetPasswordToken, err := s.store.ResetPasswordTokens().GetByOwnerID(ctx, id)
 	if err == nil {
 		err := s.store.ResetPasswordTokens().Delete(ctx, resetPasswordToken.Secret)
@@ -731,6 +890,19 @@ func (s *Service) GeneratePasswordRecoveryToken(ctx context.Context, id uuid.UUI
 	return resetPasswordToken.Secret.String(), nil
 }
 
+// limitAccountEmails counts an email about to be sent to the user, and returns
+// an ErrEmailRateLimit error instead when the user has already received too many
+// emails within the window, regardless of where the requests came from.
+func (s *Service) limitAccountEmails(userID uuid.UUID) error {
+	now := time.Now()
+	if s.accountEmails.Limited(userID, now) {
+		return ErrEmailRateLimit.New(emailRateLimitErrMsg)
+	}
+	s.accountEmails.Fail(userID, now)
+
+	return nil
+}
+
 // ActivateAccount - is a method for activating user account after registration.
 func (s *Service) ActivateAccount(ctx context.Context, activationToken string) (err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -791,7 +963,7 @@ func (s *Service) ResetPassword(ctx context.Context, resetPasswordToken, passwor
 		return Error.Wrap(err)
 	}
 
-	if err := ValidatePassword(password); err != nil {
+	if err := s.config.PasswordPolicy.Validate(password); err != nil {
 		return Error.Wrap(err)
 	}
 
@@ -840,10 +1012,21 @@ func (s *Service) Token(ctx context.Context, request AuthUser) (token string, er
 		return "", ErrUnauthorized.New(credentialsErrMsg)
 	}
 
+	now := time.Now()
 	err = bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(request.Password))
 	if err != nil {
+		s.loginAttempts.Fail(user.ID, now)
+		return "", ErrUnauthorized.New(credentialsErrMsg)
+	}
+
+	// a locked account is rejected with the same error as wrong credentials,
+	// and only once the password is checked, so that the response doesn't
+	// reveal whether an account exists or whether a guessed password is right.
+	if s.loginAttempts.Limited(user.ID, now) {
+		mon.Counter("login_locked_account").Inc(1)
 		return "", ErrUnauthorized.New(credentialsErrMsg)
 	}
+	s.loginAttempts.Reset(user.ID)
 
 	if user.MFAEnabled {
 		if request.MFARecoveryCode != "" && request.MFAPasscode != "" {
@@ -871,7 +1054,11 @@ func (s *Service) Token(ctx context.Context, request AuthUser) (token string, er
 				return "", err
 			}
 		} else if request.MFAPasscode != "" {
-			valid, err := ValidateMFAPasscode(request.MFAPasscode, user.MFASecretKey, time.Now())
+			if s.mfaAttempts.Limited(user.ID, now) {
+				return "", ErrMFARateLimit.New(mfaRateLimitErrMsg)
+			}
+
+			valid, err := s.validateMFAPasscode(user.ID, request.MFAPasscode, user.MFASecretKey, now)
 			if err != nil {
 				return "", ErrUnauthorized.Wrap(err)
 			}
@@ -883,12 +1070,7 @@ func (s *Service) Token(ctx context.Context, request AuthUser) (token string, er
 		}
 	}
 
-	claims := consoleauth.Claims{
-		ID:         user.ID,
-		Expiration: time.Now().Add(TokenExpirationTime),
-	}
-
-	token, err = s.createToken(ctx, &claims)
+	token, err = s.createSessionToken(ctx, user.ID, user.TokenEpoch)
 	if err != nil {
 		return "", err
 	}
@@ -935,27 +1117,40 @@ func (s *Service) GetUserByEmail(ctx context.Context, email string) (u *User, er
 }
 
 // UpdateAccount updates User.
-func (s *Service) UpdateAccount(ctx context.Context, fullName string, shortName string) (err error) {
+func (s *Service) UpdateAccount(ctx context.Context, info UserInfo) (err error) {
 	defer mon.Task()(&ctx)(&err)
 	auth, err := s.getAuthAndAuditLog(ctx, "update account")
 	if err != nil {
 		return Error.Wrap(err)
 	}
 
-	// validate fullName
-	err = ValidateFullName(fullName)
+	current := UserInfo{
+		FullName:         auth.User.FullName,
+		ShortName:        auth.User.ShortName,
+		IsProfessional:   auth.User.IsProfessional,
+		Position:         auth.User.Position,
+		CompanyName:      auth.User.CompanyName,
+		EmployeeCount:    auth.User.EmployeeCount,
+		HaveSalesContact: auth.User.HaveSalesContact,
+	}
+
+	info.Normalize()
+	err = info.IsValidUpdate(current)
 	if err != nil {
 		return ErrValidation.Wrap(err)
 	}
 
-	err = s.store.Users().Update(ctx, &User{
-		ID:           auth.User.ID,
-		FullName:     fullName,
-		ShortName:    shortName,
-		Email:        auth.User.Email,
-		PasswordHash: nil,
-		Status:       auth.User.Status,
-	})
+	user := auth.User
+	user.FullName = info.FullName
+	user.ShortName = info.ShortName
+	user.IsProfessional = info.IsProfessional
+	user.Position = info.Position
+	user.CompanyName = info.CompanyName
+	user.EmployeeCount = info.EmployeeCount
+	user.HaveSalesContact = info.HaveSalesContact
+	user.PasswordHash = nil
+
+	err = s.store.Users().Update(ctx, &user)
 	if err != nil {
 		return Error.Wrap(err)
 	}
@@ -963,56 +1158,142 @@ func (s *Service) UpdateAccount(ctx context.Context, fullName string, shortName
 	return nil
 }
 
-// ChangeEmail updates email for a given user.
-func (s *Service) ChangeEmail(ctx context.Context, newEmail string) (err error) {
+// ChangeEmail starts changing the email of a given user. It returns a token which
+// has to be sent to the new email address and confirmed with ConfirmEmailChange
+// before the email is changed. The old email stays in use until then.
+func (s *Service) ChangeEmail(ctx context.Context, newEmail string) (token string, err error) {
 	defer mon.Task()(&ctx)(&err)
-	auth, err := s.getAuthAndAuditLog(ctx, "change email")
+	auth, err := s.getAuthAndAuditLog(ctx, "change email", zap.String("newEmail", newEmail))
 	if err != nil {
-		return Error.Wrap(err)
+		return "", Error.Wrap(err)
 	}
 
 	if _, err := mail.ParseAddress(newEmail); err != nil {
-		return ErrValidation.Wrap(err)
+		return "", ErrValidation.Wrap(err)
+	}
+
+	// users can't move to an email domain they couldn't register with.
+	if err := s.emailDomains.Check(newEmail); err != nil {
+		return "", Error.Wrap(err)
 	}
 
 	_, err = s.store.Users().GetByEmail(ctx, newEmail)
+	if err == nil {
+		return "", ErrEmailUsed.New(emailUsedErrMsg)
+	}
+
+	claims := &consoleauth.Claims{
+		ID:         auth.User.ID,
+		Email:      auth.User.Email,
+		NewEmail:   newEmail,
+		Expiration: time.Now().Add(TokenExpirationTime),
+	}
+
+	return s.createToken(ctx, claims)
+}
+
+// ConfirmEmailChange changes the email of the user the email change token was
+// created for to the confirmed new email.
+func (s *Service) ConfirmEmailChange(ctx context.Context, emailChangeToken string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	token, err := consoleauth.FromBase64URLString(emailChangeToken)
+	if err != nil {
+		return ErrValidation.Wrap(err)
+	}
+
+	claims, err := s.authenticate(ctx, token)
+	if err != nil {
+		return ErrValidation.Wrap(err)
+	}
+
+	if claims.NewEmail == "" {
+		return ErrValidation.New("not an email change token")
+	}
+
+	if claims.Expiration.Before(time.Now()) {
+		return ErrTokenExpiration.New("email change token has expired")
+	}
+
+	user, err := s.store.Users().Get(ctx, claims.ID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	// the email has been changed since the token was created.
+	if user.Email != claims.Email {
+		return ErrTokenExpiration.New("email change token is no longer valid")
+	}
+
+	_, err = s.store.Users().GetByEmail(ctx, claims.NewEmail)
 	if err == nil {
 		return ErrEmailUsed.New(emailUsedErrMsg)
 	}
 
-	auth.User.Email = newEmail
-	err = s.store.Users().Update(ctx, &auth.User)
+	user.Email = claims.NewEmail
+	user.PasswordHash = nil
+	err = s.store.Users().Update(ctx, user)
 	if err != nil {
 		return Error.Wrap(err)
 	}
 
+	s.auditLog(ctx, "confirm email change", &user.ID, user.Email, zap.String("oldEmail", claims.Email))
+
 	return nil
 }
 
-// ChangePassword updates password for a given user.
-func (s *Service) ChangePassword(ctx context.Context, pass, newPass string) (err error) {
+// ChangePassword updates password for a given user. Every token previously issued
+// to the user is invalidated, and a fresh token is returned for the current session.
+func (s *Service) ChangePassword(ctx context.Context, pass, newPass string) (token string, err error) {
 	defer mon.Task()(&ctx)(&err)
 	auth, err := s.getAuthAndAuditLog(ctx, "change password")
 	if err != nil {
-		return Error.Wrap(err)
+		return "", Error.Wrap(err)
 	}
 
 	err = bcrypt.CompareHashAndPassword(auth.User.PasswordHash, []byte(pass))
 	if err != nil {
-		return ErrUnauthorized.New(credentialsErrMsg)
+		return "", ErrUnauthorized.New(credentialsErrMsg)
 	}
 
-	if err := ValidatePassword(newPass); err != nil {
-		return ErrValidation.Wrap(err)
+	if err := s.config.PasswordPolicy.Validate(newPass); err != nil {
+		return "", ErrValidation.Wrap(err)
 	}
 
 	hash, err := bcrypt.GenerateFromPassword([]byte(newPass), s.config.PasswordCost)
 	if err != nil {
-		return Error.Wrap(err)
+		return "", Error.Wrap(err)
 	}
 
 	auth.User.PasswordHash = hash
 	err = s.store.Users().Update(ctx, &auth.User)
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+
+	epoch, err := s.store.Users().BumpTokenEpoch(ctx, auth.User.ID)
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+
+	token, err = s.createSessionToken(ctx, auth.User.ID, epoch)
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// LogoutAll invalidates every token that has been issued to the authorized user,
+// including the one used for this request.
+func (s *Service) LogoutAll(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	auth, err := s.getAuthAndAuditLog(ctx, "logout all")
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	_, err = s.store.Users().BumpTokenEpoch(ctx, auth.User.ID)
 	if err != nil {
 		return Error.Wrap(err)
 	}
@@ -1046,6 +1327,48 @@ func (s *Service) DeleteAccount(ctx context.Context, password string) (err error
 	return nil
 }
 
+// RequestAccountDeletion marks the account of the authorized user for deletion
+// once the grace period has passed, and returns when it will be deleted. The
+// user must not own any projects or have any unpaid usage.
+func (s *Service) RequestAccountDeletion(ctx context.Context, password string) (deleteAt time.Time, err error) {
+	defer mon.Task()(&ctx)(&err)
+	auth, err := s.getAuthAndAuditLog(ctx, "request account deletion")
+	if err != nil {
+		return time.Time{}, Error.Wrap(err)
+	}
+
+	err = bcrypt.CompareHashAndPassword(auth.User.PasswordHash, []byte(password))
+	if err != nil {
+		return time.Time{}, ErrUnauthorized.New(credentialsErrMsg)
+	}
+
+	if auth.User.DeletionRequestedAt != nil {
+		return auth.User.DeletionRequestedAt.Add(s.config.AccountDeletionGracePeriod), nil
+	}
+
+	projects, err := s.store.Projects().GetOwn(ctx, auth.User.ID)
+	if err != nil {
+		return time.Time{}, Error.Wrap(err)
+	}
+	if len(projects) > 0 {
+		return time.Time{}, ErrUsage.New("user still owns %d project(s)", len(projects))
+	}
+
+	err = s.Payments().checkOutstandingInvoice(ctx)
+	if err != nil {
+		return time.Time{}, Error.Wrap(err)
+	}
+
+	now := time.Now()
+	auth.User.DeletionRequestedAt = &now
+	err = s.store.Users().Update(ctx, &auth.User)
+	if err != nil {
+		return time.Time{}, Error.Wrap(err)
+	}
+
+	return now.Add(s.config.AccountDeletionGracePeriod), nil
+}
+
 // GetProject is a method for querying project by id.
 func (s *Service) GetProject(ctx context.Context, projectID uuid.UUID) (p *Project, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -1418,6 +1741,48 @@ func (s *Service) CreateAPIKey(ctx context.Context, projectID uuid.UUID, name st
 	return info, key, nil
 }
 
+// RotateAPIKey replaces the secret of an api key, keeping its name and project.
+// The macaroons derived from the previous secret stop authorizing requests;
+// satellite processes that cached the previous key accept it until their
+// cache entry expires. The new key is only returned by this call.
+func (s *Service) RotateAPIKey(ctx context.Context, id uuid.UUID) (_ *APIKeyInfo, _ *macaroon.APIKey, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := s.getAuthAndAuditLog(ctx, "rotate api key", zap.String("apiKeyID", id.String()))
+	if err != nil {
+		return nil, nil, Error.Wrap(err)
+	}
+
+	info, err := s.store.APIKeys().Get(ctx, id)
+	if err != nil {
+		return nil, nil, Error.Wrap(err)
+	}
+
+	_, err = s.isProjectMember(ctx, auth.User.ID, info.ProjectID)
+	if err != nil {
+		return nil, nil, ErrUnauthorized.Wrap(err)
+	}
+
+	secret, err := macaroon.NewSecret()
+	if err != nil {
+		return nil, nil, Error.Wrap(err)
+	}
+
+	key, err := macaroon.NewAPIKey(secret)
+	if err != nil {
+		return nil, nil, Error.Wrap(err)
+	}
+
+	err = s.store.APIKeys().UpdateSecret(ctx, id, key.Head(), secret)
+	if err != nil {
+		return nil, nil, Error.Wrap(err)
+	}
+
+	info.Secret = secret
+
+	return info, key, nil
+}
+
 // GetAPIKeyInfo retrieves api key by id.
 func (s *Service) GetAPIKeyInfo(ctx context.Context, id uuid.UUID) (_ *APIKeyInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -1540,6 +1905,64 @@ func (s *Service) GetAPIKeys(ctx context.Context, projectID uuid.UUID, cursor AP
 	return
 }
 
+// GetAPIKeyUsage returns the number of requests the api key authorized on each
+// of the last days, including today. The number of days is capped at
+// MaxAPIKeyUsageDays.
+func (s *Service) GetAPIKeyUsage(ctx context.Context, keyID uuid.UUID, days int) (_ *APIKeyUsage, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := s.getAuthAndAuditLog(ctx, "get api key usage", zap.String("apiKeyID", keyID.String()))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	if days <= 0 {
+		return nil, ErrValidation.New("days must be positive")
+	}
+	if days > MaxAPIKeyUsageDays {
+		days = MaxAPIKeyUsageDays
+	}
+
+	key, err := s.store.APIKeys().Get(ctx, keyID)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	_, err = s.isProjectMember(ctx, auth.User.ID, key.ProjectID)
+	if err != nil {
+		return nil, ErrUnauthorized.Wrap(err)
+	}
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	since := today.AddDate(0, 0, -days+1)
+
+	recorded, err := s.store.APIKeys().GetUsage(ctx, keyID, since)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	const dayFormat = "2006-01-02"
+
+	requests := make(map[string]int64, len(recorded))
+	for _, day := range recorded {
+		requests[day.Day.Format(dayFormat)] = day.Requests
+	}
+
+	usage := &APIKeyUsage{
+		APIKeyID: keyID,
+		Days:     make([]APIKeyDailyUsage, 0, days),
+	}
+	for day := since; !day.After(today); day = day.AddDate(0, 0, 1) {
+		usage.Days = append(usage.Days, APIKeyDailyUsage{
+			Day:      day,
+			Requests: requests[day.Format(dayFormat)],
+		})
+	}
+
+	return usage, nil
+}
+
 // GetProjectUsage retrieves project usage for a given period.
 func (s *Service) GetProjectUsage(ctx context.Context, projectID uuid.UUID, since, before time.Time) (_ *accounting.ProjectUsage, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -1562,6 +1985,46 @@ func (s *Service) GetProjectUsage(ctx context.Context, projectID uuid.UUID, sinc
 	return projectUsage, nil
 }
 
+// GetProjectUsageSeries retrieves project storage and egress for every interval between since and before.
+// When the period spans more than MaxUsageSeriesPoints intervals only the most recent ones are returned.
+func (s *Service) GetProjectUsageSeries(ctx context.Context, projectID uuid.UUID, since, before time.Time, interval time.Duration) (_ *accounting.ProjectUsageSeries, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := s.getAuthAndAuditLog(ctx, "get project usage series", zap.String("projectID", projectID.String()))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	_, err = s.isProjectMember(ctx, auth.User.ID, projectID)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	if interval <= 0 {
+		return nil, ErrValidation.New("interval must be positive")
+	}
+	if !since.Before(before) {
+		return nil, ErrValidation.New("since must be earlier than before")
+	}
+
+	// the series is aligned to the interval, so the earliest start that still
+	// fits into the allowed number of points is rounded up to the interval.
+	earliest := before.Add(-MaxUsageSeriesPoints * interval)
+	if aligned := earliest.Truncate(interval); aligned.Before(earliest) {
+		earliest = aligned.Add(interval)
+	}
+	if since.Before(earliest) {
+		since = earliest
+	}
+
+	series, err := s.projectAccounting.GetProjectUsageSeries(ctx, projectID, since, before, interval)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return series, nil
+}
+
 // GetBucketTotals retrieves paged bucket total usages since project creation.
 func (s *Service) GetBucketTotals(ctx context.Context, projectID uuid.UUID, cursor accounting.BucketUsageCursor, before time.Time) (_ *accounting.BucketUsagePage, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -1619,6 +2082,165 @@ func (s *Service) GetAllBucketNames(ctx context.Context, projectID uuid.UUID) (_
 	return list, nil
 }
 
+// CreateBucket creates a new bucket with the given name in the project.
+func (s *Service) CreateBucket(ctx context.Context, projectID uuid.UUID, name string) (_ storj.Bucket, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := s.getAuthAndAuditLog(ctx, "create bucket", zap.String("projectID", projectID.String()), zap.String("bucket", name))
+	if err != nil {
+		return storj.Bucket{}, Error.Wrap(err)
+	}
+
+	_, err = s.isProjectMember(ctx, auth.User.ID, projectID)
+	if err != nil {
+		return storj.Bucket{}, Error.Wrap(err)
+	}
+
+	if err = s.bucketObjects.ValidateBucketName([]byte(name)); err != nil {
+		return storj.Bucket{}, ErrValidation.Wrap(err)
+	}
+
+	_, err = s.buckets.GetBucket(ctx, []byte(name), projectID)
+	if err == nil {
+		return storj.Bucket{}, ErrBucketExists.New(bucketExistsErrMsg)
+	}
+	if !storj.ErrBucketNotFound.Has(err) {
+		return storj.Bucket{}, Error.Wrap(err)
+	}
+
+	maxBuckets, err := s.store.Projects().GetMaxBuckets(ctx, projectID)
+	if err != nil {
+		return storj.Bucket{}, Error.Wrap(err)
+	}
+	limit := s.defaultMaxBuckets
+	if maxBuckets != nil {
+		limit = *maxBuckets
+	}
+
+	count, err := s.buckets.CountBuckets(ctx, projectID)
+	if err != nil {
+		return storj.Bucket{}, Error.Wrap(err)
+	}
+	if count >= limit {
+		return storj.Bucket{}, ErrBucketLimit.New(bucketLimitErrMsg)
+	}
+
+	bucketID, err := uuid.New()
+	if err != nil {
+		return storj.Bucket{}, Error.Wrap(err)
+	}
+
+	bucket, err := s.buckets.CreateBucket(ctx, storj.Bucket{
+		ID:        bucketID,
+		Name:      name,
+		ProjectID: projectID,
+	})
+	if err != nil {
+		return storj.Bucket{}, Error.Wrap(err)
+	}
+
+	return bucket, nil
+}
+
+// DeleteBucket deletes the bucket with the given name from the project.
+// A bucket that still has objects is only deleted, together with its
+// objects, when force is set. The pieces of those objects are left on the
+// storage nodes for garbage collection.
+func (s *Service) DeleteBucket(ctx context.Context, projectID uuid.UUID, name string, force bool) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := s.getAuthAndAuditLog(ctx, "delete bucket", zap.String("projectID", projectID.String()), zap.String("bucket", name), zap.Bool("force", force))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	_, err = s.isProjectMember(ctx, auth.User.ID, projectID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	_, err = s.buckets.GetBucket(ctx, []byte(name), projectID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	empty, err := s.bucketObjects.IsBucketEmpty(ctx, projectID, []byte(name))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if !empty {
+		if !force {
+			return ErrBucketNotEmpty.New(bucketNotEmptyErrMsg)
+		}
+
+		deleted, err := s.bucketObjects.DeleteBucketObjects(ctx, projectID, []byte(name))
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		s.log.Info("deleted bucket objects", zap.Stringer("projectID", projectID), zap.String("bucket", name), zap.Int64("objects", deleted))
+	}
+
+	return Error.Wrap(s.buckets.DeleteBucket(ctx, []byte(name), projectID))
+}
+
+// ListBucketObjects returns a page of the objects and prefixes of the bucket which are under the prefix,
+// starting after the cursor. The cursor and the returned keys are relative to the prefix.
+func (s *Service) ListBucketObjects(ctx context.Context, projectID uuid.UUID, bucketName string, prefix, cursor string, limit int) (_ []metabase.ObjectEntry, more bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := s.getAuthAndAuditLog(ctx, "list bucket objects", zap.String("projectID", projectID.String()), zap.String("bucket", bucketName))
+	if err != nil {
+		return nil, false, Error.Wrap(err)
+	}
+
+	_, err = s.isProjectMember(ctx, auth.User.ID, projectID)
+	if err != nil {
+		return nil, false, Error.Wrap(err)
+	}
+
+	if limit < 0 {
+		return nil, false, ErrValidation.New("limit can't be negative")
+	}
+
+	_, err = s.buckets.GetBucket(ctx, []byte(bucketName), projectID)
+	if err != nil {
+		return nil, false, Error.Wrap(err)
+	}
+
+	entries, more, err := s.bucketObjects.ListBucketObjects(ctx, projectID, []byte(bucketName), metabase.ObjectKey(prefix), metabase.ObjectKey(cursor), limit)
+	if err != nil {
+		return nil, false, Error.Wrap(err)
+	}
+
+	return entries, more, nil
+}
+
+// GetBucketObject returns the latest version of an object of the bucket.
+func (s *Service) GetBucketObject(ctx context.Context, projectID uuid.UUID, bucketName string, key string) (_ metabase.Object, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := s.getAuthAndAuditLog(ctx, "get bucket object", zap.String("projectID", projectID.String()), zap.String("bucket", bucketName))
+	if err != nil {
+		return metabase.Object{}, Error.Wrap(err)
+	}
+
+	_, err = s.isProjectMember(ctx, auth.User.ID, projectID)
+	if err != nil {
+		return metabase.Object{}, Error.Wrap(err)
+	}
+
+	if key == "" {
+		return metabase.Object{}, ErrValidation.New("object key can't be empty")
+	}
+
+	object, err := s.bucketObjects.GetBucketObject(ctx, projectID, []byte(bucketName), metabase.ObjectKey(key))
+	if err != nil {
+		return metabase.Object{}, Error.Wrap(err)
+	}
+
+	return object, nil
+}
+
 // GetBucketUsageRollups retrieves summed usage rollups for every bucket of particular project for a given period.
 func (s *Service) GetBucketUsageRollups(ctx context.Context, projectID uuid.UUID, since, before time.Time) (_ []accounting.BucketUsageRollup, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -1670,6 +2292,54 @@ func (s *Service) GetProjectUsageLimits(ctx context.Context, projectID uuid.UUID
 	}, nil
 }
 
+// GetProjectUsageAlerts returns the usage limits of the project that are used
+// at or above the configured alert threshold.
+func (s *Service) GetProjectUsageAlerts(ctx context.Context, projectID uuid.UUID) (_ []ProjectUsageAlert, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := s.getAuthAndAuditLog(ctx, "get project usage alerts", zap.String("projectID", projectID.String()))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	if _, err = s.isProjectMember(ctx, auth.User.ID, projectID); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	// to return empty slice instead of nil if there are no alerts
+	alerts := make([]ProjectUsageAlert, 0)
+
+	threshold := s.config.UsageLimits.AlertThreshold
+	if threshold <= 0 {
+		return alerts, nil
+	}
+
+	usageLimits, err := s.getProjectUsageLimits(ctx, projectID)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	addAlert := func(limit string, used, maximum int64) {
+		if maximum <= 0 {
+			return
+		}
+		percent := used * 100 / maximum
+		if percent >= threshold {
+			alerts = append(alerts, ProjectUsageAlert{
+				Limit:   limit,
+				Used:    used,
+				Maximum: maximum,
+				Percent: percent,
+			})
+		}
+	}
+
+	addAlert("storage", usageLimits.StorageUsed, usageLimits.StorageLimit)
+	addAlert("bandwidth", usageLimits.BandwidthUsed, usageLimits.BandwidthLimit)
+
+	return alerts, nil
+}
+
 // GetTotalUsageLimits returns total limits and current usage for all the projects.
 func (s *Service) GetTotalUsageLimits(ctx context.Context) (_ *ProjectUsageLimits, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -1709,33 +2379,90 @@ func (s *Service) GetTotalUsageLimits(ctx context.Context) (_ *ProjectUsageLimit
 	}, nil
 }
 
-func (s *Service) getProjectUsageLimits(ctx context.Context, projectID uuid.UUID) (_ *ProjectUsageLimits, err error) {
+// GetProjectLimits returns the limits of the project without its current usage.
+func (s *Service) GetProjectLimits(ctx context.Context, projectID uuid.UUID) (_ *ProjectUsageLimits, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	storageLimit, err := s.projectUsage.GetProjectStorageLimit(ctx, projectID)
+	auth, err := s.getAuthAndAuditLog(ctx, "get project limits", zap.String("projectID", projectID.String()))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	if _, err = s.isProjectMember(ctx, auth.User.ID, projectID); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	limits, err := s.getProjectLimits(ctx, projectID)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return limits, nil
+}
+
+// GetTotalLimits returns the total limits of all the projects the user owns without their current usage.
+func (s *Service) GetTotalLimits(ctx context.Context) (_ *ProjectUsageLimits, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := s.getAuthAndAuditLog(ctx, "get total limits for all the projects")
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	projects, err := s.store.Projects().GetOwn(ctx, auth.User.ID)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	var total ProjectUsageLimits
+	for _, pr := range projects {
+		limits, err := s.getProjectLimits(ctx, pr.ID)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+
+		total.StorageLimit += limits.StorageLimit
+		total.BandwidthLimit += limits.BandwidthLimit
+	}
+
+	return &total, nil
+}
+
+// getProjectLimits returns the current limits of the project. They aren't
+// served from the project limit cache, so that updated limits are shown
+// right away.
+func (s *Service) getProjectLimits(ctx context.Context, projectID uuid.UUID) (_ *ProjectUsageLimits, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	limits, err := s.projectUsage.GetProjectLimits(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
-	bandwidthLimit, err := s.projectUsage.GetProjectBandwidthLimit(ctx, projectID)
+
+	return &ProjectUsageLimits{
+		StorageLimit:   *limits.Usage,
+		BandwidthLimit: *limits.Bandwidth,
+	}, nil
+}
+
+func (s *Service) getProjectUsageLimits(ctx context.Context, projectID uuid.UUID) (_ *ProjectUsageLimits, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	usageLimits, err := s.getProjectLimits(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
 
-	storageUsed, err := s.projectUsage.GetProjectStorageTotals(ctx, projectID)
+	usageLimits.StorageUsed, err = s.projectUsage.GetProjectStorageTotals(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
-	bandwidthUsed, err := s.projectUsage.GetProjectBandwidthTotals(ctx, projectID)
+	usageLimits.BandwidthUsed, err = s.projectUsage.GetProjectBandwidthTotals(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ProjectUsageLimits{
-		StorageLimit:   storageLimit.Int64(),
-		BandwidthLimit: bandwidthLimit.Int64(),
-		StorageUsed:    storageUsed,
-		BandwidthUsed:  bandwidthUsed,
-	}, nil
+	return usageLimits, nil
 }
 
 // Authorize validates token from context and returns authorized Authorization.
@@ -1812,6 +2539,11 @@ func (s *Service) checkProjectLimit(ctx context.Context, userID uuid.UUID) (curr
 		return 0, Error.Wrap(err)
 	}
 
+	// the maximum is a hard cap which applies even if the project limit of the user is higher.
+	if s.config.MaxProjectLimit > 0 && len(projects) >= s.config.MaxProjectLimit {
+		return 0, ErrValidation.New(maxProjLimitErrMsg)
+	}
+
 	if len(projects) >= limit {
 		return 0, ErrProjLimit.New(projLimitErrMsg)
 	}
@@ -1848,6 +2580,20 @@ func (s *Service) createToken(ctx context.Context, claims *consoleauth.Claims) (
 	return token.String(), nil
 }
 
+// createSessionToken creates a login token for the user that is valid as long
+// as the token epoch of the user is the given epoch.
+func (s *Service) createSessionToken(ctx context.Context, userID uuid.UUID, epoch int64) (_ string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	claims := &consoleauth.Claims{
+		ID:         userID,
+		Expiration: time.Now().Add(TokenExpirationTime),
+		Epoch:      epoch,
+	}
+
+	return s.createToken(ctx, claims)
+}
+
 // authenticate validates token signature and returns authenticated *satelliteauth.Authorization.
 func (s *Service) authenticate(ctx context.Context, token consoleauth.Token) (_ *consoleauth.Claims, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -1877,11 +2623,21 @@ func (s *Service) authorize(ctx context.Context, claims *consoleauth.Claims) (_
 		return nil, ErrTokenExpiration.New("")
 	}
 
+	// email change tokens are sent to an unconfirmed address, so they
+	// must not grant access to the account.
+	if claims.NewEmail != "" {
+		return nil, ErrUnauthorized.New("not an auth token")
+	}
+
 	user, err := s.store.Users().Get(ctx, claims.ID)
 	if err != nil {
 		return nil, ErrValidation.New("authorization failed. no user with id: %s", claims.ID.String())
 	}
 
+	if claims.Epoch != user.TokenEpoch {
+		return nil, ErrTokenExpiration.New("token has been revoked")
+	}
+
 	return user, nil
 }
 
@@ -5,6 +5,7 @@ package console_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -72,7 +73,7 @@ func TestService(t *testing.T) {
 				authCtx1, err := sat.AuthenticatedContext(ctx, user.ID)
 				require.NoError(t, err)
 				// add a credit card to put the user in the paid tier
-				err = service.Payments().AddCreditCard(authCtx1, "test-cc-token")
+				err = service.Payments().AddCreditCard(authCtx1, "test-cc-token", "")
 				require.NoError(t, err)
 				// update auth ctx
 				authCtx1, err = sat.AuthenticatedContext(ctx, user.ID)
@@ -178,14 +179,21 @@ func TestService(t *testing.T) {
 			t.Run("TestChangeEmail", func(t *testing.T) {
 				const newEmail = "newEmail@example.com"
 
-				err = service.ChangeEmail(authCtx2, newEmail)
+				token, err := service.ChangeEmail(authCtx2, newEmail)
+				require.NoError(t, err)
+
+				// the email isn't changed until it is confirmed.
+				_, err = service.GetUserByEmail(authCtx2, newEmail)
+				require.Error(t, err)
+
+				err = service.ConfirmEmailChange(ctx, token)
 				require.NoError(t, err)
 
 				userWithUpdatedEmail, err := service.GetUserByEmail(authCtx2, newEmail)
 				require.NoError(t, err)
 				require.Equal(t, newEmail, userWithUpdatedEmail.Email)
 
-				err = service.ChangeEmail(authCtx2, newEmail)
+				_, err = service.ChangeEmail(authCtx2, newEmail)
 				require.Error(t, err)
 			})
 
@@ -291,7 +299,7 @@ func TestPaidTier(t *testing.T) {
 		require.NoError(t, err)
 
 		// add a credit card to the user
-		err = service.Payments().AddCreditCard(authCtx, "test-cc-token")
+		err = service.Payments().AddCreditCard(authCtx, "test-cc-token", "")
 		require.NoError(t, err)
 
 		// expect user to be in paid tier
@@ -514,6 +522,204 @@ func TestMFA(t *testing.T) {
 	})
 }
 
+func TestMFAPasscodeSkew(t *testing.T) {
+	for _, skew := range []uint{0, 1} {
+		skew := skew
+		t.Run(fmt.Sprintf("Skew_%d", skew), func(t *testing.T) {
+			testplanet.Run(t, testplanet.Config{
+				SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+				Reconfigure: testplanet.Reconfigure{
+					Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+						config.Console.MFAPasscodeSkew = skew
+					},
+				},
+			}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+				sat := planet.Satellites[0]
+				service := sat.API.Console.Service
+
+				user, err := sat.AddUser(ctx, console.CreateUser{
+					FullName: "MFA Skew Test User",
+					Email:    "mfaskewuser@mail.test",
+				}, 1)
+				require.NoError(t, err)
+
+				authCtx, err := sat.AuthenticatedContext(ctx, user.ID)
+				require.NoError(t, err)
+
+				key, err := service.ResetMFASecretKey(authCtx)
+				require.NoError(t, err)
+
+				period := time.Duration(console.NewMFAValidationOpts().Period) * time.Second
+				now := time.Now()
+
+				requireSkew := func(err error) {
+					if skew == 0 {
+						require.True(t, console.ErrMFAPasscode.Has(err))
+					} else {
+						require.NoError(t, err)
+					}
+				}
+
+				// enabling with a passcode from one time step earlier.
+				earlierCode, err := console.NewMFAPasscode(key, now.Add(-period))
+				require.NoError(t, err)
+				requireSkew(service.EnableUserMFA(authCtx, earlierCode, now))
+
+				// the remaining paths require MFA to be enabled.
+				if skew == 0 {
+					currentCode, err := console.NewMFAPasscode(key, now)
+					require.NoError(t, err)
+					require.NoError(t, service.EnableUserMFA(authCtx, currentCode, now))
+				}
+
+				// getting a token with a passcode from one time step later.
+				laterCode, err := console.NewMFAPasscode(key, time.Now().Add(period))
+				require.NoError(t, err)
+				_, err = service.Token(ctx, console.AuthUser{Email: user.Email, Password: user.FullName, MFAPasscode: laterCode})
+				if skew == 0 {
+					require.True(t, console.ErrUnauthorized.Has(err))
+				} else {
+					require.NoError(t, err)
+				}
+
+				// disabling with a passcode from one time step later.
+				authCtx, err = sat.AuthenticatedContext(ctx, user.ID)
+				require.NoError(t, err)
+				laterCode, err = console.NewMFAPasscode(key, now.Add(period))
+				require.NoError(t, err)
+				requireSkew(service.DisableUserMFA(authCtx, laterCode, now, ""))
+			})
+		})
+	}
+}
+
+func TestMFARateLimit(t *testing.T) {
+	const maxAttempts = 3
+	const window = time.Hour
+
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Console.MFARateLimit.MaxAttempts = maxAttempts
+				config.Console.MFARateLimit.Window = window
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		service := sat.API.Console.Service
+
+		user, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "MFA Rate Limit Test User",
+			Email:    "mfaratelimituser@mail.test",
+		}, 1)
+		require.NoError(t, err)
+
+		authCtx, err := sat.AuthenticatedContext(ctx, user.ID)
+		require.NoError(t, err)
+
+		key, err := service.ResetMFASecretKey(authCtx)
+		require.NoError(t, err)
+
+		now := time.Now()
+
+		badCode, err := console.NewMFAPasscode(key, now.Add(time.Hour))
+		require.NoError(t, err)
+		goodCode, err := console.NewMFAPasscode(key, now)
+		require.NoError(t, err)
+
+		// Expect bad passcodes to be rejected as invalid until the limit is hit.
+		for i := 0; i < maxAttempts; i++ {
+			err = service.EnableUserMFA(authCtx, badCode, now)
+			require.True(t, console.ErrMFAPasscode.Has(err))
+			require.False(t, console.ErrMFARateLimit.Has(err))
+		}
+
+		// Expect even a good passcode to be rejected while rate limited.
+		err = service.EnableUserMFA(authCtx, goodCode, now)
+		require.True(t, console.ErrMFARateLimit.Has(err))
+
+		// Expect a good passcode to be accepted once the window has passed.
+		later := now.Add(window)
+		goodCode, err = console.NewMFAPasscode(key, later)
+		require.NoError(t, err)
+		require.NoError(t, service.EnableUserMFA(authCtx, goodCode, later))
+
+		// Expect the successful attempt to have reset the failed attempts.
+		authCtx, err = sat.AuthenticatedContext(ctx, user.ID)
+		require.NoError(t, err)
+		badCode, err = console.NewMFAPasscode(key, later.Add(time.Hour))
+		require.NoError(t, err)
+		for i := 0; i < maxAttempts; i++ {
+			err = service.DisableUserMFA(authCtx, badCode, later, "")
+			require.True(t, console.ErrMFAPasscode.Has(err))
+		}
+		err = service.DisableUserMFA(authCtx, goodCode, later, "")
+		require.True(t, console.ErrMFARateLimit.Has(err))
+	})
+}
+
+func TestLoginLockout(t *testing.T) {
+	const maxAttempts = 3
+
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Console.LoginLockout.MaxAttempts = maxAttempts
+				config.Console.LoginLockout.Window = time.Hour
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		service := sat.API.Console.Service
+
+		user, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Lockout Test User",
+			Email:    "lockoutuser@mail.test",
+		}, 1)
+		require.NoError(t, err)
+
+		otherUser, err := sat.AddUser(ctx, console.CreateUser{
+			FullName: "Other Lockout Test User",
+			Email:    "otherlockoutuser@mail.test",
+		}, 1)
+		require.NoError(t, err)
+
+		// sat.AddUser sets password to full name.
+		goodRequest := console.AuthUser{Email: user.Email, Password: user.FullName}
+		badRequest := console.AuthUser{Email: user.Email, Password: "wrong password"}
+
+		// Expect a successful login to reset the failed attempts.
+		for i := 0; i < maxAttempts-1; i++ {
+			_, err = service.Token(ctx, badRequest)
+			require.True(t, console.ErrUnauthorized.Has(err))
+		}
+		_, err = service.Token(ctx, goodRequest)
+		require.NoError(t, err)
+
+		// Expect bad credentials to be rejected until the threshold is hit.
+		for i := 0; i < maxAttempts; i++ {
+			_, err = service.Token(ctx, badRequest)
+			require.True(t, console.ErrUnauthorized.Has(err))
+		}
+
+		// Expect the correct password to be rejected while the account is
+		// locked, with the same error as wrong credentials and unknown emails.
+		token, err := service.Token(ctx, goodRequest)
+		require.True(t, console.ErrUnauthorized.Has(err))
+		require.Empty(t, token)
+
+		_, unknownErr := service.Token(ctx, console.AuthUser{Email: "unknown@mail.test", Password: "password"})
+		require.Equal(t, unknownErr.Error(), err.Error())
+
+		// Expect other accounts to be unaffected.
+		token, err = service.Token(ctx, console.AuthUser{Email: otherUser.Email, Password: otherUser.FullName})
+		require.NoError(t, err)
+		require.NotEmpty(t, token)
+	})
+}
+
 func TestResetPassword(t *testing.T) {
 	testplanet.Run(t, testplanet.Config{
 		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
@@ -550,3 +756,51 @@ func TestResetPassword(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestMaxProjectLimit(t *testing.T) {
+	maxProjects := 3
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Console.MaxProjectLimit = maxProjects
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		service := sat.API.Console.Service
+
+		createProjects := func(t *testing.T, email string, projectLimit int) (created int, err error) {
+			user, err := sat.AddUser(ctx, console.CreateUser{
+				FullName: "Project Creator",
+				Email:    email,
+			}, projectLimit)
+			require.NoError(t, err)
+
+			authCtx, err := sat.AuthenticatedContext(ctx, user.ID)
+			require.NoError(t, err)
+
+			for {
+				_, err := service.CreateProject(authCtx, console.ProjectInfo{Name: fmt.Sprintf("project %d", created)})
+				if err != nil {
+					return created, err
+				}
+				created++
+				require.LessOrEqual(t, created, maxProjects)
+			}
+		}
+
+		t.Run("user limit above the maximum", func(t *testing.T) {
+			created, err := createProjects(t, "above@mail.test", maxProjects+2)
+			require.Equal(t, maxProjects, created)
+			require.True(t, console.ErrValidation.Has(err), err)
+		})
+
+		t.Run("user limit below the maximum", func(t *testing.T) {
+			created, err := createProjects(t, "below@mail.test", maxProjects-1)
+			require.Equal(t, maxProjects-1, created)
+			require.True(t, console.ErrProjLimit.Has(err), err)
+			require.False(t, console.ErrValidation.Has(err), err)
+		})
+	})
+}
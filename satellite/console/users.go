@@ -6,6 +6,7 @@ package console
 import (
 	"context"
 	"net/mail"
+	"strings"
 	"time"
 
 	"storj.io/common/uuid"
@@ -29,16 +30,53 @@ type Users interface {
 	UpdatePaidTier(ctx context.Context, id uuid.UUID, paidTier bool) error
 	// GetProjectLimit is a method to get the users project limit
 	GetProjectLimit(ctx context.Context, id uuid.UUID) (limit int, err error)
+	// BumpTokenEpoch increments the token epoch of the user and returns the new epoch.
+	BumpTokenEpoch(ctx context.Context, id uuid.UUID) (epoch int64, err error)
 }
 
 // UserInfo holds User updatable data.
 type UserInfo struct {
-	FullName  string `json:"fullName"`
-	ShortName string `json:"shortName"`
+	FullName         string `json:"fullName"`
+	ShortName        string `json:"shortName"`
+	IsProfessional   bool   `json:"isProfessional"`
+	Position         string `json:"position"`
+	CompanyName      string `json:"companyName"`
+	EmployeeCount    string `json:"employeeCount"`
+	HaveSalesContact bool   `json:"haveSalesContact"`
+}
+
+// Normalize trims the whitespace around the string fields and clears the
+// professional fields of a personal account.
+func (user *UserInfo) Normalize() {
+	user.FullName = strings.TrimSpace(user.FullName)
+	user.ShortName = strings.TrimSpace(user.ShortName)
+	user.Position = strings.TrimSpace(user.Position)
+	user.CompanyName = strings.TrimSpace(user.CompanyName)
+	user.EmployeeCount = strings.TrimSpace(user.EmployeeCount)
+
+	if !user.IsProfessional {
+		user.Position = ""
+		user.CompanyName = ""
+		user.EmployeeCount = ""
+		user.HaveSalesContact = false
+	}
 }
 
 // IsValid checks UserInfo validity and returns error describing whats wrong.
 func (user *UserInfo) IsValid() error {
+	return user.validate(nil)
+}
+
+// IsValidUpdate checks the validity of the fields which differ from current,
+// so that values stored before they were validated don't prevent updating the
+// other fields. The full name is always validated.
+func (user *UserInfo) IsValidUpdate(current UserInfo) error {
+	return user.validate(&current)
+}
+
+// validate checks the fields which differ from current, or all fields when
+// current is nil.
+func (user *UserInfo) validate(current *UserInfo) error {
 	var errs validationErrors
 
 	// validate fullName
@@ -46,6 +84,30 @@ func (user *UserInfo) IsValid() error {
 		errs.AddWrap(err)
 	}
 
+	if (current == nil || user.FullName != current.FullName) && len(user.FullName) > userInfoMaxLength {
+		errs.Addf("full name can not be longer than %d characters", userInfoMaxLength)
+	}
+	if (current == nil || user.ShortName != current.ShortName) && len(user.ShortName) > userInfoMaxLength {
+		errs.Addf("short name can not be longer than %d characters", userInfoMaxLength)
+	}
+
+	if user.IsProfessional {
+		// the professional fields are new to an account which wasn't professional.
+		wasProfessional := current != nil && current.IsProfessional
+
+		if (!wasProfessional || user.Position != current.Position) && len(user.Position) > userInfoMaxLength {
+			errs.Addf("position can not be longer than %d characters", userInfoMaxLength)
+		}
+		if (!wasProfessional || user.CompanyName != current.CompanyName) && len(user.CompanyName) > userInfoMaxLength {
+			errs.Addf("company name can not be longer than %d characters", userInfoMaxLength)
+		}
+		if !wasProfessional || user.EmployeeCount != current.EmployeeCount {
+			if err := ValidateEmployeeCount(user.EmployeeCount); err != nil {
+				errs.AddWrap(err)
+			}
+		}
+	}
+
 	return errs.Combine()
 }
 
@@ -137,4 +199,10 @@ type User struct {
 	MFAEnabled       bool     `json:"mfaEnabled"`
 	MFASecretKey     string   `json:"mfaSecretKey"`
 	MFARecoveryCodes []string `json:"mfaRecoveryCodes"`
+
+	DeletionRequestedAt *time.Time `json:"deletionRequestedAt"`
+
+	// TokenEpoch is the epoch of the tokens which are currently valid for the
+	// user. Bumping it invalidates every token that was issued before.
+	TokenEpoch int64 `json:"-"`
 }
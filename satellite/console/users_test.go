@@ -159,6 +159,35 @@ func TestUserUpdatePaidTier(t *testing.T) {
 	})
 }
 
+func TestUserBumpTokenEpoch(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		users := db.Console().Users()
+
+		createdUser, err := users.Insert(ctx, &console.User{
+			ID:           testrand.UUID(),
+			FullName:     "first name last name",
+			Email:        "testemail@mail.test",
+			PasswordHash: []byte("password"),
+		})
+		require.NoError(t, err)
+		require.Zero(t, createdUser.TokenEpoch)
+
+		for expected := int64(1); expected <= 2; expected++ {
+			epoch, err := users.BumpTokenEpoch(ctx, createdUser.ID)
+			require.NoError(t, err)
+			require.Equal(t, expected, epoch)
+		}
+
+		// updating the user must not reset the epoch
+		createdUser.Status = console.Active
+		require.NoError(t, users.Update(ctx, createdUser))
+
+		retrievedUser, err := users.Get(ctx, createdUser.ID)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, retrievedUser.TokenEpoch)
+	})
+}
+
 func testUsers(ctx context.Context, t *testing.T, repository console.Users, user *console.User) {
 
 	t.Run("User insertion success", func(t *testing.T) {
@@ -4,13 +4,21 @@
 package console
 
 import (
+	"strings"
+	"unicode"
+
 	"github.com/zeebo/errs"
 )
 
 const (
 	passMinLength = 6
+
+	userInfoMaxLength = 100
 )
 
+// EmployeeCounts are the allowed company size ranges of a professional user.
+var EmployeeCounts = []string{"1-50", "51-1000", "1001+"}
+
 // ErrValidation validation related error class.
 var ErrValidation = errs.Class("validation")
 
@@ -43,6 +51,44 @@ func ValidatePassword(pass string) error {
 	return errs.Combine()
 }
 
+// Validate validates that the password meets the requirements of the policy.
+// The length required is never lower than the one ValidatePassword requires.
+func (policy PasswordPolicyConfig) Validate(pass string) error {
+	var errs validationErrors
+
+	minLength := policy.MinLength
+	if minLength < passMinLength {
+		minLength = passMinLength
+	}
+	if len(pass) < minLength {
+		errs.Addf(passwordIncorrectErrMsg, minLength)
+	}
+
+	var hasDigit, hasUpper, hasSymbol bool
+	for _, r := range pass {
+		switch {
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	if policy.RequireDigit && !hasDigit {
+		errs.Addf(passwordDigitErrMsg)
+	}
+	if policy.RequireUpper && !hasUpper {
+		errs.Addf(passwordUpperErrMsg)
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		errs.Addf(passwordSymbolErrMsg)
+	}
+
+	return errs.Combine()
+}
+
 // ValidateFullName validates full name.
 func ValidateFullName(name string) error {
 	if name == "" {
@@ -51,3 +97,14 @@ func ValidateFullName(name string) error {
 
 	return nil
 }
+
+// ValidateEmployeeCount validates that employee count is one of the allowed ranges.
+func ValidateEmployeeCount(count string) error {
+	for _, allowed := range EmployeeCounts {
+		if count == allowed {
+			return nil
+		}
+	}
+
+	return errs.New("employee count must be one of %s", strings.Join(EmployeeCounts, ", "))
+}
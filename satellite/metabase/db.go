@@ -26,20 +26,34 @@ var (
 	mon = monkit.Package()
 )
 
+// Config is the configuration of the metabase.
+type Config struct {
+	LoopBatchSizeLimit int `help:"maximum number of objects or segments the metabase loops query in a single batch" default:"2500"`
+}
+
 // DB implements a database for storing objects and segments.
 type DB struct {
 	log     *zap.Logger
 	db      tagsql.DB
 	connstr string
 	impl    dbutil.Implementation
+	config  Config
 
 	aliasCache *NodeAliasCache
 
 	testCleanup func() error
 }
 
-// Open opens a connection to metabase.
-func Open(ctx context.Context, log *zap.Logger, connstr string) (*DB, error) {
+// Open opens a connection to metabase. A zero LoopBatchSizeLimit in config
+// uses the default limit.
+func Open(ctx context.Context, log *zap.Logger, connstr string, config Config) (*DB, error) {
+	if config.LoopBatchSizeLimit < 0 {
+		return nil, Error.New("LoopBatchSizeLimit must be positive: %d", config.LoopBatchSizeLimit)
+	}
+	if config.LoopBatchSizeLimit == 0 {
+		config.LoopBatchSizeLimit = defaultLoopBatchSizeLimit
+	}
+
 	var driverName string
 	_, _, impl, err := dbutil.SplitConnStr(connstr)
 	if err != nil {
@@ -65,6 +79,7 @@ func Open(ctx context.Context, log *zap.Logger, connstr string) (*DB, error) {
 		db:          postgresRebind{rawdb},
 		connstr:     connstr,
 		impl:        impl,
+		config:      config,
 		testCleanup: func() error { return nil },
 	}
 	db.aliasCache = NewNodeAliasCache(db)
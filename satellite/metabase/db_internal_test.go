@@ -69,3 +69,18 @@ func TestLimitedAsOfSystemTime(t *testing.T) {
 	check("", unixNano, 0, 0)
 	check("", 0, unixNano, 0)
 }
+
+func TestLoopBatchSize(t *testing.T) {
+	db := &DB{config: Config{LoopBatchSizeLimit: 5000}}
+
+	// a requested batch size above the default limit is honored
+	require.Equal(t, 3000, db.loopBatchSize(3000))
+	require.Equal(t, 5000, db.loopBatchSize(5000))
+	// out of range requests are clamped to the limit
+	require.Equal(t, 5000, db.loopBatchSize(5001))
+	require.Equal(t, 5000, db.loopBatchSize(0))
+	require.Equal(t, 5000, db.loopBatchSize(-1))
+
+	db = &DB{config: Config{LoopBatchSizeLimit: defaultLoopBatchSizeLimit}}
+	require.Equal(t, defaultLoopBatchSizeLimit, db.loopBatchSize(3000))
+}
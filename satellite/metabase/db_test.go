@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
 
 	"storj.io/common/testcontext"
 	"storj.io/storj/satellite/metabase"
@@ -22,3 +23,13 @@ func TestNow(t *testing.T) {
 		require.WithinDuration(t, sysnow, now, 5*time.Second)
 	})
 }
+
+func TestOpenInvalidConfig(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	_, err := metabase.Open(ctx, zaptest.NewLogger(t), "postgres://localhost/metabase", metabase.Config{
+		LoopBatchSizeLimit: -1,
+	})
+	require.Error(t, err)
+}
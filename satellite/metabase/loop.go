@@ -6,6 +6,7 @@ package metabase
 import (
 	"bytes"
 	"context"
+	"errors"
 	"sort"
 	"time"
 
@@ -17,7 +18,13 @@ import (
 	"storj.io/private/tagsql"
 )
 
-const loopIteratorBatchSizeLimit = 2500
+// defaultLoopBatchSizeLimit is the loop batch size limit used when Config
+// doesn't set one.
+const defaultLoopBatchSizeLimit = 2500
+
+// ErrLoopBatchTimeout is used when a batch of a loop iteration doesn't finish
+// within the configured BatchTimeout.
+var ErrLoopBatchTimeout = errs.Class("metabase: loop batch timeout")
 
 // IterateLoopObjects contains arguments necessary for listing objects in metabase.
 type IterateLoopObjects struct {
@@ -25,6 +32,17 @@ type IterateLoopObjects struct {
 
 	AsOfSystemTime     time.Time
 	AsOfSystemInterval time.Duration
+
+	// BatchTimeout limits how long querying and reading a single batch may
+	// take. The rows are read lazily while iterating, so the timeout also
+	// includes the time spent in the callback on the entries of the batch.
+	// Zero means no timeout.
+	BatchTimeout time.Duration
+
+	// Fields selects the LoopObjectEntry fields that are read in addition to
+	// ObjectStream, the fields that aren't selected are left zero. Zero means
+	// all fields.
+	Fields LoopObjectFields
 }
 
 // Verify verifies get object request fields.
@@ -32,9 +50,54 @@ func (opts *IterateLoopObjects) Verify() error {
 	if opts.BatchSize < 0 {
 		return ErrInvalidRequest.New("BatchSize is negative")
 	}
+	if opts.BatchTimeout < 0 {
+		return ErrInvalidRequest.New("BatchTimeout is negative")
+	}
+	if opts.Fields&^LoopObjectAllFields != 0 {
+		return ErrInvalidRequest.New("Fields contains unknown fields")
+	}
 	return nil
 }
 
+// LoopObjectFields is a set of LoopObjectEntry fields to read when iterating
+// objects. ObjectStream is always read, because the iteration depends on it,
+// any combination of the other fields is supported.
+type LoopObjectFields int
+
+const (
+	// LoopObjectStatus selects LoopObjectEntry.Status.
+	LoopObjectStatus LoopObjectFields = 1 << iota
+	// LoopObjectCreatedAt selects LoopObjectEntry.CreatedAt.
+	LoopObjectCreatedAt
+	// LoopObjectExpiresAt selects LoopObjectEntry.ExpiresAt.
+	LoopObjectExpiresAt
+	// LoopObjectSegmentCount selects LoopObjectEntry.SegmentCount.
+	LoopObjectSegmentCount
+	// LoopObjectTotalEncryptedSize selects LoopObjectEntry.TotalEncryptedSize.
+	LoopObjectTotalEncryptedSize
+	// LoopObjectEncryptedMetadataSize selects LoopObjectEntry.EncryptedMetadataSize.
+	LoopObjectEncryptedMetadataSize
+
+	// LoopObjectAllFields selects all LoopObjectEntry fields.
+	LoopObjectAllFields = LoopObjectStatus | LoopObjectCreatedAt | LoopObjectExpiresAt |
+		LoopObjectSegmentCount | LoopObjectTotalEncryptedSize | LoopObjectEncryptedMetadataSize
+)
+
+// loopObjectColumns are the optional columns read for LoopObjectEntry, in the
+// order they are selected.
+var loopObjectColumns = []struct {
+	field  LoopObjectFields
+	column string
+	dest   func(item *LoopObjectEntry) interface{}
+}{
+	{LoopObjectStatus, "status", func(item *LoopObjectEntry) interface{} { return &item.Status }},
+	{LoopObjectCreatedAt, "created_at", func(item *LoopObjectEntry) interface{} { return &item.CreatedAt }},
+	{LoopObjectExpiresAt, "expires_at", func(item *LoopObjectEntry) interface{} { return &item.ExpiresAt }},
+	{LoopObjectSegmentCount, "segment_count", func(item *LoopObjectEntry) interface{} { return &item.SegmentCount }},
+	{LoopObjectTotalEncryptedSize, "total_encrypted_size", func(item *LoopObjectEntry) interface{} { return &item.TotalEncryptedSize }},
+	{LoopObjectEncryptedMetadataSize, "LENGTH(COALESCE(encrypted_metadata,''))", func(item *LoopObjectEntry) interface{} { return &item.EncryptedMetadataSize }},
+}
+
 // LoopObjectsIterator iterates over a sequence of LoopObjectEntry items.
 type LoopObjectsIterator interface {
 	Next(ctx context.Context, item *LoopObjectEntry) bool
@@ -56,12 +119,13 @@ func (o LoopObjectEntry) Expired(now time.Time) bool {
 	return o.ExpiresAt != nil && o.ExpiresAt.Before(now)
 }
 
-// IterateLoopObjects iterates through all objects in metabase.
-func (db *DB) IterateLoopObjects(ctx context.Context, opts IterateLoopObjects, fn func(context.Context, LoopObjectsIterator) error) (err error) {
+// IterateLoopObjects iterates through all objects in metabase. It returns the
+// number of objects that were yielded by the iterator.
+func (db *DB) IterateLoopObjects(ctx context.Context, opts IterateLoopObjects, fn func(context.Context, LoopObjectsIterator) error) (count int64, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	if err := opts.Verify(); err != nil {
-		return err
+		return 0, err
 	}
 
 	it := &loopIterator{
@@ -73,16 +137,19 @@ func (db *DB) IterateLoopObjects(ctx context.Context, opts IterateLoopObjects, f
 		cursor:             loopIterateCursor{},
 		asOfSystemTime:     opts.AsOfSystemTime,
 		asOfSystemInterval: opts.AsOfSystemInterval,
+		batchTimeout:       opts.BatchTimeout,
+		fields:             opts.Fields,
 	}
 
-	// ensure batch size is reasonable
-	if it.batchSize <= 0 || it.batchSize > loopIteratorBatchSizeLimit {
-		it.batchSize = loopIteratorBatchSizeLimit
+	if it.fields == 0 {
+		it.fields = LoopObjectAllFields
 	}
 
+	it.batchSize = db.loopBatchSize(it.batchSize)
+
 	it.curRows, err = it.doNextQuery(ctx)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	defer func() {
@@ -92,7 +159,8 @@ func (db *DB) IterateLoopObjects(ctx context.Context, opts IterateLoopObjects, f
 		err = errs.Combine(err, it.failErr, it.curRows.Close())
 	}()
 
-	return fn(ctx, it)
+	err = fn(ctx, it)
+	return it.count, err
 }
 
 // loopIterator enables iteration of all objects in metabase.
@@ -102,11 +170,16 @@ type loopIterator struct {
 	batchSize          int
 	asOfSystemTime     time.Time
 	asOfSystemInterval time.Duration
+	batchTimeout       time.Duration
+	fields             LoopObjectFields
 
 	curIndex int
 	curRows  tagsql.Rows
 	cursor   loopIterateCursor
 
+	// count is the number of items yielded so far.
+	count int64
+
 	// failErr is set when either scan or next query fails during iteration.
 	failErr error
 }
@@ -155,6 +228,7 @@ func (it *loopIterator) Next(ctx context.Context, item *LoopObjectEntry) bool {
 	}
 
 	it.curIndex++
+	it.count++
 	it.cursor.ProjectID = item.ProjectID
 	it.cursor.BucketName = item.BucketName
 	it.cursor.ObjectKey = item.ObjectKey
@@ -166,14 +240,19 @@ func (it *loopIterator) Next(ctx context.Context, item *LoopObjectEntry) bool {
 func (it *loopIterator) doNextQuery(ctx context.Context) (_ tagsql.Rows, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	return it.db.db.QueryContext(ctx, `
-		SELECT
-			project_id, bucket_name,
-			object_key, stream_id, version,
-			status,
-			created_at, expires_at,
-			segment_count, total_encrypted_size,
-			LENGTH(COALESCE(encrypted_metadata,''))
+	columns := "project_id, bucket_name, object_key, stream_id, version"
+	for _, optional := range loopObjectColumns {
+		if it.fields&optional.field != 0 {
+			columns += ", " + optional.column
+		}
+	}
+
+	mon.Counter("objects_loop_batches").Inc(1)
+	defer observeLoopQueryDuration("objects_loop_query_duration", time.Now())
+
+	batchCtx, batch := withLoopBatchTimeout(ctx, it.batchTimeout)
+	return batch.wrap(it.db.db.QueryContext(batchCtx, `
+		SELECT `+columns+`
 		FROM objects
 		`+it.db.asOfTime(it.asOfSystemTime, it.asOfSystemInterval)+`
 		WHERE (project_id, bucket_name, object_key, version) > ($1, $2, $3, $4)
@@ -182,19 +261,26 @@ func (it *loopIterator) doNextQuery(ctx context.Context) (_ tagsql.Rows, err err
 		`, it.cursor.ProjectID, []byte(it.cursor.BucketName),
 		[]byte(it.cursor.ObjectKey), int(it.cursor.Version),
 		it.batchSize,
-	)
+	))
 }
 
 // scanItem scans doNextQuery results into LoopObjectEntry.
 func (it *loopIterator) scanItem(item *LoopObjectEntry) error {
-	return it.curRows.Scan(
+	if it.fields != LoopObjectAllFields {
+		// the fields that aren't read must not keep values from a previous item
+		*item = LoopObjectEntry{}
+	}
+
+	dest := []interface{}{
 		&item.ProjectID, &item.BucketName,
 		&item.ObjectKey, &item.StreamID, &item.Version,
-		&item.Status,
-		&item.CreatedAt, &item.ExpiresAt,
-		&item.SegmentCount, &item.TotalEncryptedSize,
-		&item.EncryptedMetadataSize,
-	)
+	}
+	for _, optional := range loopObjectColumns {
+		if it.fields&optional.field != 0 {
+			dest = append(dest, optional.dest(item))
+		}
+	}
+	return it.curRows.Scan(dest...)
 }
 
 // IterateLoopStreams contains arguments necessary for listing multiple streams segments.
@@ -347,6 +433,16 @@ type IterateLoopSegments struct {
 	BatchSize          int
 	AsOfSystemTime     time.Time
 	AsOfSystemInterval time.Duration
+
+	// BatchTimeout limits how long querying and reading a single batch may
+	// take. The rows are read lazily while iterating, so the timeout also
+	// includes the time spent in the callback on the entries of the batch.
+	// Zero means no timeout.
+	BatchTimeout time.Duration
+
+	// SkipInline excludes inline segments from the iteration, so that their
+	// rows aren't read at all.
+	SkipInline bool
 }
 
 // Verify verifies segments request fields.
@@ -354,15 +450,19 @@ func (opts *IterateLoopSegments) Verify() error {
 	if opts.BatchSize < 0 {
 		return ErrInvalidRequest.New("BatchSize is negative")
 	}
+	if opts.BatchTimeout < 0 {
+		return ErrInvalidRequest.New("BatchTimeout is negative")
+	}
 	return nil
 }
 
-// IterateLoopSegments iterates through all segments in metabase.
-func (db *DB) IterateLoopSegments(ctx context.Context, opts IterateLoopSegments, fn func(context.Context, LoopSegmentsIterator) error) (err error) {
+// IterateLoopSegments iterates through all segments in metabase. It returns the
+// number of segments that were yielded by the iterator.
+func (db *DB) IterateLoopSegments(ctx context.Context, opts IterateLoopSegments, fn func(context.Context, LoopSegmentsIterator) error) (count int64, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	if err := opts.Verify(); err != nil {
-		return err
+		return 0, err
 	}
 
 	it := &loopSegmentIterator{
@@ -371,19 +471,18 @@ func (db *DB) IterateLoopSegments(ctx context.Context, opts IterateLoopSegments,
 		asOfSystemTime:     opts.AsOfSystemTime,
 		asOfSystemInterval: opts.AsOfSystemInterval,
 		batchSize:          opts.BatchSize,
+		batchTimeout:       opts.BatchTimeout,
+		skipInline:         opts.SkipInline,
 
 		curIndex: 0,
 		cursor:   loopSegmentIteratorCursor{},
 	}
 
-	// ensure batch size is reasonable
-	if it.batchSize <= 0 || it.batchSize > loopIteratorBatchSizeLimit {
-		it.batchSize = loopIteratorBatchSizeLimit
-	}
+	it.batchSize = db.loopBatchSize(it.batchSize)
 
 	it.curRows, err = it.doNextQuery(ctx)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	defer func() {
@@ -393,7 +492,8 @@ func (db *DB) IterateLoopSegments(ctx context.Context, opts IterateLoopSegments,
 		err = errs.Combine(err, it.failErr, it.curRows.Close())
 	}()
 
-	return fn(ctx, it)
+	err = fn(ctx, it)
+	return it.count, err
 }
 
 // loopSegmentIterator enables iteration of all segments in metabase.
@@ -403,11 +503,16 @@ type loopSegmentIterator struct {
 	batchSize          int
 	asOfSystemTime     time.Time
 	asOfSystemInterval time.Duration
+	batchTimeout       time.Duration
+	skipInline         bool
 
 	curIndex int
 	curRows  tagsql.Rows
 	cursor   loopSegmentIteratorCursor
 
+	// count is the number of items yielded so far.
+	count int64
+
 	// failErr is set when either scan or next query fails during iteration.
 	failErr error
 }
@@ -454,6 +559,7 @@ func (it *loopSegmentIterator) Next(ctx context.Context, item *LoopSegmentEntry)
 	}
 
 	it.curIndex++
+	it.count++
 	it.cursor.StreamID = item.StreamID
 	it.cursor.Position = item.Position
 
@@ -463,7 +569,18 @@ func (it *loopSegmentIterator) Next(ctx context.Context, item *LoopSegmentEntry)
 func (it *loopSegmentIterator) doNextQuery(ctx context.Context) (_ tagsql.Rows, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	return it.db.db.QueryContext(ctx, `
+	mon.Counter("segments_loop_batches").Inc(1)
+	defer observeLoopQueryDuration("segments_loop_query_duration", time.Now())
+
+	skipInlinePredicate := ""
+	if it.skipInline {
+		// inline segments have neither a redundancy scheme nor remote pieces,
+		// the same as LoopSegmentEntry.Inline.
+		skipInlinePredicate = "AND (redundancy <> 0 OR remote_alias_pieces IS NOT NULL)"
+	}
+
+	batchCtx, batch := withLoopBatchTimeout(ctx, it.batchTimeout)
+	return batch.wrap(it.db.db.QueryContext(batchCtx, `
 		SELECT
 			stream_id, position,
 			created_at, expires_at, repaired_at,
@@ -476,11 +593,12 @@ func (it *loopSegmentIterator) doNextQuery(ctx context.Context) (_ tagsql.Rows,
 		`+it.db.asOfTime(it.asOfSystemTime, it.asOfSystemInterval)+`
 		WHERE
 			(stream_id, position) > ($1, $2)
+			`+skipInlinePredicate+`
 		ORDER BY (stream_id, position) ASC
 		LIMIT $3
 		`, it.cursor.StreamID, it.cursor.Position,
 		it.batchSize,
-	)
+	))
 }
 
 // scanItem scans doNextQuery results into LoopSegmentEntry.
@@ -506,3 +624,81 @@ func (it *loopSegmentIterator) scanItem(ctx context.Context, item *LoopSegmentEn
 
 	return nil
 }
+
+// loopBatchSize ensures the requested batch size is reasonable, it's clamped to
+// the configured limit and the limit is used when none is requested.
+func (db *DB) loopBatchSize(requested int) int {
+	if requested <= 0 || requested > db.config.LoopBatchSizeLimit {
+		return db.config.LoopBatchSizeLimit
+	}
+	return requested
+}
+
+// observeLoopQueryDuration records how long the query of a single loop batch
+// took, reading the rows isn't included.
+func observeLoopQueryDuration(name string, start time.Time) {
+	mon.DurationVal(name).Observe(time.Since(start))
+}
+
+// loopBatch is the context of a single loop batch with a timeout.
+type loopBatch struct {
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// withLoopBatchTimeout returns the context for querying a single loop batch.
+// The context lives until the rows of the batch are closed, so the timeout
+// counts the time the caller spends processing the rows as well as the query.
+// A timeout of zero or less doesn't limit the batch.
+func withLoopBatchTimeout(ctx context.Context, timeout time.Duration) (context.Context, *loopBatch) {
+	if timeout <= 0 {
+		return ctx, nil
+	}
+	batchCtx, cancel := context.WithTimeout(ctx, timeout)
+	return batchCtx, &loopBatch{parent: ctx, ctx: batchCtx, cancel: cancel}
+}
+
+// wrap ties the batch context to the lifetime of rows, the context is canceled
+// when the rows are closed or the query fails.
+func (batch *loopBatch) wrap(rows tagsql.Rows, err error) (tagsql.Rows, error) {
+	if batch == nil {
+		return rows, err
+	}
+	if err != nil {
+		err = batch.convert(err)
+		batch.cancel()
+		return nil, err
+	}
+	return &loopBatchRows{Rows: rows, batch: batch}, nil
+}
+
+// convert wraps err with ErrLoopBatchTimeout when the batch ran out of time,
+// so that it can be distinguished from the parent context being canceled.
+func (batch *loopBatch) convert(err error) error {
+	if err == nil || batch.parent.Err() != nil {
+		return err
+	}
+	if errors.Is(batch.ctx.Err(), context.DeadlineExceeded) {
+		return ErrLoopBatchTimeout.Wrap(err)
+	}
+	return err
+}
+
+// loopBatchRows are the rows of a single loop batch with a timeout.
+type loopBatchRows struct {
+	tagsql.Rows
+	batch *loopBatch
+}
+
+// Err returns the error encountered while reading the rows.
+func (rows *loopBatchRows) Err() error {
+	return rows.batch.convert(rows.Rows.Err())
+}
+
+// Close closes the rows and releases the batch context.
+func (rows *loopBatchRows) Close() error {
+	err := rows.Rows.Close()
+	rows.batch.cancel()
+	return err
+}
@@ -5,11 +5,15 @@ package metabase_test
 
 import (
 	"bytes"
+	"context"
 	"sort"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/stretchr/testify/require"
+
 	"storj.io/common/storj"
 	"storj.io/common/testcontext"
 	"storj.io/common/testrand"
@@ -32,6 +36,56 @@ func TestIterateLoopObjects(t *testing.T) {
 			metabasetest.Verify{}.Check(ctx, t, db)
 		})
 
+		t.Run("BatchTimeout is negative", func(t *testing.T) {
+			defer metabasetest.DeleteAll{}.Check(ctx, t, db)
+			metabasetest.IterateLoopObjects{
+				Opts: metabase.IterateLoopObjects{
+					BatchTimeout: -1,
+				},
+				ErrClass: &metabase.ErrInvalidRequest,
+				ErrText:  "BatchTimeout is negative",
+			}.Check(ctx, t, db)
+			metabasetest.Verify{}.Check(ctx, t, db)
+		})
+
+		t.Run("Fields contains unknown fields", func(t *testing.T) {
+			metabasetest.IterateLoopObjects{
+				Opts: metabase.IterateLoopObjects{
+					Fields: metabase.LoopObjectAllFields + 1,
+				},
+				ErrClass: &metabase.ErrInvalidRequest,
+				ErrText:  "Fields contains unknown fields",
+			}.Check(ctx, t, db)
+			metabasetest.Verify{}.Check(ctx, t, db)
+		})
+
+		t.Run("batch timeout", func(t *testing.T) {
+			defer metabasetest.DeleteAll{}.Check(ctx, t, db)
+
+			metabasetest.CreateObject(ctx, t, db, metabasetest.RandObjectStream(), 0)
+
+			// a nanosecond is less than any query takes, so the database is
+			// always too slow for the batch.
+			_, err := db.IterateLoopObjects(ctx, metabase.IterateLoopObjects{
+				BatchTimeout: time.Nanosecond,
+			}, func(ctx context.Context, it metabase.LoopObjectsIterator) error {
+				return nil
+			})
+			require.Error(t, err)
+			require.True(t, metabase.ErrLoopBatchTimeout.Has(err), err)
+
+			// cancellation isn't reported as a timeout.
+			canceledCtx, cancel := context.WithCancel(ctx)
+			cancel()
+			_, err = db.IterateLoopObjects(canceledCtx, metabase.IterateLoopObjects{
+				BatchTimeout: time.Hour,
+			}, func(ctx context.Context, it metabase.LoopObjectsIterator) error {
+				return nil
+			})
+			require.Error(t, err)
+			require.False(t, metabase.ErrLoopBatchTimeout.Has(err), err)
+		})
+
 		t.Run("no data", func(t *testing.T) {
 			defer metabasetest.DeleteAll{}.Check(ctx, t, db)
 
@@ -126,6 +180,28 @@ func TestIterateLoopObjects(t *testing.T) {
 				},
 				Result: expected,
 			}.Check(ctx, t, db)
+
+			metabasetest.IterateLoopObjects{
+				Opts: metabase.IterateLoopObjects{
+					BatchSize: 1,
+					Fields:    metabase.LoopObjectEncryptedMetadataSize,
+				},
+				Result: []metabase.LoopObjectEntry{
+					{ObjectStream: pending},
+					{ObjectStream: committed, EncryptedMetadataSize: len(encryptedMetadata)},
+				},
+			}.Check(ctx, t, db)
+
+			metabasetest.IterateLoopObjects{
+				Opts: metabase.IterateLoopObjects{
+					BatchSize: 1,
+					Fields:    metabase.LoopObjectStatus | metabase.LoopObjectCreatedAt,
+				},
+				Result: []metabase.LoopObjectEntry{
+					{ObjectStream: pending, Status: metabase.Pending, CreatedAt: createdAt},
+					{ObjectStream: committed, Status: metabase.Committed, CreatedAt: createdAt},
+				},
+			}.Check(ctx, t, db)
 		})
 
 		t.Run("less objects than limit", func(t *testing.T) {
@@ -457,6 +533,42 @@ func TestIterateLoopSegments(t *testing.T) {
 			metabasetest.Verify{}.Check(ctx, t, db)
 		})
 
+		t.Run("BatchTimeout is negative", func(t *testing.T) {
+			defer metabasetest.DeleteAll{}.Check(ctx, t, db)
+			metabasetest.IterateLoopSegments{
+				Opts: metabase.IterateLoopSegments{
+					BatchTimeout: -1,
+				},
+				ErrClass: &metabase.ErrInvalidRequest,
+				ErrText:  "BatchTimeout is negative",
+			}.Check(ctx, t, db)
+			metabasetest.Verify{}.Check(ctx, t, db)
+		})
+
+		t.Run("batch timeout", func(t *testing.T) {
+			defer metabasetest.DeleteAll{}.Check(ctx, t, db)
+
+			metabasetest.CreateObject(ctx, t, db, metabasetest.RandObjectStream(), 2)
+
+			_, err := db.IterateLoopSegments(ctx, metabase.IterateLoopSegments{
+				BatchTimeout: time.Nanosecond,
+			}, func(ctx context.Context, it metabase.LoopSegmentsIterator) error {
+				return nil
+			})
+			require.Error(t, err)
+			require.True(t, metabase.ErrLoopBatchTimeout.Has(err), err)
+
+			canceledCtx, cancel := context.WithCancel(ctx)
+			cancel()
+			_, err = db.IterateLoopSegments(canceledCtx, metabase.IterateLoopSegments{
+				BatchTimeout: time.Hour,
+			}, func(ctx context.Context, it metabase.LoopSegmentsIterator) error {
+				return nil
+			})
+			require.Error(t, err)
+			require.False(t, metabase.ErrLoopBatchTimeout.Has(err), err)
+		})
+
 		t.Run("no segments", func(t *testing.T) {
 			defer metabasetest.DeleteAll{}.Check(ctx, t, db)
 
@@ -627,6 +739,155 @@ func TestIterateLoopSegments(t *testing.T) {
 				Segments: expectedRaw,
 			}.Check(ctx, t, db)
 		})
+
+		t.Run("skip inline", func(t *testing.T) {
+			defer metabasetest.DeleteAll{}.Check(ctx, t, db)
+
+			remote := metabasetest.RandObjectStream()
+			metabasetest.CreateObject(ctx, t, db, remote, 2)
+
+			inline := metabasetest.RandObjectStream()
+			metabasetest.BeginObjectExactVersion{
+				Opts: metabase.BeginObjectExactVersion{
+					ObjectStream: inline,
+					Encryption:   metabasetest.DefaultEncryption,
+				},
+				Version: inline.Version,
+			}.Check(ctx, t, db)
+			metabasetest.CommitInlineSegment{
+				Opts: metabase.CommitInlineSegment{
+					ObjectStream: inline,
+
+					InlineData: []byte{1, 2, 3},
+
+					EncryptedKey:      testrand.Bytes(32),
+					EncryptedKeyNonce: testrand.Bytes(32),
+
+					PlainSize: 3,
+				},
+			}.Check(ctx, t, db)
+			metabasetest.CommitObject{
+				Opts: metabase.CommitObject{
+					ObjectStream: inline,
+				},
+			}.Check(ctx, t, db)
+
+			iterate := func(skipInline bool) (streams map[uuid.UUID]int) {
+				streams = make(map[uuid.UUID]int)
+				_, err := db.IterateLoopSegments(ctx, metabase.IterateLoopSegments{
+					BatchSize:  1,
+					SkipInline: skipInline,
+				}, func(ctx context.Context, it metabase.LoopSegmentsIterator) error {
+					var entry metabase.LoopSegmentEntry
+					for it.Next(ctx, &entry) {
+						require.Equal(t, entry.StreamID == inline.StreamID, entry.Inline())
+						streams[entry.StreamID]++
+					}
+					return nil
+				})
+				require.NoError(t, err)
+				return streams
+			}
+
+			require.Equal(t, map[uuid.UUID]int{
+				remote.StreamID: 2,
+				inline.StreamID: 1,
+			}, iterate(false))
+
+			require.Equal(t, map[uuid.UUID]int{
+				remote.StreamID: 2,
+			}, iterate(true))
+		})
+	})
+}
+
+func TestIterateLoopMetrics(t *testing.T) {
+	metabasetest.Run(t, func(ctx *testcontext.Context, t *testing.T, db *metabase.DB) {
+		metabaseScope := monkit.Default.ScopeNamed("storj.io/storj/satellite/metabase")
+
+		durationCount := func(name string) (count float64) {
+			metabaseScope.DurationVal(name).Stats(func(key monkit.SeriesKey, field string, val float64) {
+				if field == "count" {
+					count = val
+				}
+			})
+			return count
+		}
+
+		iterate := func(t *testing.T, prefix string, iterate func() error) (batches int64, queries float64) {
+			batchesBefore := metabaseScope.Counter(prefix + "_loop_batches").Current()
+			queriesBefore := durationCount(prefix + "_loop_query_duration")
+
+			require.NoError(t, iterate())
+
+			return metabaseScope.Counter(prefix+"_loop_batches").Current() - batchesBefore,
+				durationCount(prefix+"_loop_query_duration") - queriesBefore
+		}
+
+		defer metabasetest.DeleteAll{}.Check(ctx, t, db)
+
+		for i := 0; i < 3; i++ {
+			metabasetest.CreateObject(ctx, t, db, metabasetest.RandObjectStream(), 2)
+		}
+
+		t.Run("objects", func(t *testing.T) {
+			// three batches with an object each and an empty one ending the iteration
+			batches, queries := iterate(t, "objects", func() error {
+				_, err := db.IterateLoopObjects(ctx, metabase.IterateLoopObjects{
+					BatchSize: 1,
+				}, func(ctx context.Context, it metabase.LoopObjectsIterator) error {
+					var entry metabase.LoopObjectEntry
+					for it.Next(ctx, &entry) {
+					}
+					return nil
+				})
+				return err
+			})
+			require.EqualValues(t, 4, batches)
+			require.EqualValues(t, 4, queries)
+		})
+
+		t.Run("segments", func(t *testing.T) {
+			// six segments in batches of four and two
+			batches, queries := iterate(t, "segments", func() error {
+				_, err := db.IterateLoopSegments(ctx, metabase.IterateLoopSegments{
+					BatchSize: 4,
+				}, func(ctx context.Context, it metabase.LoopSegmentsIterator) error {
+					var entry metabase.LoopSegmentEntry
+					for it.Next(ctx, &entry) {
+					}
+					return nil
+				})
+				return err
+			})
+			require.EqualValues(t, 2, batches)
+			require.EqualValues(t, 2, queries)
+		})
+	})
+}
+
+func TestIterateLoopBatchSizeLimit(t *testing.T) {
+	metabasetest.RunWithConfig(t, metabase.Config{LoopBatchSizeLimit: 2}, func(ctx *testcontext.Context, t *testing.T, db *metabase.DB) {
+		defer metabasetest.DeleteAll{}.Check(ctx, t, db)
+
+		batches := monkit.Default.ScopeNamed("storj.io/storj/satellite/metabase").Counter("segments_loop_batches")
+
+		metabasetest.CreateObject(ctx, t, db, metabasetest.RandObjectStream(), 5)
+
+		// the requested batch size is clamped to the configured limit, so five
+		// segments are queried in three batches.
+		before := batches.Current()
+		count, err := db.IterateLoopSegments(ctx, metabase.IterateLoopSegments{
+			BatchSize: 10,
+		}, func(ctx context.Context, it metabase.LoopSegmentsIterator) error {
+			var entry metabase.LoopSegmentEntry
+			for it.Next(ctx, &entry) {
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 5, count)
+		require.EqualValues(t, 3, batches.Current()-before)
 	})
 }
 
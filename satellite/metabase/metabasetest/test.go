@@ -391,7 +391,7 @@ type IterateLoopSegments struct {
 // Check runs the test.
 func (step IterateLoopSegments) Check(ctx *testcontext.Context, t testing.TB, db *metabase.DB) {
 	result := make([]metabase.LoopSegmentEntry, 0, 10)
-	err := db.IterateLoopSegments(ctx, step.Opts,
+	count, err := db.IterateLoopSegments(ctx, step.Opts,
 		func(ctx context.Context, iterator metabase.LoopSegmentsIterator) error {
 			var entry metabase.LoopSegmentEntry
 			for iterator.Next(ctx, &entry) {
@@ -400,6 +400,7 @@ func (step IterateLoopSegments) Check(ctx *testcontext.Context, t testing.TB, db
 			return nil
 		})
 	checkError(t, err, step.ErrClass, step.ErrText)
+	require.Equal(t, int64(len(result)), count)
 
 	if len(result) == 0 {
 		result = nil
@@ -660,8 +661,9 @@ type IterateLoopObjects struct {
 func (step IterateLoopObjects) Check(ctx *testcontext.Context, t testing.TB, db *metabase.DB) {
 	var result LoopIterateCollector
 
-	err := db.IterateLoopObjects(ctx, step.Opts, result.Add)
+	count, err := db.IterateLoopObjects(ctx, step.Opts, result.Add)
 	checkError(t, err, step.ErrClass, step.ErrText)
+	require.Equal(t, int64(len(result)), count)
 
 	diff := cmp.Diff(step.Result, []metabase.LoopObjectEntry(result), cmpopts.EquateApproxTime(5*time.Second))
 	require.Zero(t, diff)
@@ -152,7 +152,7 @@ type MetabaseDB interface {
 	// Now returns the time on the database.
 	Now(ctx context.Context) (time.Time, error)
 	// IterateLoopStreams iterates through all streams passed in as arguments.
-	IterateLoopSegments(ctx context.Context, opts metabase.IterateLoopSegments, fn func(context.Context, metabase.LoopSegmentsIterator) error) (err error)
+	IterateLoopSegments(ctx context.Context, opts metabase.IterateLoopSegments, fn func(context.Context, metabase.LoopSegmentsIterator) error) (count int64, err error)
 
 	// GetTableStats gathers statistics about the tables.
 	GetTableStats(context.Context, metabase.GetTableStats) (metabase.TableStats, error)
@@ -448,7 +448,7 @@ func (loop *Service) iterateSegments(ctx context.Context, observers []*observerC
 		return processed, observers, errNoObservers
 	}
 
-	err = loop.metabaseDB.IterateLoopSegments(ctx, metabase.IterateLoopSegments{
+	_, err = loop.metabaseDB.IterateLoopSegments(ctx, metabase.IterateLoopSegments{
 		BatchSize:          limit,
 		AsOfSystemTime:     startingTime,
 		AsOfSystemInterval: loop.config.AsOfSystemInterval,
@@ -0,0 +1,150 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/common/sync2"
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/console"
+)
+
+// apiKeyUsageKey is the key of the usage aggregated in APIKeyUsageCache.
+type apiKeyUsageKey struct {
+	APIKeyID uuid.UUID
+	Day      time.Time
+}
+
+// apiKeyUsage is the usage of an api key on a day.
+type apiKeyUsage struct {
+	Requests   int64
+	LastUsedAt time.Time
+}
+
+// APIKeyUsageCache aggregates the usage of api keys in memory and flushes it
+// to the database in batches, so that authorizing a request doesn't write to
+// the database. The pending usage is flushed when the cache is closed, but it
+// is lost when the process stops without closing it.
+//
+// architecture: Chore
+type APIKeyUsageCache struct {
+	log       *zap.Logger
+	db        APIKeys
+	batchSize int
+	wg        sync.WaitGroup
+
+	mu      sync.Mutex
+	pending map[apiKeyUsageKey]apiKeyUsage
+
+	Loop *sync2.Cycle
+}
+
+// NewAPIKeyUsageCache creates a new api key usage cache.
+func NewAPIKeyUsageCache(log *zap.Logger, db APIKeys, config APIKeyUsageConfig) *APIKeyUsageCache {
+	return &APIKeyUsageCache{
+		log:       log,
+		db:        db,
+		batchSize: config.BatchSize,
+		pending:   make(map[apiKeyUsageKey]apiKeyUsage),
+		Loop:      sync2.NewCycle(config.FlushInterval),
+	}
+}
+
+// Record adds a request authorized by the api key at usedAt. The usage is
+// flushed in the background once the batch size is reached. The flush isn't
+// part of the request, so it doesn't use the context of the request.
+func (cache *APIKeyUsageCache) Record(keyID uuid.UUID, usedAt time.Time) {
+	usedAt = usedAt.UTC()
+	key := apiKeyUsageKey{
+		APIKeyID: keyID,
+		Day:      time.Date(usedAt.Year(), usedAt.Month(), usedAt.Day(), 0, 0, 0, 0, time.UTC),
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	usage := cache.pending[key]
+	usage.Requests++
+	if usedAt.After(usage.LastUsedAt) {
+		usage.LastUsedAt = usedAt
+	}
+	cache.pending[key] = usage
+
+	if len(cache.pending) < cache.batchSize {
+		return
+	}
+	pending := cache.reset()
+
+	cache.wg.Add(1)
+	go func() {
+		defer cache.wg.Done()
+		cache.flush(context.Background(), pending)
+	}()
+}
+
+// Flush writes all the aggregated usage to the database.
+func (cache *APIKeyUsageCache) Flush(ctx context.Context) {
+	defer mon.Task()(&ctx)(nil)
+
+	cache.mu.Lock()
+	pending := cache.reset()
+	cache.mu.Unlock()
+
+	cache.flush(ctx, pending)
+}
+
+// reset must be called with the lock held. It returns the pending usage and
+// replaces it with an empty one.
+func (cache *APIKeyUsageCache) reset() map[apiKeyUsageKey]apiKeyUsage {
+	pending := cache.pending
+	cache.pending = make(map[apiKeyUsageKey]apiKeyUsage)
+	return pending
+}
+
+// flush writes the usage to the database.
+func (cache *APIKeyUsageCache) flush(ctx context.Context, pending map[apiKeyUsageKey]apiKeyUsage) {
+	defer mon.Task()(&ctx)(nil)
+
+	if len(pending) == 0 {
+		return
+	}
+
+	updates := make([]console.APIKeyUsageUpdate, 0, len(pending))
+	for key, usage := range pending {
+		updates = append(updates, console.APIKeyUsageUpdate{
+			APIKeyID:   key.APIKeyID,
+			Day:        key.Day,
+			Requests:   usage.Requests,
+			LastUsedAt: usage.LastUsedAt,
+		})
+	}
+
+	err := cache.db.UpdateUsage(ctx, updates)
+	if err != nil {
+		cache.log.Warn("unable to record api key usage", zap.Int("keys", len(updates)), zap.Error(err))
+	}
+}
+
+// Run flushes the aggregated usage in intervals.
+func (cache *APIKeyUsageCache) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return cache.Loop.Run(ctx, func(ctx context.Context) error {
+		cache.Flush(ctx)
+		return nil
+	})
+}
+
+// Close stops flushing the usage in intervals, waits for the background
+// flushes and flushes the pending usage.
+func (cache *APIKeyUsageCache) Close() error {
+	cache.Loop.Close()
+	cache.wg.Wait()
+	cache.Flush(context.Background())
+	return nil
+}
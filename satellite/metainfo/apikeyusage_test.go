@@ -0,0 +1,100 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/console"
+	"storj.io/storj/satellite/metainfo"
+)
+
+// usageAPIKeys collects the usage written by APIKeyUsageCache.
+type usageAPIKeys struct {
+	metainfo.APIKeys
+
+	mu      sync.Mutex
+	batches [][]console.APIKeyUsageUpdate
+	flushed chan struct{}
+}
+
+func (keys *usageAPIKeys) UpdateUsage(ctx context.Context, updates []console.APIKeyUsageUpdate) error {
+	keys.mu.Lock()
+	keys.batches = append(keys.batches, updates)
+	keys.mu.Unlock()
+
+	keys.flushed <- struct{}{}
+	return nil
+}
+
+func (keys *usageAPIKeys) requests() map[uuid.UUID]int64 {
+	keys.mu.Lock()
+	defer keys.mu.Unlock()
+
+	requests := make(map[uuid.UUID]int64)
+	for _, batch := range keys.batches {
+		for _, update := range batch {
+			requests[update.APIKeyID] += update.Requests
+		}
+	}
+	return requests
+}
+
+func TestAPIKeyUsageCache(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db := &usageAPIKeys{flushed: make(chan struct{}, 10)}
+	cache := metainfo.NewAPIKeyUsageCache(zaptest.NewLogger(t), db, metainfo.APIKeyUsageConfig{
+		FlushInterval: time.Hour,
+		BatchSize:     3,
+	})
+
+	first, second, third := testrand.UUID(), testrand.UUID(), testrand.UUID()
+	day := time.Date(2021, 9, 2, 10, 0, 0, 0, time.UTC)
+
+	// the requests of a key on the same day are aggregated, keeping the last use.
+	cache.Record(first, day)
+	cache.Record(first, day.Add(time.Hour))
+	cache.Record(second, day)
+	require.Empty(t, db.requests())
+
+	// reaching the batch size flushes in the background.
+	cache.Record(third, day)
+	<-db.flushed
+
+	require.Len(t, db.batches, 1)
+	require.ElementsMatch(t, []console.APIKeyUsageUpdate{
+		{APIKeyID: first, Day: time.Date(2021, 9, 2, 0, 0, 0, 0, time.UTC), Requests: 2, LastUsedAt: day.Add(time.Hour)},
+		{APIKeyID: second, Day: time.Date(2021, 9, 2, 0, 0, 0, 0, time.UTC), Requests: 1, LastUsedAt: day},
+		{APIKeyID: third, Day: time.Date(2021, 9, 2, 0, 0, 0, 0, time.UTC), Requests: 1, LastUsedAt: day},
+	}, db.batches[0])
+
+	// flushing writes the rest.
+	cache.Record(first, day.AddDate(0, 0, 1))
+	cache.Flush(ctx)
+	<-db.flushed
+
+	require.Equal(t, map[uuid.UUID]int64{first: 3, second: 1, third: 1}, db.requests())
+
+	// flushing without usage doesn't write.
+	cache.Flush(ctx)
+	require.Len(t, db.batches, 2)
+
+	// closing flushes the pending usage.
+	cache.Record(second, day)
+	require.NoError(t, cache.Close())
+	<-db.flushed
+
+	require.Equal(t, map[uuid.UUID]int64{first: 3, second: 2, third: 1}, db.requests())
+}
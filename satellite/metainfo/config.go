@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"storj.io/common/memory"
+	"storj.io/storj/satellite/metabase"
 	"storj.io/storj/satellite/metabase/segmentloop"
 	"storj.io/storj/satellite/metainfo/piecedeletion"
 )
@@ -98,6 +99,12 @@ type RateLimiterConfig struct {
 	CacheExpiration time.Duration `help:"how long to cache the projects limiter." releaseDefault:"10m" devDefault:"10s"`
 }
 
+// APIKeyUsageConfig is a configuration struct for recording the usage of api keys.
+type APIKeyUsageConfig struct {
+	FlushInterval time.Duration `help:"how often the request counts and last uses of api keys are flushed to the database." devDefault:"30s" releaseDefault:"1m" testDefault:"$TESTINTERVAL"`
+	BatchSize     int           `help:"number of api keys whose usage is collected before it's flushed to the database." default:"1000"`
+}
+
 // ProjectLimitConfig is a configuration struct for default project limits.
 type ProjectLimitConfig struct {
 	MaxBuckets int `help:"max bucket count for a project." default:"100" testDefault:"10"`
@@ -106,6 +113,7 @@ type ProjectLimitConfig struct {
 // Config is a configuration struct that is everything you need to start a metainfo.
 type Config struct {
 	DatabaseURL          string               `help:"the database connection string to use" default:"postgres://"`
+	Metabase             metabase.Config      `help:"metabase configuration"`
 	MinRemoteSegmentSize memory.Size          `default:"1240" testDefault:"0" help:"minimum remote segment size"` // TODO: fix tests to work with 1024
 	MaxInlineSegmentSize memory.Size          `default:"4KiB" help:"maximum inline segment size"`
 	MaxSegmentSize       memory.Size          `default:"64MiB" help:"maximum segment size"`
@@ -115,6 +123,7 @@ type Config struct {
 	RS                   RSConfig             `releaseDefault:"29/35/80/110-256B" devDefault:"4/6/8/10-256B" help:"redundancy scheme configuration in the format k/m/o/n-sharesize"`
 	SegmentLoop          segmentloop.Config   `help:"segment loop configuration"`
 	RateLimiter          RateLimiterConfig    `help:"rate limiter configuration"`
+	APIKeyUsage          APIKeyUsageConfig    `help:"api key usage configuration"`
 	ProjectLimits        ProjectLimitConfig   `help:"project limit configuration"`
 	PieceDeletion        piecedeletion.Config `help:"piece deletion configuration"`
 }
@@ -56,6 +56,7 @@ var (
 // architecture: Database
 type APIKeys interface {
 	GetByHead(ctx context.Context, head []byte) (*console.APIKeyInfo, error)
+	UpdateUsage(ctx context.Context, updates []console.APIKeyUsageUpdate) error
 }
 
 // Endpoint metainfo endpoint.
@@ -75,6 +76,7 @@ type Endpoint struct {
 	projectUsage         *accounting.Service
 	projects             console.Projects
 	apiKeys              APIKeys
+	apiKeyUsage          *APIKeyUsageCache
 	satellite            signing.Signer
 	limiterCache         *lrucache.ExpiringLRU
 	encInlineSegmentSize int64 // max inline segment size + encryption overhead
@@ -88,7 +90,7 @@ type Endpoint struct {
 func NewEndpoint(log *zap.Logger, metainfo *Service, deletePieces *piecedeletion.Service,
 	orders *orders.Service, cache *overlay.Service, attributions attribution.DB,
 	partners *rewards.PartnersService, peerIdentities overlay.PeerIdentities,
-	apiKeys APIKeys, projectUsage *accounting.Service, projects console.Projects,
+	apiKeys APIKeys, apiKeyUsage *APIKeyUsageCache, projectUsage *accounting.Service, projects console.Projects,
 	satellite signing.Signer, revocations revocation.DB, config Config) (*Endpoint, error) {
 	// TODO do something with too many params
 
@@ -119,6 +121,7 @@ func NewEndpoint(log *zap.Logger, metainfo *Service, deletePieces *piecedeletion
 		partners:            partners,
 		pointerVerification: pointerverification.NewService(peerIdentities),
 		apiKeys:             apiKeys,
+		apiKeyUsage:         apiKeyUsage,
 		projectUsage:        projectUsage,
 		projects:            projects,
 		satellite:           satellite,
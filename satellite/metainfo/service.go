@@ -86,6 +86,91 @@ func (s *Service) IsBucketEmpty(ctx context.Context, projectID uuid.UUID, bucket
 	return empty, Error.Wrap(err)
 }
 
+// DeleteBucketObjects deletes all objects in the bucket. Unlike deleting a
+// bucket through the endpoint, the pieces are not deleted from the storage
+// nodes; garbage collection removes them from the nodes once they are no
+// longer referenced by any segment. The number of pieces left for garbage
+// collection is tracked by the bucket_delete_pieces_left_for_gc metric.
+func (s *Service) DeleteBucketObjects(ctx context.Context, projectID uuid.UUID, bucketName []byte) (deleted int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	deleted, err = s.metabaseDB.DeleteBucketObjects(ctx, metabase.DeleteBucketObjects{
+		Bucket: metabase.BucketLocation{
+			ProjectID:  projectID,
+			BucketName: string(bucketName),
+		},
+		DeletePieces: func(ctx context.Context, segments []metabase.DeletedSegmentInfo) error {
+			var pieces int
+			for _, segment := range segments {
+				pieces += len(segment.Pieces)
+			}
+			mon.Meter("bucket_delete_pieces_left_for_gc").Mark(pieces)
+			return nil
+		},
+	})
+	return deleted, Error.Wrap(err)
+}
+
+// ValidateBucketName validates that the bucket name follows the bucket naming rules.
+func (s *Service) ValidateBucketName(bucketName []byte) error {
+	return ValidateBucketName(bucketName)
+}
+
+// ListBucketObjects lists the committed objects and prefixes of the bucket which
+// are under the prefix, starting after the cursor. The cursor and the keys of the
+// returned entries are relative to the prefix. The limit is capped at metabase.ListLimit.
+func (s *Service) ListBucketObjects(ctx context.Context, projectID uuid.UUID, bucketName []byte, prefix, cursor metabase.ObjectKey, limit int) (entries []metabase.ObjectEntry, more bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	metabase.ListLimit.Ensure(&limit)
+
+	if prefix != "" && prefix[len(prefix)-1] != metabase.Delimiter {
+		prefix += metabase.ObjectKey(metabase.Delimiter)
+	}
+	if cursor != "" {
+		cursor = prefix + cursor
+	}
+
+	err = s.metabaseDB.IterateObjectsAllVersionsWithStatus(ctx,
+		metabase.IterateObjectsWithStatus{
+			ProjectID:  projectID,
+			BucketName: string(bucketName),
+			Prefix:     prefix,
+			Cursor: metabase.IterateCursor{
+				Key:     cursor,
+				Version: 1,
+			},
+			BatchSize: limit + 1,
+			Status:    metabase.Committed,
+		}, func(ctx context.Context, it metabase.ObjectsIterator) error {
+			entry := metabase.ObjectEntry{}
+			for len(entries) < limit && it.Next(ctx, &entry) {
+				entries = append(entries, entry)
+			}
+			more = it.Next(ctx, &entry)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, false, Error.Wrap(err)
+	}
+	return entries, more, nil
+}
+
+// GetBucketObject returns the latest version of the committed object.
+func (s *Service) GetBucketObject(ctx context.Context, projectID uuid.UUID, bucketName []byte, key metabase.ObjectKey) (_ metabase.Object, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	object, err := s.metabaseDB.GetObjectLatestVersion(ctx, metabase.GetObjectLatestVersion{
+		ObjectLocation: metabase.ObjectLocation{
+			ProjectID:  projectID,
+			BucketName: string(bucketName),
+			ObjectKey:  key,
+		},
+	})
+	return object, Error.Wrap(err)
+}
+
 // ListBuckets returns a list of buckets for a project.
 func (s *Service) ListBuckets(ctx context.Context, projectID uuid.UUID, listOpts storj.BucketListOptions, allowedBuckets macaroon.AllowedBuckets) (bucketList storj.BucketList, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -58,9 +58,20 @@ func (endpoint *Endpoint) validateAuth(ctx context.Context, header *pb.RequestHe
 		return nil, rpcstatus.Error(rpcstatus.PermissionDenied, "Unauthorized API credentials")
 	}
 
+	endpoint.recordAPIKeyUsage(ctx, keyInfo.ID)
+
 	return keyInfo, nil
 }
 
+// recordAPIKeyUsage records that the api key was used. The daily request
+// count and the last use of the key are aggregated in memory and flushed in
+// batches, so that the request doesn't wait for a database write.
+func (endpoint *Endpoint) recordAPIKeyUsage(ctx context.Context, keyID uuid.UUID) {
+	defer mon.Task()(&ctx)(nil)
+
+	endpoint.apiKeyUsage.Record(keyID, time.Now())
+}
+
 func (endpoint *Endpoint) validateBasic(ctx context.Context, header *pb.RequestHeader) (_ *macaroon.APIKey, _ *console.APIKeyInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
@@ -154,6 +165,12 @@ func (endpoint *Endpoint) validateBucket(ctx context.Context, bucket []byte) (er
 		return Error.Wrap(storj.ErrNoBucket.New(""))
 	}
 
+	return ValidateBucketName(bucket)
+}
+
+// ValidateBucketName validates that the bucket name follows the bucket naming
+// rules, which are the same as those of S3.
+func ValidateBucketName(bucket []byte) (err error) {
 	if len(bucket) < 3 || len(bucket) > 63 {
 		return Error.New("bucket name must be at least 3 and no more than 63 characters long")
 	}
@@ -5,6 +5,7 @@ package metrics
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/spacemonkeygo/monkit/v3"
@@ -23,8 +24,13 @@ var (
 
 // Config contains configurable values for metrics collection.
 type Config struct {
+	Workers int `help:"number of workers counting the segments, the segments are counted serially when at most 1" default:"1"`
 }
 
+// parallelCounterBatchSize is the minimum number of segments in a batch
+// counted by a worker.
+const parallelCounterBatchSize = 1000
+
 // Chore implements the metrics chore.
 //
 // architecture: Chore
@@ -44,6 +50,7 @@ func NewChore(log *zap.Logger, config Config, loop *segmentloop.Service) *Chore
 		// This chore monitors segment loop, so it's fine to use very small cycle time.
 		Loop:        sync2.NewCycle(time.Nanosecond),
 		segmentLoop: loop,
+		Counter:     NewCounter(),
 	}
 }
 
@@ -54,13 +61,12 @@ func (chore *Chore) Run(ctx context.Context) (err error) {
 	return chore.Loop.Run(ctx, func(ctx context.Context) (err error) {
 		defer mon.Task()(&ctx)(&err)
 
-		chore.Counter = NewCounter()
-
-		err = chore.segmentLoop.Monitor(ctx, chore.Counter)
+		counter, err := chore.countSegments(ctx)
 		if err != nil {
 			chore.log.Error("error joining segment loop", zap.Error(err))
 			return nil
 		}
+		chore.Counter = counter
 		mon.IntVal("remote_dependent_object_count").Observe(chore.Counter.RemoteObjects)
 		mon.IntVal("inline_object_count").Observe(chore.Counter.InlineObjects)
 
@@ -70,6 +76,12 @@ func (chore *Chore) Run(ctx context.Context) (err error) {
 		mon.IntVal("total_inline_segments").Observe(chore.Counter.TotalInlineSegments) //mon:locked
 		mon.IntVal("total_remote_segments").Observe(chore.Counter.TotalRemoteSegments) //mon:locked
 
+		for bucket := 0; bucket < SegmentSizeBuckets; bucket++ {
+			limit := monkit.NewSeriesTag("le", strconv.FormatInt(SegmentSizeBucketLimit(bucket).Int64(), 10))
+			mon.IntVal("inline_segment_size_histogram", limit).Observe(chore.Counter.InlineSegmentSizes[bucket])
+			mon.IntVal("remote_segment_size_histogram", limit).Observe(chore.Counter.RemoteSegmentSizes[bucket])
+		}
+
 		// TODO move this metric to a place where objects are iterated e.g. tally
 		// or drop it completely as we can easily get this value with redash
 		// mon.IntVal("total_object_count").Observe(chore.Counter.ObjectCount)
@@ -78,6 +90,28 @@ func (chore *Chore) Run(ctx context.Context) (err error) {
 	})
 }
 
+// countSegments collects the counts of the segments from the segment loop,
+// with multiple workers when configured.
+func (chore *Chore) countSegments(ctx context.Context) (_ *Counter, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if chore.config.Workers <= 1 {
+		// the counter is reset when the loop starts.
+		if err := chore.segmentLoop.Monitor(ctx, chore.Counter); err != nil {
+			return nil, err
+		}
+		return chore.Counter, nil
+	}
+
+	parallel := NewParallelCounter(ctx, chore.config.Workers, parallelCounterBatchSize)
+	err = chore.segmentLoop.Monitor(ctx, parallel)
+	counter, finishErr := parallel.Finish(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return counter, finishErr
+}
+
 // Close closes metrics chore.
 func (chore *Chore) Close() error {
 	chore.Loop.Close()
@@ -27,6 +27,10 @@ type Counter struct {
 	TotalInlineSegments int64
 	TotalRemoteSegments int64
 
+	// distribution of encrypted segment sizes
+	InlineSegmentSizes SegmentSizeHistogram
+	RemoteSegmentSizes SegmentSizeHistogram
+
 	lastStreamID uuid.UUID
 	onlyInline   bool
 }
@@ -38,8 +42,17 @@ func NewCounter() *Counter {
 	}
 }
 
-// LoopStarted is called at each start of a loop.
+// Reset zeroes all the counts, so that the counter can be reused.
+func (counter *Counter) Reset() {
+	*counter = Counter{
+		onlyInline: true,
+	}
+}
+
+// LoopStarted is called at each start of a loop, and resets the counts of the
+// previous loop.
 func (counter *Counter) LoopStarted(context.Context, segmentloop.LoopInfo) (err error) {
+	counter.Reset()
 	return nil
 }
 
@@ -51,6 +64,7 @@ func (counter *Counter) RemoteSegment(ctx context.Context, segment *segmentloop.
 
 	counter.TotalRemoteBytes += int64(segment.EncryptedSize)
 	counter.TotalRemoteSegments++
+	counter.RemoteSegmentSizes.Add(int64(segment.EncryptedSize))
 
 	if counter.lastStreamID.Compare(segment.StreamID) != 0 {
 		counter.RemoteObjects++
@@ -67,6 +81,7 @@ func (counter *Counter) InlineSegment(ctx context.Context, segment *segmentloop.
 
 	counter.TotalInlineBytes += int64(segment.EncryptedSize)
 	counter.TotalInlineSegments++
+	counter.InlineSegmentSizes.Add(int64(segment.EncryptedSize))
 
 	if counter.lastStreamID.Compare(segment.StreamID) != 0 {
 		if counter.onlyInline {
@@ -80,3 +95,24 @@ func (counter *Counter) InlineSegment(ctx context.Context, segment *segmentloop.
 	}
 	return nil
 }
+
+// add adds the counts of the other counter, which counted the segments after
+// those of this counter.
+func (counter *Counter) add(other *Counter) {
+	counter.RemoteObjects += other.RemoteObjects
+	counter.InlineObjects += other.InlineObjects
+
+	counter.TotalInlineBytes += other.TotalInlineBytes
+	counter.TotalRemoteBytes += other.TotalRemoteBytes
+
+	counter.TotalInlineSegments += other.TotalInlineSegments
+	counter.TotalRemoteSegments += other.TotalRemoteSegments
+
+	for bucket := 0; bucket < SegmentSizeBuckets; bucket++ {
+		counter.InlineSegmentSizes[bucket] += other.InlineSegmentSizes[bucket]
+		counter.RemoteSegmentSizes[bucket] += other.RemoteSegmentSizes[bucket]
+	}
+
+	counter.lastStreamID = other.lastStreamID
+	counter.onlyInline = other.onlyInline
+}
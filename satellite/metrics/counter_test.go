@@ -4,15 +4,20 @@
 package metrics_test
 
 import (
+	"sort"
 	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"storj.io/common/memory"
+	"storj.io/common/storj"
 	"storj.io/common/testcontext"
 	"storj.io/common/testrand"
 	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite/metabase"
+	"storj.io/storj/satellite/metabase/segmentloop"
+	"storj.io/storj/satellite/metrics"
 )
 
 func TestCounterInlineAndRemote(t *testing.T) {
@@ -52,9 +57,71 @@ func TestCounterInlineAndRemote(t *testing.T) {
 		require.EqualValues(t, 2080, metricsChore.Counter.TotalInlineBytes)
 		// 2 remote segments * (8192 + encryption overhead)
 		require.EqualValues(t, 29696, metricsChore.Counter.TotalRemoteBytes)
+
+		// the next run doesn't add to the counts of the previous one
+		metricsChore.Loop.TriggerWait()
+		require.EqualValues(t, 2, metricsChore.Counter.InlineObjects)
+		require.EqualValues(t, 2, metricsChore.Counter.RemoteObjects)
+		require.EqualValues(t, 2, metricsChore.Counter.TotalInlineSegments)
+		require.EqualValues(t, 2, metricsChore.Counter.TotalRemoteSegments)
 	})
 }
 
+func TestCounterSegmentSizes(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		ul := planet.Uplinks[0]
+		metricsChore := satellite.Metrics.Chore
+		metricsChore.Loop.Pause()
+
+		// upload 2 inline files of 1KiB, which are 1040 bytes once encrypted
+		for i := 0; i < 2; i++ {
+			testData := testrand.Bytes(memory.KiB)
+			path := "/some/inline/path/" + strconv.Itoa(i)
+			err := ul.Upload(ctx, satellite, "bucket", path, testData)
+			require.NoError(t, err)
+		}
+
+		// upload 3 remote files of 8KiB, which are 14848 bytes once encrypted
+		for i := 0; i < 3; i++ {
+			testData := testrand.Bytes(8 * memory.KiB)
+			path := "/some/remote/path/" + strconv.Itoa(i)
+			err := ul.Upload(ctx, satellite, "testbucket", path, testData)
+			require.NoError(t, err)
+		}
+
+		metricsChore.Loop.TriggerWait()
+
+		var expectedInline, expectedRemote metrics.SegmentSizeHistogram
+		// bucket 1 is (1KiB, 2KiB]
+		expectedInline[1] = 2
+		// bucket 4 is (8KiB, 16KiB]
+		expectedRemote[4] = 3
+
+		require.Equal(t, expectedInline, metricsChore.Counter.InlineSegmentSizes)
+		require.Equal(t, expectedRemote, metricsChore.Counter.RemoteSegmentSizes)
+	})
+}
+
+func TestSegmentSizeHistogram(t *testing.T) {
+	var histogram metrics.SegmentSizeHistogram
+	histogram.Add(0)
+	histogram.Add(memory.KiB.Int64())
+	histogram.Add(memory.KiB.Int64() + 1)
+	histogram.Add(64 * memory.MiB.Int64())
+	histogram.Add(1 * memory.GiB.Int64())
+
+	var expected metrics.SegmentSizeHistogram
+	expected[0] = 2
+	expected[1] = 1
+	expected[metrics.SegmentSizeBuckets-1] = 2
+	require.Equal(t, expected, histogram)
+
+	require.Equal(t, 64*memory.MiB, metrics.SegmentSizeBucketLimit(metrics.SegmentSizeBuckets-1))
+}
+
 func TestCounterInlineOnly(t *testing.T) {
 	testplanet.Run(t, testplanet.Config{
 		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
@@ -103,3 +170,110 @@ func TestCounterRemoteOnly(t *testing.T) {
 		require.EqualValues(t, 2, metricsChore.Counter.RemoteObjects)
 	})
 }
+
+func TestParallelCounter(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	// objects with every mix of inline and remote segments, in the order of
+	// the segment loop
+	var segments []segmentloop.Segment
+	for i := 0; i < 200; i++ {
+		streamID := testrand.UUID()
+		for position := 0; position < 1+testrand.Intn(4); position++ {
+			segment := segmentloop.Segment{
+				StreamID:      streamID,
+				Position:      metabase.SegmentPosition{Index: uint32(position)},
+				EncryptedSize: int32(testrand.Intn(10 * memory.KiB.Int())),
+			}
+			if testrand.Intn(2) == 0 {
+				segment.RootPieceID = testrand.PieceID()
+				segment.Redundancy = storj.RedundancyScheme{
+					Algorithm:      storj.ReedSolomon,
+					ShareSize:      256,
+					RequiredShares: 1,
+					RepairShares:   2,
+					OptimalShares:  3,
+					TotalShares:    4,
+				}
+				segment.Pieces = metabase.Pieces{{Number: 0, StorageNode: testrand.NodeID()}}
+				segment.EncryptedSize += int32(memory.MiB.Int())
+			}
+			segments = append(segments, segment)
+		}
+	}
+	sort.SliceStable(segments, func(i, k int) bool {
+		return segments[i].StreamID.Less(segments[k].StreamID)
+	})
+
+	count := func(observer segmentloop.Observer) {
+		for i := range segments {
+			segment := segments[i]
+			var err error
+			if segment.Inline() {
+				err = observer.InlineSegment(ctx, &segment)
+			} else {
+				err = observer.RemoteSegment(ctx, &segment)
+			}
+			require.NoError(t, err)
+		}
+	}
+
+	serial := metrics.NewCounter()
+	count(serial)
+	require.EqualValues(t, len(segments), serial.TotalInlineSegments+serial.TotalRemoteSegments)
+
+	for _, workers := range []int{1, 2, 5} {
+		for _, batchSize := range []int{1, 3, 16, 1000} {
+			parallel := metrics.NewParallelCounter(ctx, workers, batchSize)
+			count(parallel)
+			counter, err := parallel.Finish(ctx)
+			require.NoError(t, err)
+			require.Equal(t, serial, counter, "workers %d, batch size %d", workers, batchSize)
+		}
+	}
+
+	// an empty loop doesn't count anything
+	parallel := metrics.NewParallelCounter(ctx, 2, 10)
+	counter, err := parallel.Finish(ctx)
+	require.NoError(t, err)
+	require.Equal(t, metrics.NewCounter(), counter)
+}
+
+func TestCounterReset(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	newSegments := func(sizes ...int32) []segmentloop.Segment {
+		segments := make([]segmentloop.Segment, len(sizes))
+		for i, size := range sizes {
+			segments[i] = segmentloop.Segment{StreamID: testrand.UUID(), EncryptedSize: size}
+		}
+		return segments
+	}
+	run := func(counter *metrics.Counter, segments []segmentloop.Segment) {
+		require.NoError(t, counter.LoopStarted(ctx, segmentloop.LoopInfo{}))
+		for i := range segments {
+			require.NoError(t, counter.InlineSegment(ctx, &segments[i]))
+		}
+	}
+
+	counter := metrics.NewCounter()
+	run(counter, newSegments(10, 20, 30))
+	require.EqualValues(t, 3, counter.InlineObjects)
+	require.EqualValues(t, 3, counter.TotalInlineSegments)
+	require.EqualValues(t, 60, counter.TotalInlineBytes)
+
+	// the second run only counts its own segments
+	second := newSegments(40)
+	run(counter, second)
+	expected := metrics.NewCounter()
+	run(expected, second)
+	require.Equal(t, expected, counter)
+	require.EqualValues(t, 1, counter.InlineObjects)
+	require.EqualValues(t, 1, counter.TotalInlineSegments)
+	require.EqualValues(t, 40, counter.TotalInlineBytes)
+
+	counter.Reset()
+	require.Equal(t, metrics.NewCounter(), counter)
+}
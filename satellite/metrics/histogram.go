@@ -0,0 +1,36 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metrics
+
+import (
+	"storj.io/common/memory"
+)
+
+const (
+	// segmentSizeHistogramMin is the upper limit of the smallest bucket.
+	segmentSizeHistogramMin = memory.KiB
+	// SegmentSizeBuckets is the number of buckets in a SegmentSizeHistogram,
+	// with limits doubling from 1KiB up to 64MiB (the default max segment size).
+	SegmentSizeBuckets = 17
+)
+
+// SegmentSizeHistogram counts segments by their encrypted size. Bucket i counts
+// the segments that are larger than the limit of bucket i-1 and at most
+// SegmentSizeBucketLimit(i). Segments larger than the last limit are counted in
+// the last bucket.
+type SegmentSizeHistogram [SegmentSizeBuckets]int64
+
+// SegmentSizeBucketLimit returns the inclusive upper limit of the bucket.
+func SegmentSizeBucketLimit(bucket int) memory.Size {
+	return segmentSizeHistogramMin << bucket
+}
+
+// Add counts a segment with the encrypted size.
+func (histogram *SegmentSizeHistogram) Add(size int64) {
+	bucket := 0
+	for bucket < SegmentSizeBuckets-1 && size > SegmentSizeBucketLimit(bucket).Int64() {
+		bucket++
+	}
+	histogram[bucket]++
+}
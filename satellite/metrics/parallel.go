@@ -0,0 +1,196 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metrics
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/common/uuid"
+	"storj.io/storj/satellite/metabase/segmentloop"
+)
+
+// ParallelCounter implements the segment loop observer interface, counting the
+// segments with multiple workers.
+//
+// The segments are counted in batches, which are split between objects. The
+// counts of the batches are merged in the loop order, so that the totals are
+// exactly the same as those of a single Counter.
+//
+// architecture: Observer
+type ParallelCounter struct {
+	batchSize int
+	batches   chan counterBatch
+	group     *errgroup.Group
+	// failed is closed when a worker fails.
+	failed <-chan struct{}
+
+	batch counterBatch
+	count int
+
+	mu      sync.Mutex
+	results []counterBatchResult
+}
+
+// counterBatch is a batch of segments of whole objects.
+type counterBatch struct {
+	index int
+	// lastStreamID is the stream ID of the last segment before the batch.
+	lastStreamID uuid.UUID
+	segments     []segmentloop.Segment
+}
+
+// counterBatchResult is the counts of a batch.
+type counterBatchResult struct {
+	index        int
+	startsInline bool
+	counter      *Counter
+}
+
+// NewParallelCounter creates a new counter, which counts the segments in
+// batches of at least batchSize segments with the number of workers.
+// Finish must be called once the segment loop is done.
+func NewParallelCounter(ctx context.Context, workers, batchSize int) *ParallelCounter {
+	if workers < 1 {
+		workers = 1
+	}
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	parallel := &ParallelCounter{
+		batchSize: batchSize,
+		batches:   make(chan counterBatch, workers),
+		group:     group,
+		failed:    groupCtx.Done(),
+	}
+	for i := 0; i < workers; i++ {
+		parallel.group.Go(func() error {
+			for batch := range parallel.batches {
+				if err := parallel.countBatch(groupCtx, batch); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	return parallel
+}
+
+// LoopStarted is called at each start of a loop.
+func (parallel *ParallelCounter) LoopStarted(context.Context, segmentloop.LoopInfo) (err error) {
+	return nil
+}
+
+// RemoteSegment adds the remote segment to the current batch.
+func (parallel *ParallelCounter) RemoteSegment(ctx context.Context, segment *segmentloop.Segment) (err error) {
+	return parallel.add(ctx, segment)
+}
+
+// InlineSegment adds the inline segment to the current batch.
+func (parallel *ParallelCounter) InlineSegment(ctx context.Context, segment *segmentloop.Segment) (err error) {
+	return parallel.add(ctx, segment)
+}
+
+// add adds the segment to the current batch, which is sent to the workers once
+// it's full and the segment belongs to the next object.
+func (parallel *ParallelCounter) add(ctx context.Context, segment *segmentloop.Segment) error {
+	if n := len(parallel.batch.segments); n >= parallel.batchSize {
+		last := parallel.batch.segments[n-1].StreamID
+		if last.Compare(segment.StreamID) != 0 {
+			if err := parallel.send(ctx); err != nil {
+				return err
+			}
+			parallel.batch.lastStreamID = last
+		}
+	}
+
+	parallel.batch.segments = append(parallel.batch.segments, *segment)
+	return nil
+}
+
+// send sends the current batch to the workers and starts the next one.
+func (parallel *ParallelCounter) send(ctx context.Context) error {
+	parallel.batch.index = parallel.count
+	select {
+	case parallel.batches <- parallel.batch:
+	case <-parallel.failed:
+		return Error.New("counting segments failed")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	parallel.count++
+	parallel.batch = counterBatch{
+		segments: make([]segmentloop.Segment, 0, parallel.batchSize),
+	}
+	return nil
+}
+
+// countBatch counts the segments of a batch.
+func (parallel *ParallelCounter) countBatch(ctx context.Context, batch counterBatch) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	counter := NewCounter()
+	counter.lastStreamID = batch.lastStreamID
+	for i := range batch.segments {
+		segment := &batch.segments[i]
+		if segment.Inline() {
+			err = counter.InlineSegment(ctx, segment)
+		} else {
+			err = counter.RemoteSegment(ctx, segment)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	parallel.mu.Lock()
+	defer parallel.mu.Unlock()
+	parallel.results = append(parallel.results, counterBatchResult{
+		index:        batch.index,
+		startsInline: batch.segments[0].Inline(),
+		counter:      counter,
+	})
+	return nil
+}
+
+// Finish counts the remaining segments, stops the workers and returns the
+// totals.
+func (parallel *ParallelCounter) Finish(ctx context.Context) (_ *Counter, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(parallel.batch.segments) > 0 {
+		err = parallel.send(ctx)
+	}
+	close(parallel.batches)
+	if waitErr := parallel.group.Wait(); err == nil {
+		err = waitErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(parallel.results, func(i, k int) bool {
+		return parallel.results[i].index < parallel.results[k].index
+	})
+
+	total := NewCounter()
+	for _, result := range parallel.results {
+		// the first segment of a batch is counted by a fresh counter, while a
+		// single counter would still carry whether the previous object had
+		// only inline segments, which decides how an inline segment is
+		// counted.
+		if result.startsInline && !total.onlyInline {
+			result.counter.InlineObjects--
+			result.counter.RemoteObjects++
+		}
+		total.add(result.counter)
+	}
+	return total, nil
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"math"
 	mathrand "math/rand"
+	"net"
 	"sync"
 	"time"
 
@@ -38,6 +39,9 @@ type Config struct {
 	FlushInterval       time.Duration  `help:"how often to flush the rollups write cache to the database" devDefault:"30s" releaseDefault:"1m" testDefault:"$TESTINTERVAL"`
 	NodeStatusLogging   bool           `hidden:"true" help:"deprecated, log the offline/disqualification status of nodes" default:"false" testDefault:"true"`
 	OrdersSemaphoreSize int            `help:"how many concurrent orders to process at once. zero is unlimited" default:"2"`
+
+	DownloadNodesMultiplier float64 `help:"multiplier applied to the number of order limits returned for a download, giving the uplink extra nodes to choose from" default:"1.0"`
+	DownloadNodesExtra      int     `help:"number of order limits to return for a download in addition to the multiplied number" default:"0"`
 }
 
 // BucketsDB returns information about buckets.
@@ -60,6 +64,9 @@ type Service struct {
 
 	orderExpiration time.Duration
 
+	downloadNodesMultiplier float64
+	downloadNodesExtra      int
+
 	rngMu sync.Mutex
 	rng   *mathrand.Rand
 }
@@ -85,6 +92,9 @@ func NewService(
 
 		orderExpiration: config.Expiration,
 
+		downloadNodesMultiplier: config.DownloadNodesMultiplier,
+		downloadNodesExtra:      config.DownloadNodesExtra,
+
 		rng: mathrand.New(mathrand.NewSource(time.Now().UnixNano())),
 	}, nil
 }
@@ -128,14 +138,13 @@ func (service *Service) updateBandwidth(ctx context.Context, bucket metabase.Buc
 func (service *Service) CreateGetOrderLimits(ctx context.Context, bucket metabase.BucketLocation, segment metabase.Segment, overrideLimit int64) (_ []*pb.AddressedOrderLimit, privateKey storj.PiecePrivateKey, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	redundancy, err := eestream.NewRedundancyStrategyFromStorj(segment.Redundancy)
+	outcome := orderLimitsOutcome{bucket: bucket, action: pb.PieceAction_GET}
+	defer func() { service.logOrderLimits(outcome, err) }()
+
+	redundancy, orderLimit, err := getOrderLimitSize(segment, overrideLimit)
 	if err != nil {
 		return nil, storj.PiecePrivateKey{}, Error.Wrap(err)
 	}
-	orderLimit := eestream.CalcPieceSize(int64(segment.EncryptedSize), redundancy)
-	if overrideLimit > 0 && overrideLimit < orderLimit {
-		orderLimit = overrideLimit
-	}
 
 	nodeIDs := make([]storj.NodeID, len(segment.Pieces))
 	for i, piece := range segment.Pieces {
@@ -153,7 +162,9 @@ func (service *Service) CreateGetOrderLimits(ctx context.Context, bucket metabas
 		return nil, storj.PiecePrivateKey{}, Error.Wrap(err)
 	}
 
-	neededLimits := segment.Redundancy.DownloadNodes()
+	neededLimits := service.downloadNodes(segment.Redundancy)
+	outcome.requested = neededLimits
+	outcome.available = len(nodes)
 
 	pieces := segment.Pieces
 	for _, pieceIndex := range service.perm(len(pieces)) {
@@ -163,20 +174,16 @@ func (service *Service) CreateGetOrderLimits(ctx context.Context, bucket metabas
 			continue
 		}
 
-		address := node.Address.Address
-		if node.LastIPPort != "" {
-			address = node.LastIPPort
-		}
-
 		_, err := signer.Sign(ctx, storj.NodeURL{
 			ID:      piece.StorageNode,
-			Address: address,
+			Address: nodeAddress(node.Address.Address, node.LastIPPort),
 		}, int32(piece.Number))
 		if err != nil {
 			return nil, storj.PiecePrivateKey{}, Error.Wrap(err)
 		}
+		outcome.signed++
 
-		if len(signer.AddressedLimits) >= int(neededLimits) {
+		if len(signer.AddressedLimits) >= neededLimits {
 			break
 		}
 	}
@@ -192,6 +199,119 @@ func (service *Service) CreateGetOrderLimits(ctx context.Context, bucket metabas
 	return signer.AddressedLimits, signer.PrivateKey, nil
 }
 
+// EstimateGetEgress estimates the egress that CreateGetOrderLimits would
+// allocate for downloading the segment, assuming all nodes holding its pieces
+// are online. It doesn't sign any order limits or touch the database.
+func (service *Service) EstimateGetEgress(ctx context.Context, segment metabase.Segment, overrideLimit int64) (_ int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	redundancy, orderLimit, err := getOrderLimitSize(segment, overrideLimit)
+	if err != nil {
+		return 0, Error.Wrap(err)
+	}
+
+	limitCount := len(segment.Pieces)
+	if neededLimits := service.downloadNodes(segment.Redundancy); limitCount > neededLimits {
+		limitCount = neededLimits
+	}
+	if limitCount < redundancy.RequiredCount() {
+		return 0, ErrDownloadFailedNotEnoughPieces.New("not enough pieces: got %d, required %d", limitCount, redundancy.RequiredCount())
+	}
+
+	return int64(limitCount) * orderLimit, nil
+}
+
+// downloadNodes returns the number of order limits to create for a download,
+// which is the minimum needed for the redundancy scheme adjusted by the
+// configured multiplier and extra nodes. It's never less than the minimum.
+func (service *Service) downloadNodes(redundancy storj.RedundancyScheme) int {
+	minimum := int(redundancy.DownloadNodes())
+
+	needed := minimum
+	if service.downloadNodesMultiplier > 1 {
+		needed = int(math.Ceil(float64(minimum) * service.downloadNodesMultiplier))
+	}
+	if service.downloadNodesExtra > 0 {
+		needed += service.downloadNodesExtra
+	}
+
+	return needed
+}
+
+// getOrderLimitSize returns the redundancy strategy of the segment and the
+// limit of each GET order for its pieces.
+func getOrderLimitSize(segment metabase.Segment, overrideLimit int64) (eestream.RedundancyStrategy, int64, error) {
+	redundancy, err := eestream.NewRedundancyStrategyFromStorj(segment.Redundancy)
+	if err != nil {
+		return eestream.RedundancyStrategy{}, 0, err
+	}
+	orderLimit := eestream.CalcPieceSize(int64(segment.EncryptedSize), redundancy)
+	if overrideLimit > 0 && overrideLimit < orderLimit {
+		orderLimit = overrideLimit
+	}
+	return redundancy, orderLimit, nil
+}
+
+// orderLimitsOutcome describes the result of creating order limits, so that it
+// can be logged the same way by all the order limit creators.
+type orderLimitsOutcome struct {
+	bucket metabase.BucketLocation
+	action pb.PieceAction
+
+	// requested is the number of order limits the caller asked for.
+	requested int
+	// available is the number of nodes order limits could be created for.
+	available int
+	// signed is the number of order limits that were signed.
+	signed int
+}
+
+// logOrderLimits logs the outcome of creating order limits at debug level.
+func (service *Service) logOrderLimits(outcome orderLimitsOutcome, err error) {
+	ce := service.log.Check(zap.DebugLevel, "created order limits")
+	if err != nil {
+		ce = service.log.Check(zap.DebugLevel, "failed to create order limits")
+	}
+	if ce == nil {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.Stringer("Project ID", outcome.bucket.ProjectID),
+		zap.String("Bucket", outcome.bucket.BucketName),
+		zap.Stringer("Action", outcome.action),
+		zap.Int("Requested", outcome.requested),
+		zap.Int("Available", outcome.available),
+		zap.Int("Signed", outcome.signed),
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	ce.Write(fields...)
+}
+
+// nodeAddress returns the address to dial a node at, preferring the last
+// ip:port the node was resolved to over the address it advertises.
+func nodeAddress(address, lastIPPort string) string {
+	if lastIPPort := validLastIPPort(lastIPPort); lastIPPort != "" {
+		return lastIPPort
+	}
+	return address
+}
+
+// validLastIPPort returns lastIPPort if it can be dialed, otherwise it returns
+// an empty string. IPv6 addresses must be in the bracketed [host]:port form,
+// as the ones stored when a node checks in are.
+func validLastIPPort(lastIPPort string) string {
+	if lastIPPort == "" {
+		return ""
+	}
+	if _, _, err := net.SplitHostPort(lastIPPort); err != nil {
+		return ""
+	}
+	return lastIPPort
+}
+
 func (service *Service) perm(n int) []int {
 	service.rngMu.Lock()
 	defer service.rngMu.Unlock()
@@ -202,20 +322,20 @@ func (service *Service) perm(n int) []int {
 func (service *Service) CreatePutOrderLimits(ctx context.Context, bucket metabase.BucketLocation, nodes []*overlay.SelectedNode, pieceExpiration time.Time, maxPieceSize int64) (_ storj.PieceID, _ []*pb.AddressedOrderLimit, privateKey storj.PiecePrivateKey, err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	outcome := orderLimitsOutcome{bucket: bucket, action: pb.PieceAction_PUT, requested: len(nodes), available: len(nodes)}
+	defer func() { service.logOrderLimits(outcome, err) }()
+
 	signer, err := NewSignerPut(service, pieceExpiration, time.Now(), maxPieceSize, bucket)
 	if err != nil {
 		return storj.PieceID{}, nil, storj.PiecePrivateKey{}, Error.Wrap(err)
 	}
 
 	for pieceNum, node := range nodes {
-		address := node.Address.Address
-		if node.LastIPPort != "" {
-			address = node.LastIPPort
-		}
-		_, err := signer.Sign(ctx, storj.NodeURL{ID: node.ID, Address: address}, int32(pieceNum))
+		_, err := signer.Sign(ctx, storj.NodeURL{ID: node.ID, Address: nodeAddress(node.Address.Address, node.LastIPPort)}, int32(pieceNum))
 		if err != nil {
 			return storj.PieceID{}, nil, storj.PiecePrivateKey{}, Error.Wrap(err)
 		}
+		outcome.signed++
 	}
 
 	if err := service.updateBandwidth(ctx, bucket, signer.AddressedLimits...); err != nil {
@@ -229,6 +349,9 @@ func (service *Service) CreatePutOrderLimits(ctx context.Context, bucket metabas
 func (service *Service) CreateAuditOrderLimits(ctx context.Context, segment metabase.Segment, skip map[storj.NodeID]bool) (_ []*pb.AddressedOrderLimit, _ storj.PiecePrivateKey, cachedIPsAndPorts map[storj.NodeID]string, err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	outcome := orderLimitsOutcome{action: pb.PieceAction_GET_AUDIT}
+	defer func() { service.logOrderLimits(outcome, err) }()
+
 	nodeIDs := make([]storj.NodeID, len(segment.Pieces))
 	for i, piece := range segment.Pieces {
 		nodeIDs[i] = piece.StorageNode
@@ -239,6 +362,7 @@ func (service *Service) CreateAuditOrderLimits(ctx context.Context, segment meta
 		service.log.Debug("error getting nodes from overlay", zap.Error(err))
 		return nil, storj.PiecePrivateKey{}, nil, Error.Wrap(err)
 	}
+	outcome.available = len(nodes)
 
 	bucket := metabase.BucketLocation{}
 	signer, err := NewSignerAudit(service, segment.RootPieceID, time.Now(), int64(segment.Redundancy.ShareSize), bucket)
@@ -254,19 +378,20 @@ func (service *Service) CreateAuditOrderLimits(ctx context.Context, segment meta
 		if skip[piece.StorageNode] {
 			continue
 		}
+		outcome.requested++
+
 		node, ok := nodes[piece.StorageNode]
 		if !ok {
 			nodeErrors.Add(errs.New("node %q is not reliable", piece.StorageNode))
 			continue
 		}
 
-		address := node.Address.Address
-		if node.LastIPPort != "" {
-			cachedIPsAndPorts[piece.StorageNode] = node.LastIPPort
+		if lastIPPort := validLastIPPort(node.LastIPPort); lastIPPort != "" {
+			cachedIPsAndPorts[piece.StorageNode] = lastIPPort
 		}
 		limit, err := signer.Sign(ctx, storj.NodeURL{
 			ID:      piece.StorageNode,
-			Address: address,
+			Address: node.Address.Address,
 		}, int32(piece.Number))
 		if err != nil {
 			return nil, storj.PiecePrivateKey{}, nil, Error.Wrap(err)
@@ -274,6 +399,7 @@ func (service *Service) CreateAuditOrderLimits(ctx context.Context, segment meta
 
 		limits[piece.Number] = limit
 		limitsCount++
+		outcome.signed++
 	}
 
 	if limitsCount < segment.Redundancy.RequiredShares {
@@ -289,19 +415,17 @@ func (service *Service) CreateAuditOrderLimit(ctx context.Context, nodeID storj.
 	// TODO reduce number of params ?
 	defer mon.Task()(&ctx)(&err)
 
+	outcome := orderLimitsOutcome{action: pb.PieceAction_GET_AUDIT, requested: 1}
+	defer func() { service.logOrderLimits(outcome, err) }()
+
 	node, err := service.overlay.Get(ctx, nodeID)
 	if err != nil {
 		return nil, storj.PiecePrivateKey{}, "", Error.Wrap(err)
 	}
-	if node.Disqualified != nil {
-		return nil, storj.PiecePrivateKey{}, "", overlay.ErrNodeDisqualified.New("%v", nodeID)
-	}
-	if node.ExitStatus.ExitFinishedAt != nil {
-		return nil, storj.PiecePrivateKey{}, "", overlay.ErrNodeFinishedGE.New("%v", nodeID)
-	}
-	if !service.overlay.IsOnline(node) {
-		return nil, storj.PiecePrivateKey{}, "", overlay.ErrNodeOffline.New("%v", nodeID)
+	if err := service.auditNodeError(node); err != nil {
+		return nil, storj.PiecePrivateKey{}, "", err
 	}
+	outcome.available = 1
 
 	signer, err := NewSignerAudit(service, rootPieceID, time.Now(), int64(shareSize), metabase.BucketLocation{})
 	if err != nil {
@@ -315,8 +439,155 @@ func (service *Service) CreateAuditOrderLimit(ctx context.Context, nodeID storj.
 	if err != nil {
 		return nil, storj.PiecePrivateKey{}, "", Error.Wrap(err)
 	}
+	outcome.signed = 1
+
+	return orderLimit, signer.PrivateKey, validLastIPPort(node.LastIPPort), nil
+}
+
+// AuditPiece is a piece to create an audit order limit for.
+type AuditPiece struct {
+	NodeID      storj.NodeID
+	RootPieceID storj.PieceID
+	PieceNum    uint16
+	ShareSize   int32
+}
+
+// AuditPieceLimit is the audit order limit of a piece. Limit is nil and Err is
+// set, e.g. to overlay.ErrNodeOffline, when the limit couldn't be created.
+type AuditPieceLimit struct {
+	Piece           AuditPiece
+	Limit           *pb.AddressedOrderLimit
+	PrivateKey      storj.PiecePrivateKey
+	CachedIPAndPort string
+	Err             error
+}
+
+// CreateAuditPieceOrderLimits creates the audit order limits for downloading
+// a share of each of the pieces, which may be pieces of different segments.
+//
+// The nodes are looked up all at once. The nodes which aren't available for
+// downloading are looked up again in a single query, to tell why the limit
+// couldn't be created for their pieces.
+func (service *Service) CreateAuditPieceOrderLimits(ctx context.Context, pieces []AuditPiece) (_ []AuditPieceLimit, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	outcome := orderLimitsOutcome{action: pb.PieceAction_GET_AUDIT, requested: len(pieces)}
+	defer func() { service.logOrderLimits(outcome, err) }()
+
+	if len(pieces) == 0 {
+		return nil, nil
+	}
+
+	nodeIDs := make([]storj.NodeID, len(pieces))
+	for i, piece := range pieces {
+		nodeIDs[i] = piece.NodeID
+	}
+
+	nodes, err := service.overlay.GetOnlineNodesForGetDelete(ctx, nodeIDs)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	// pieces of the same segment share a signer, like the limits of a single
+	// segment do.
+	type signerKey struct {
+		rootPieceID storj.PieceID
+		shareSize   int32
+	}
+	signers := make(map[signerKey]*Signer)
+	now := time.Now()
+
+	var unavailable []storj.NodeID
+	for _, piece := range pieces {
+		if _, ok := nodes[piece.NodeID]; !ok {
+			unavailable = append(unavailable, piece.NodeID)
+		}
+	}
+	unavailableErrs := service.unavailableAuditNodeErrors(ctx, unavailable)
+
+	limits := make([]AuditPieceLimit, len(pieces))
+	for i, piece := range pieces {
+		limits[i].Piece = piece
+
+		node, ok := nodes[piece.NodeID]
+		if !ok {
+			limits[i].Err = unavailableErrs[piece.NodeID]
+			continue
+		}
+		outcome.available++
+
+		key := signerKey{rootPieceID: piece.RootPieceID, shareSize: piece.ShareSize}
+		signer, ok := signers[key]
+		if !ok {
+			signer, err = NewSignerAudit(service, piece.RootPieceID, now, int64(piece.ShareSize), metabase.BucketLocation{})
+			if err != nil {
+				return nil, Error.Wrap(err)
+			}
+			signers[key] = signer
+		}
+
+		limit, err := signer.Sign(ctx, storj.NodeURL{
+			ID:      piece.NodeID,
+			Address: node.Address.Address,
+		}, int32(piece.PieceNum))
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		outcome.signed++
+
+		limits[i].Limit = limit
+		limits[i].PrivateKey = signer.PrivateKey
+		limits[i].CachedIPAndPort = validLastIPPort(node.LastIPPort)
+	}
+
+	return limits, nil
+}
+
+// auditNodeError returns why the node can't be audited, or nil if it can.
+func (service *Service) auditNodeError(node *overlay.NodeDossier) error {
+	switch {
+	case node.Disqualified != nil:
+		return overlay.ErrNodeDisqualified.New("%v", node.Id)
+	case node.ExitStatus.ExitFinishedAt != nil:
+		return overlay.ErrNodeFinishedGE.New("%v", node.Id)
+	case !service.overlay.IsOnline(node):
+		return overlay.ErrNodeOffline.New("%v", node.Id)
+	}
+	return nil
+}
+
+// unavailableAuditNodeErrors returns why each of the nodes, which aren't
+// available for downloading, can't be audited.
+func (service *Service) unavailableAuditNodeErrors(ctx context.Context, nodeIDs []storj.NodeID) map[storj.NodeID]error {
+	defer mon.Task()(&ctx)(nil)
+
+	nodeErrs := make(map[storj.NodeID]error, len(nodeIDs))
+	if len(nodeIDs) == 0 {
+		return nodeErrs
+	}
 
-	return orderLimit, signer.PrivateKey, node.LastIPPort, nil
+	nodes, err := service.overlay.GetNodes(ctx, nodeIDs)
+	if err != nil {
+		for _, nodeID := range nodeIDs {
+			nodeErrs[nodeID] = Error.Wrap(err)
+		}
+		return nodeErrs
+	}
+
+	for _, nodeID := range nodeIDs {
+		node, ok := nodes[nodeID]
+		if !ok {
+			nodeErrs[nodeID] = Error.Wrap(overlay.ErrNodeNotFound.New("%v", nodeID))
+			continue
+		}
+		if err := service.auditNodeError(node); err != nil {
+			nodeErrs[nodeID] = err
+			continue
+		}
+		// the node came online after it was looked up.
+		nodeErrs[nodeID] = overlay.ErrNodeOffline.New("%v", nodeID)
+	}
+	return nodeErrs
 }
 
 // CreateGetRepairOrderLimits creates the order limits for downloading the
@@ -327,6 +598,36 @@ func (service *Service) CreateAuditOrderLimit(ctx context.Context, nodeID storj.
 func (service *Service) CreateGetRepairOrderLimits(ctx context.Context, bucket metabase.BucketLocation, segment metabase.Segment, healthy metabase.Pieces) (_ []*pb.AddressedOrderLimit, _ storj.PiecePrivateKey, cachedIPsAndPorts map[storj.NodeID]string, err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	limits, privateKey, cachedIPsAndPorts, err := service.createGetRepairOrderLimits(ctx, bucket, segment, healthy)
+	if err != nil {
+		return nil, storj.PiecePrivateKey{}, nil, err
+	}
+
+	if err := service.updateBandwidth(ctx, bucket, limits...); err != nil {
+		return nil, storj.PiecePrivateKey{}, nil, Error.Wrap(err)
+	}
+
+	return limits, privateKey, cachedIPsAndPorts, nil
+}
+
+// CreateGetVerifyOrderLimits creates GET_REPAIR order limits like
+// CreateGetRepairOrderLimits for downloading pieces of segment to verify them.
+// No bandwidth is allocated to a bucket for them, since verification isn't
+// billed.
+func (service *Service) CreateGetVerifyOrderLimits(ctx context.Context, segment metabase.Segment, pieces metabase.Pieces) (_ []*pb.AddressedOrderLimit, _ storj.PiecePrivateKey, cachedIPsAndPorts map[storj.NodeID]string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return service.createGetRepairOrderLimits(ctx, metabase.BucketLocation{}, segment, pieces)
+}
+
+// createGetRepairOrderLimits signs the GET_REPAIR order limits for the healthy
+// pieces of segment without allocating their bandwidth.
+func (service *Service) createGetRepairOrderLimits(ctx context.Context, bucket metabase.BucketLocation, segment metabase.Segment, healthy metabase.Pieces) (_ []*pb.AddressedOrderLimit, _ storj.PiecePrivateKey, cachedIPsAndPorts map[storj.NodeID]string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	outcome := orderLimitsOutcome{bucket: bucket, action: pb.PieceAction_GET_REPAIR, requested: len(healthy)}
+	defer func() { service.logOrderLimits(outcome, err) }()
+
 	redundancy, err := eestream.NewRedundancyStrategyFromStorj(segment.Redundancy)
 	if err != nil {
 		return nil, storj.PiecePrivateKey{}, nil, Error.Wrap(err)
@@ -345,6 +646,7 @@ func (service *Service) CreateGetRepairOrderLimits(ctx context.Context, bucket m
 		service.log.Debug("error getting nodes from overlay", zap.Error(err))
 		return nil, storj.PiecePrivateKey{}, nil, Error.Wrap(err)
 	}
+	outcome.available = len(nodes)
 
 	signer, err := NewSignerRepairGet(service, segment.RootPieceID, time.Now(), pieceSize, bucket)
 	if err != nil {
@@ -362,8 +664,8 @@ func (service *Service) CreateGetRepairOrderLimits(ctx context.Context, bucket m
 			continue
 		}
 
-		if node.LastIPPort != "" {
-			cachedIPsAndPorts[piece.StorageNode] = node.LastIPPort
+		if lastIPPort := validLastIPPort(node.LastIPPort); lastIPPort != "" {
+			cachedIPsAndPorts[piece.StorageNode] = lastIPPort
 		}
 
 		limit, err := signer.Sign(ctx, storj.NodeURL{
@@ -376,6 +678,7 @@ func (service *Service) CreateGetRepairOrderLimits(ctx context.Context, bucket m
 
 		limits[piece.Number] = limit
 		limitsCount++
+		outcome.signed++
 	}
 
 	if limitsCount < redundancy.RequiredCount() {
@@ -383,10 +686,6 @@ func (service *Service) CreateGetRepairOrderLimits(ctx context.Context, bucket m
 		return nil, storj.PiecePrivateKey{}, nil, errs.Combine(err, nodeErrors.Err())
 	}
 
-	if err := service.updateBandwidth(ctx, bucket, limits...); err != nil {
-		return nil, storj.PiecePrivateKey{}, nil, Error.Wrap(err)
-	}
-
 	return limits, signer.PrivateKey, cachedIPsAndPorts, nil
 }
 
@@ -394,6 +693,9 @@ func (service *Service) CreateGetRepairOrderLimits(ctx context.Context, bucket m
 func (service *Service) CreatePutRepairOrderLimits(ctx context.Context, bucket metabase.BucketLocation, segment metabase.Segment, getOrderLimits []*pb.AddressedOrderLimit, newNodes []*overlay.SelectedNode, optimalThresholdMultiplier float64) (_ []*pb.AddressedOrderLimit, _ storj.PiecePrivateKey, err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	outcome := orderLimitsOutcome{bucket: bucket, action: pb.PieceAction_PUT_REPAIR, available: len(newNodes)}
+	defer func() { service.logOrderLimits(outcome, err) }()
+
 	// Create the order limits for being used to upload the repaired pieces
 	redundancy, err := eestream.NewRedundancyStrategyFromStorj(segment.Redundancy)
 	if err != nil {
@@ -415,6 +717,7 @@ func (service *Service) CreatePutRepairOrderLimits(ctx context.Context, bucket m
 	}
 
 	totalPiecesToRepair := totalPiecesAfterRepair - numCurrentPieces
+	outcome.requested = totalPiecesToRepair
 
 	limits := make([]*pb.AddressedOrderLimit, totalPieces)
 
@@ -449,6 +752,7 @@ func (service *Service) CreatePutRepairOrderLimits(ctx context.Context, bucket m
 		limits[pieceNum] = limit
 		pieceNum++
 		totalPiecesToRepair--
+		outcome.signed++
 
 		if totalPiecesToRepair == 0 {
 			break
@@ -466,6 +770,9 @@ func (service *Service) CreatePutRepairOrderLimits(ctx context.Context, bucket m
 func (service *Service) CreateGracefulExitPutOrderLimit(ctx context.Context, bucket metabase.BucketLocation, nodeID storj.NodeID, pieceNum int32, rootPieceID storj.PieceID, shareSize int32) (limit *pb.AddressedOrderLimit, _ storj.PiecePrivateKey, err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	outcome := orderLimitsOutcome{bucket: bucket, action: pb.PieceAction_PUT_REPAIR, requested: 1}
+	defer func() { service.logOrderLimits(outcome, err) }()
+
 	// should this use KnownReliable or similar?
 	node, err := service.overlay.Get(ctx, nodeID)
 	if err != nil {
@@ -477,21 +784,19 @@ func (service *Service) CreateGracefulExitPutOrderLimit(ctx context.Context, buc
 	if !service.overlay.IsOnline(node) {
 		return nil, storj.PiecePrivateKey{}, overlay.ErrNodeOffline.New("%v", nodeID)
 	}
+	outcome.available = 1
 
 	signer, err := NewSignerGracefulExit(service, rootPieceID, time.Now(), shareSize, bucket)
 	if err != nil {
 		return nil, storj.PiecePrivateKey{}, Error.Wrap(err)
 	}
 
-	address := node.Address.Address
-	if node.LastIPPort != "" {
-		address = node.LastIPPort
-	}
-	nodeURL := storj.NodeURL{ID: nodeID, Address: address}
+	nodeURL := storj.NodeURL{ID: nodeID, Address: nodeAddress(node.Address.Address, node.LastIPPort)}
 	limit, err = signer.Sign(ctx, nodeURL, pieceNum)
 	if err != nil {
 		return nil, storj.PiecePrivateKey{}, Error.Wrap(err)
 	}
+	outcome.signed = 1
 
 	if err := service.updateBandwidth(ctx, bucket, limit); err != nil {
 		return nil, storj.PiecePrivateKey{}, Error.Wrap(err)
@@ -4,15 +4,27 @@
 package orders_test
 
 import (
+	"context"
+	"net"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
 
 	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/signing"
+	"storj.io/common/storj"
 	"storj.io/common/testcontext"
 	"storj.io/common/testrand"
 	"storj.io/storj/private/testplanet"
 	"storj.io/storj/satellite/metabase"
+	"storj.io/storj/satellite/orders"
+	"storj.io/storj/satellite/overlay"
 )
 
 func TestOrderLimitsEncryptedMetadata(t *testing.T) {
@@ -60,3 +72,361 @@ func TestOrderLimitsEncryptedMetadata(t *testing.T) {
 		require.Equal(t, projectID, actualBucketInfo.ProjectID)
 	})
 }
+
+func TestOrderLimitsIPv6LastIPPort(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellitePeer := planet.Satellites[0]
+
+		// a node listening on an IPv6 address.
+		listener, err := net.Listen("tcp", "[::1]:0")
+		if err != nil {
+			t.Skip("IPv6 is not available:", err)
+		}
+		defer ctx.Check(listener.Close)
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				_ = conn.Close()
+			}
+		}()
+		lastIPPort := listener.Addr().String()
+		require.True(t, strings.HasPrefix(lastIPPort, "["))
+
+		const advertised = "node.example.test:28967"
+		nodes := []*overlay.SelectedNode{
+			{
+				ID:         testrand.NodeID(),
+				Address:    &pb.NodeAddress{Address: advertised},
+				LastIPPort: lastIPPort,
+			},
+			{
+				// an IPv6 address without brackets can't be dialed.
+				ID:         testrand.NodeID(),
+				Address:    &pb.NodeAddress{Address: advertised},
+				LastIPPort: "2001:db8::1:28967",
+			},
+		}
+
+		bucket := metabase.BucketLocation{ProjectID: planet.Uplinks[0].Projects[0].ID, BucketName: "testbucket"}
+		_, limits, _, err := satellitePeer.Orders.Service.CreatePutOrderLimits(ctx, bucket, nodes, time.Time{}, 1*memory.KiB.Int64())
+		require.NoError(t, err)
+		require.Len(t, limits, 2)
+
+		require.Equal(t, lastIPPort, limits[0].StorageNodeAddress.Address)
+		require.Equal(t, advertised, limits[1].StorageNodeAddress.Address)
+
+		conn, err := net.Dial("tcp", limits[0].StorageNodeAddress.Address)
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+	})
+}
+
+func TestEstimateGetEgress(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		const (
+			bucketName = "testbucket"
+			filePath   = "test/path"
+		)
+
+		var (
+			satellitePeer = planet.Satellites[0]
+			uplinkPeer    = planet.Uplinks[0]
+			projectID     = uplinkPeer.Projects[0].ID
+		)
+
+		require.NoError(t, uplinkPeer.Upload(ctx, satellitePeer, bucketName, filePath, testrand.Bytes(10*memory.KiB)))
+
+		bucket := metabase.BucketLocation{ProjectID: projectID, BucketName: bucketName}
+
+		segments, err := satellitePeer.Metainfo.Metabase.TestingAllSegments(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(segments))
+
+		since := time.Now().Add(-time.Hour)
+		projectAccounting := satellitePeer.DB.ProjectAccounting()
+
+		for _, overrideLimit := range []int64{0, 100} {
+			estimate, err := satellitePeer.Orders.Service.EstimateGetEgress(ctx, segments[0], overrideLimit)
+			require.NoError(t, err)
+			require.NotZero(t, estimate)
+
+			before, err := projectAccounting.GetAllocatedBandwidthTotal(ctx, projectID, since)
+			require.NoError(t, err)
+
+			limits, _, err := satellitePeer.Orders.Service.CreateGetOrderLimits(ctx, bucket, segments[0], overrideLimit)
+			require.NoError(t, err)
+
+			after, err := projectAccounting.GetAllocatedBandwidthTotal(ctx, projectID, since)
+			require.NoError(t, err)
+
+			var limitsTotal int64
+			for _, limit := range limits {
+				limitsTotal += limit.Limit.Limit
+			}
+
+			require.Equal(t, limitsTotal, estimate)
+			require.Equal(t, after-before, estimate)
+		}
+	})
+}
+
+func TestDownloadNodesConfig(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 6, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: testplanet.ReconfigureRS(2, 3, 4, 6),
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		const (
+			bucketName = "testbucket"
+			filePath   = "test/path"
+		)
+
+		var (
+			satellitePeer = planet.Satellites[0]
+			uplinkPeer    = planet.Uplinks[0]
+			projectID     = uplinkPeer.Projects[0].ID
+		)
+
+		require.NoError(t, uplinkPeer.Upload(ctx, satellitePeer, bucketName, filePath, testrand.Bytes(10*memory.KiB)))
+
+		bucket := metabase.BucketLocation{ProjectID: projectID, BucketName: bucketName}
+
+		segments, err := satellitePeer.Metainfo.Metabase.TestingAllSegments(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(segments))
+		segment := segments[0]
+
+		minimum := int(segment.Redundancy.DownloadNodes())
+		require.Equal(t, 3, minimum)
+
+		for _, tt := range []struct {
+			multiplier float64
+			extra      int
+			expected   int
+		}{
+			{multiplier: 0, extra: 0, expected: 3},
+			{multiplier: 1, extra: 0, expected: 3},
+			{multiplier: 0.5, extra: 0, expected: 3},
+			{multiplier: 1, extra: 1, expected: 4},
+			{multiplier: 1.5, extra: 0, expected: 5},
+			{multiplier: 2, extra: 0, expected: 6},
+			{multiplier: 2, extra: 10, expected: 16},
+		} {
+			config := satellitePeer.Config.Orders
+			config.DownloadNodesMultiplier = tt.multiplier
+			config.DownloadNodesExtra = tt.extra
+
+			service, err := orders.NewService(
+				zaptest.NewLogger(t),
+				signing.SignerFromFullIdentity(satellitePeer.Identity),
+				satellitePeer.Overlay.Service,
+				satellitePeer.Orders.DB,
+				satellitePeer.DB.Buckets(),
+				config,
+			)
+			require.NoError(t, err)
+
+			expected := tt.expected
+			if expected > len(segment.Pieces) {
+				expected = len(segment.Pieces)
+			}
+
+			limits, _, err := service.CreateGetOrderLimits(ctx, bucket, segment, 0)
+			require.NoError(t, err)
+			require.Len(t, limits, expected, "multiplier %v, extra %d", tt.multiplier, tt.extra)
+
+			estimate, err := service.EstimateGetEgress(ctx, segment, 0)
+			require.NoError(t, err)
+			require.Equal(t, int64(expected)*limits[0].Limit.Limit, estimate)
+		}
+	})
+}
+
+func TestOrderLimitsLogging(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		const (
+			bucketName = "testbucket"
+			filePath   = "test/path"
+		)
+
+		var (
+			satellitePeer = planet.Satellites[0]
+			uplinkPeer    = planet.Uplinks[0]
+			projectID     = uplinkPeer.Projects[0].ID
+		)
+
+		require.NoError(t, uplinkPeer.Upload(ctx, satellitePeer, bucketName, filePath, testrand.Bytes(5*memory.KiB)))
+
+		bucket := metabase.BucketLocation{ProjectID: projectID, BucketName: bucketName}
+
+		segments, err := satellitePeer.Metainfo.Metabase.TestingAllSegments(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(segments))
+
+		core, logs := observer.New(zap.DebugLevel)
+		service, err := orders.NewService(
+			zap.New(core),
+			signing.SignerFromFullIdentity(satellitePeer.Identity),
+			satellitePeer.Overlay.Service,
+			satellitePeer.Orders.DB,
+			satellitePeer.DB.Buckets(),
+			satellitePeer.Config.Orders,
+		)
+		require.NoError(t, err)
+
+		requireFields := func(t *testing.T, entry observer.LoggedEntry, requested, available, signed int64) {
+			fields := entry.ContextMap()
+			require.Equal(t, projectID.String(), fields["Project ID"])
+			require.Equal(t, bucketName, fields["Bucket"])
+			require.Equal(t, pb.PieceAction_GET.String(), fields["Action"])
+			require.Equal(t, requested, fields["Requested"])
+			require.Equal(t, available, fields["Available"])
+			require.Equal(t, signed, fields["Signed"])
+		}
+
+		segment := segments[0]
+		limits, _, err := service.CreateGetOrderLimits(ctx, bucket, segment, 0)
+		require.NoError(t, err)
+
+		entries := logs.FilterMessage("created order limits").TakeAll()
+		require.Len(t, entries, 1)
+		requireFields(t, entries[0], int64(segment.Redundancy.DownloadNodes()), int64(len(segment.Pieces)), int64(len(limits)))
+		require.NotContains(t, entries[0].ContextMap(), "error")
+
+		// pieces on unknown nodes can't be downloaded
+		unknown := segment
+		unknown.Pieces = make(metabase.Pieces, len(segment.Pieces))
+		for i, piece := range segment.Pieces {
+			unknown.Pieces[i] = metabase.Piece{Number: piece.Number, StorageNode: testrand.NodeID()}
+		}
+
+		_, _, err = service.CreateGetOrderLimits(ctx, bucket, unknown, 0)
+		require.Error(t, err)
+		require.True(t, orders.ErrDownloadFailedNotEnoughPieces.Has(err))
+
+		entries = logs.FilterMessage("failed to create order limits").TakeAll()
+		require.Len(t, entries, 1)
+		requireFields(t, entries[0], int64(unknown.Redundancy.DownloadNodes()), 0, 0)
+		require.Contains(t, entries[0].ContextMap(), "error")
+	})
+}
+
+func TestCreateAuditPieceOrderLimits(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellitePeer := planet.Satellites[0]
+		uplinkPeer := planet.Uplinks[0]
+
+		require.NoError(t, uplinkPeer.Upload(ctx, satellitePeer, "testbucket", "test/path", testrand.Bytes(5*memory.KiB)))
+
+		segments, err := satellitePeer.Metainfo.Metabase.TestingAllSegments(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(segments))
+		segment := segments[0]
+
+		overlayDB := &countingOverlayDB{DB: satellitePeer.Overlay.DB}
+		overlayService, err := overlay.NewService(zaptest.NewLogger(t), overlayDB, satellitePeer.Config.Overlay)
+		require.NoError(t, err)
+
+		service, err := orders.NewService(
+			zaptest.NewLogger(t),
+			signing.SignerFromFullIdentity(satellitePeer.Identity),
+			overlayService,
+			satellitePeer.Orders.DB,
+			satellitePeer.DB.Buckets(),
+			satellitePeer.Config.Orders,
+		)
+		require.NoError(t, err)
+
+		shareSize := segment.Redundancy.ShareSize
+		pieces := make([]orders.AuditPiece, len(segment.Pieces))
+		for i, piece := range segment.Pieces {
+			pieces[i] = orders.AuditPiece{
+				NodeID:      piece.StorageNode,
+				RootPieceID: segment.RootPieceID,
+				PieceNum:    piece.Number,
+				ShareSize:   shareSize,
+			}
+		}
+		// a piece of another segment gets a limit of its own.
+		other := orders.AuditPiece{
+			NodeID:      segment.Pieces[0].StorageNode,
+			RootPieceID: testrand.PieceID(),
+			PieceNum:    segment.Pieces[0].Number,
+			ShareSize:   2 * shareSize,
+		}
+		pieces = append(pieces, other)
+
+		limits, err := service.CreateAuditPieceOrderLimits(ctx, pieces)
+		require.NoError(t, err)
+		require.Len(t, limits, len(pieces))
+		require.Equal(t, 1, overlayDB.getOnlineNodes)
+		require.Zero(t, overlayDB.get)
+
+		for i, limit := range limits {
+			require.NoError(t, limit.Err)
+			require.Equal(t, pieces[i], limit.Piece)
+			require.NotNil(t, limit.Limit)
+			require.False(t, limit.PrivateKey.IsZero())
+			require.Equal(t, pieces[i].NodeID, limit.Limit.Limit.StorageNodeId)
+			require.Equal(t, pb.PieceAction_GET_AUDIT, limit.Limit.Limit.Action)
+			require.Equal(t, int64(pieces[i].ShareSize), limit.Limit.Limit.Limit)
+			require.Equal(t, pieces[i].RootPieceID.Derive(pieces[i].NodeID, int32(pieces[i].PieceNum)), limit.Limit.Limit.PieceId)
+		}
+		// the pieces of the same segment share the key.
+		require.Equal(t, limits[0].PrivateKey, limits[1].PrivateKey)
+		require.NotEqual(t, limits[0].PrivateKey, limits[len(limits)-1].PrivateKey)
+
+		// only the pieces of unavailable nodes are looked up again
+		pieces = append([]orders.AuditPiece{{
+			NodeID:      testrand.NodeID(),
+			RootPieceID: segment.RootPieceID,
+			PieceNum:    100,
+			ShareSize:   shareSize,
+		}}, pieces...)
+
+		overlayDB.getOnlineNodes, overlayDB.get = 0, 0
+		limits, err = service.CreateAuditPieceOrderLimits(ctx, pieces)
+		require.NoError(t, err)
+		require.Len(t, limits, len(pieces))
+		require.Equal(t, 1, overlayDB.getOnlineNodes)
+		require.Equal(t, 1, overlayDB.get)
+
+		require.Error(t, limits[0].Err)
+		require.True(t, overlay.ErrNodeNotFound.Has(limits[0].Err))
+		require.Nil(t, limits[0].Limit)
+		for _, limit := range limits[1:] {
+			require.NoError(t, limit.Err)
+			require.NotNil(t, limit.Limit)
+		}
+	})
+}
+
+// countingOverlayDB counts the node lookups made through it.
+type countingOverlayDB struct {
+	overlay.DB
+
+	getOnlineNodes int
+	get            int
+}
+
+func (db *countingOverlayDB) GetOnlineNodesForGetDelete(ctx context.Context, nodeIDs []storj.NodeID, onlineWindow time.Duration) (map[storj.NodeID]*overlay.SelectedNode, error) {
+	db.getOnlineNodes++
+	return db.DB.GetOnlineNodesForGetDelete(ctx, nodeIDs, onlineWindow)
+}
+
+func (db *countingOverlayDB) Get(ctx context.Context, nodeID storj.NodeID) (*overlay.NodeDossier, error) {
+	db.get++
+	return db.DB.Get(ctx, nodeID)
+}
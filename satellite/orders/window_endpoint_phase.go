@@ -11,6 +11,9 @@ import (
 )
 
 // WindowEndpointRolloutPhase controls the phase of the new orders endpoint rollout.
+//
+// Phases are ordered, so a later phase compares greater than an earlier one,
+// e.g. phase >= WindowEndpointRolloutPhase2 holds for phase2 and phase3.
 type WindowEndpointRolloutPhase int
 
 const (
@@ -27,6 +30,26 @@ const (
 	WindowEndpointRolloutPhase3
 )
 
+// ParseWindowEndpointRolloutPhase parses the human readable form of a rollout
+// phase, e.g. "phase1".
+func ParseWindowEndpointRolloutPhase(s string) (WindowEndpointRolloutPhase, error) {
+	switch strings.ToLower(s) {
+	case "phase1":
+		return WindowEndpointRolloutPhase1, nil
+	case "phase2":
+		return WindowEndpointRolloutPhase2, nil
+	case "phase3":
+		return WindowEndpointRolloutPhase3, nil
+	default:
+		return 0, errs.New("invalid window endpoint rollout phase: %q (expected phase1, phase2 or phase3)", s)
+	}
+}
+
+// Valid returns whether the phase is a known rollout phase.
+func (phase WindowEndpointRolloutPhase) Valid() bool {
+	return phase >= WindowEndpointRolloutPhase1 && phase <= WindowEndpointRolloutPhase3
+}
+
 // String provides a human readable form of the rollout phase.
 func (phase WindowEndpointRolloutPhase) String() string {
 	switch phase {
@@ -43,16 +66,11 @@ func (phase WindowEndpointRolloutPhase) String() string {
 
 // Set implements flag.Value interface.
 func (phase *WindowEndpointRolloutPhase) Set(s string) error {
-	switch strings.ToLower(s) {
-	case "phase1":
-		*phase = WindowEndpointRolloutPhase1
-	case "phase2":
-		*phase = WindowEndpointRolloutPhase2
-	case "phase3":
-		*phase = WindowEndpointRolloutPhase3
-	default:
-		return errs.New("invalid window endpoint rollout phase: %q", s)
+	parsed, err := ParseWindowEndpointRolloutPhase(s)
+	if err != nil {
+		return err
 	}
+	*phase = parsed
 	return nil
 }
 
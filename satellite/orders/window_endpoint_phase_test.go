@@ -0,0 +1,52 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package orders_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/satellite/orders"
+)
+
+func TestParseWindowEndpointRolloutPhase(t *testing.T) {
+	for _, tt := range []struct {
+		input    string
+		expected orders.WindowEndpointRolloutPhase
+	}{
+		{input: "phase1", expected: orders.WindowEndpointRolloutPhase1},
+		{input: "phase2", expected: orders.WindowEndpointRolloutPhase2},
+		{input: "phase3", expected: orders.WindowEndpointRolloutPhase3},
+		{input: "PHASE2", expected: orders.WindowEndpointRolloutPhase2},
+	} {
+		phase, err := orders.ParseWindowEndpointRolloutPhase(tt.input)
+		require.NoError(t, err, tt.input)
+		require.Equal(t, tt.expected, phase)
+		require.True(t, phase.Valid())
+		require.Equal(t, tt.expected.String(), phase.String())
+
+		var flagValue orders.WindowEndpointRolloutPhase
+		require.NoError(t, flagValue.Set(tt.input))
+		require.Equal(t, tt.expected, flagValue)
+	}
+
+	for _, input := range []string{"", "phase0", "phase4", "phse2"} {
+		_, err := orders.ParseWindowEndpointRolloutPhase(input)
+		require.Error(t, err, input)
+		require.Contains(t, err.Error(), "invalid window endpoint rollout phase")
+
+		flagValue := orders.WindowEndpointRolloutPhase1
+		require.Error(t, flagValue.Set(input), input)
+		require.Equal(t, orders.WindowEndpointRolloutPhase1, flagValue)
+	}
+}
+
+func TestWindowEndpointRolloutPhaseValid(t *testing.T) {
+	require.False(t, orders.WindowEndpointRolloutPhase(0).Valid())
+	require.False(t, orders.WindowEndpointRolloutPhase(4).Valid())
+
+	require.True(t, orders.WindowEndpointRolloutPhase1 < orders.WindowEndpointRolloutPhase2)
+	require.True(t, orders.WindowEndpointRolloutPhase2 < orders.WindowEndpointRolloutPhase3)
+}
@@ -50,6 +50,9 @@ type DB interface {
 
 	// Get looks up the node by nodeID
 	Get(ctx context.Context, nodeID storj.NodeID) (*NodeDossier, error)
+	// GetNodes looks up the nodes by nodeIDs, only setting their address, disqualification,
+	// suspension, graceful exit and last contact fields. Unknown nodes are left out.
+	GetNodes(ctx context.Context, nodeIDs []storj.NodeID) (map[storj.NodeID]*NodeDossier, error)
 	// KnownOffline filters a set of nodes to offline nodes
 	KnownOffline(context.Context, *NodeCriteria, storj.NodeIDList) (storj.NodeIDList, error)
 	// KnownUnreliableOrOffline filters a set of nodes to unhealth or offlines node, independent of new
@@ -324,6 +327,15 @@ func (service *Service) Get(ctx context.Context, nodeID storj.NodeID) (_ *NodeDo
 	return service.db.Get(ctx, nodeID)
 }
 
+// GetNodes looks up the provided nodeIDs from the overlay. The returned
+// dossiers only contain the address, disqualification, suspension, graceful
+// exit and last contact of the nodes. Unknown nodes are left out.
+func (service *Service) GetNodes(ctx context.Context, nodeIDs []storj.NodeID) (_ map[storj.NodeID]*NodeDossier, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return service.db.GetNodes(ctx, nodeIDs)
+}
+
 // GetOnlineNodesForGetDelete returns a map of nodes for the supplied nodeIDs.
 func (service *Service) GetOnlineNodesForGetDelete(ctx context.Context, nodeIDs []storj.NodeID) (_ map[storj.NodeID]*SelectedNode, err error) {
 	defer mon.Task()(&ctx)(&err)
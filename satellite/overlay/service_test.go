@@ -380,6 +380,38 @@ func TestGetOnlineNodesForGetDelete(t *testing.T) {
 	})
 }
 
+func TestGetNodesByID(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 2, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		service := planet.Satellites[0].Overlay.Service
+
+		nodes, err := service.GetNodes(ctx, []storj.NodeID{})
+		require.NoError(t, err)
+		require.Len(t, nodes, 0)
+
+		disqualified := planet.StorageNodes[0].ID()
+		err = service.DisqualifyNode(ctx, disqualified)
+		require.NoError(t, err)
+
+		nodeIDs := []storj.NodeID{planet.StorageNodes[0].ID(), planet.StorageNodes[1].ID(), testrand.NodeID()}
+		nodes, err = service.GetNodes(ctx, nodeIDs)
+		require.NoError(t, err)
+		require.Len(t, nodes, 2)
+
+		for _, node := range planet.StorageNodes {
+			dossier, err := service.Get(ctx, node.ID())
+			require.NoError(t, err)
+
+			require.Contains(t, nodes, node.ID())
+			require.Equal(t, dossier.Address.Address, nodes[node.ID()].Address.Address)
+			require.Equal(t, dossier.Disqualified != nil, nodes[node.ID()].Disqualified != nil)
+			require.Equal(t, service.IsOnline(dossier), service.IsOnline(nodes[node.ID()]))
+		}
+		require.NotNil(t, nodes[disqualified].Disqualified)
+	})
+}
+
 func TestKnownReliable(t *testing.T) {
 	testplanet.Run(t, testplanet.Config{
 		SatelliteCount: 1, StorageNodeCount: 6, UplinkCount: 1,
@@ -15,6 +15,9 @@ import (
 // ErrAccountNotSetup is an error type which indicates that payment account is not created.
 var ErrAccountNotSetup = errs.Class("payment account is not set up")
 
+// ErrUnavailable is an error type which indicates that the billing backend couldn't be reached.
+var ErrUnavailable = errs.Class("billing backend unavailable")
+
 // Accounts exposes all needed functionality to manage payment accounts.
 //
 // architecture: Service
@@ -27,6 +30,9 @@ type Accounts interface {
 	Balance(ctx context.Context, userID uuid.UUID) (Balance, error)
 
 	// ProjectCharges returns how much money current user will be charged for each project.
+	// It returns an empty slice when there are no charges. The charges are calculated
+	// from the usage in the satellite database, so ErrUnavailable is only returned by
+	// implementations which depend on the billing backend.
 	ProjectCharges(ctx context.Context, userID uuid.UUID, since, before time.Time) ([]ProjectCharge, error)
 
 	// CheckProjectInvoicingStatus returns true if for the given project there are outstanding project records and/or usage
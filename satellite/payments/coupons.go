@@ -7,10 +7,15 @@ import (
 	"context"
 	"time"
 
+	"github.com/zeebo/errs"
+
 	"storj.io/common/memory"
 	"storj.io/common/uuid"
 )
 
+// ErrCouponNotFound is returned when a coupon doesn't exist or doesn't belong to the user.
+var ErrCouponNotFound = errs.Class("coupon not found")
+
 // Coupons exposes all needed functionality to manage coupons.
 //
 // architecture: Service
@@ -27,6 +32,9 @@ type Coupons interface {
 	// Create attaches a coupon for payment account.
 	Create(ctx context.Context, coupon CouponOld) (coup CouponOld, err error)
 
+	// Revoke expires the coupon of the user, so that it's no longer applied.
+	Revoke(ctx context.Context, userID, couponID uuid.UUID) error
+
 	// AddPromotionalCoupon is used to add a promotional coupon for specified users who already have
 	// a project and do not have a promotional coupon yet.
 	// And updates project limits to selected size.
@@ -37,6 +45,10 @@ type Coupons interface {
 	// And updates project limits to selected size.
 	PopulatePromotionalCoupons(ctx context.Context, duration *int, amount int64, projectLimit memory.Size) error
 
+	// PreviewPopulatePromotionalCoupons returns the users which would get a promotional coupon
+	// by PopulatePromotionalCoupons, without changing anything.
+	PreviewPopulatePromotionalCoupons(ctx context.Context) ([]uuid.UUID, error)
+
 	// ApplyCouponCode attempts to apply a coupon code to the user.
 	ApplyCouponCode(ctx context.Context, userID uuid.UUID, couponCode string) (*Coupon, error)
 }
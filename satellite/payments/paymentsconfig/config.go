@@ -4,7 +4,12 @@
 package paymentsconfig
 
 import (
+	"sort"
 	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/zeebo/errs"
 
 	"storj.io/common/memory"
 	"storj.io/storj/satellite/payments/stripecoinpayments"
@@ -14,18 +19,19 @@ import (
 type Config struct {
 	Provider                 string `help:"payments provider to use" default:""`
 	StripeCoinPayments       stripecoinpayments.Config
-	StorageTBPrice           string         `help:"price user should pay for storing TB per month" default:"4" testDefault:"10"`
-	EgressTBPrice            string         `help:"price user should pay for each TB of egress" default:"7" testDefault:"45"`
-	ObjectPrice              string         `help:"price user should pay for each object stored in network per month" default:"0" testDefault:"0.0000022"`
-	BonusRate                int64          `help:"amount of percents that user will earn as bonus credits by depositing in STORJ tokens" default:"10"`
-	CouponValue              int64          `help:"coupon value in cents" default:"165" testDefault:"275"`
-	CouponDuration           CouponDuration `help:"duration a new coupon is valid in months/billing cycles. An empty string means the coupon never expires" default:"1" testDefault:"2"`
-	CouponProjectLimit       memory.Size    `help:"project limit to which increase to after applying the coupon, 0 B means not changing it from the default" default:"0 B"`
-	MinCoinPayment           int64          `help:"minimum value of coin payments in cents before coupon is applied" default:"1000"`
-	NodeEgressBandwidthPrice int64          `help:"price node receive for storing TB of egress in cents" default:"2000"`
-	NodeRepairBandwidthPrice int64          `help:"price node receive for storing TB of repair in cents" default:"1000"`
-	NodeAuditBandwidthPrice  int64          `help:"price node receive for storing TB of audit in cents" default:"1000"`
-	NodeDiskSpacePrice       int64          `help:"price node receive for storing disk space in cents/TB" default:"150"`
+	StorageTBPrice           string          `help:"price user should pay for storing TB per month" default:"4" testDefault:"10"`
+	EgressTBPrice            string          `help:"price user should pay for each TB of egress" default:"7" testDefault:"45"`
+	ObjectPrice              string          `help:"price user should pay for each object stored in network per month" default:"0" testDefault:"0.0000022"`
+	RegionalPricing          RegionalPricing `help:"comma separated pricing shown in the web interface instead of the default one for clients of a region, in the format region:storage-tb-price/egress-tb-price/object-price; it is display only, invoices are always billed with the default pricing" default:""`
+	BonusRate                int64           `help:"amount of percents that user will earn as bonus credits by depositing in STORJ tokens" default:"10"`
+	CouponValue              int64           `help:"coupon value in cents" default:"165" testDefault:"275"`
+	CouponDuration           CouponDuration  `help:"duration a new coupon is valid in months/billing cycles. An empty string means the coupon never expires" default:"1" testDefault:"2"`
+	CouponProjectLimit       memory.Size     `help:"project limit to which increase to after applying the coupon, 0 B means not changing it from the default" default:"0 B"`
+	MinCoinPayment           int64           `help:"minimum value of coin payments in cents before coupon is applied" default:"1000"`
+	NodeEgressBandwidthPrice int64           `help:"price node receive for storing TB of egress in cents" default:"2000"`
+	NodeRepairBandwidthPrice int64           `help:"price node receive for storing TB of repair in cents" default:"1000"`
+	NodeAuditBandwidthPrice  int64           `help:"price node receive for storing TB of audit in cents" default:"1000"`
+	NodeDiskSpacePrice       int64           `help:"price node receive for storing disk space in cents/TB" default:"150"`
 }
 
 // CouponDuration is a configuration struct that keeps details about default
@@ -42,6 +48,86 @@ type PricingValues struct {
 	StorageTBPrice string
 	EgressTBPrice  string
 	ObjectPrice    string
+
+	// Regions contains the pricing which is shown instead of the default one
+	// to the clients of a region. It only affects what is displayed, invoices
+	// are billed with the default pricing.
+	Regions RegionalPricing
+}
+
+// ForRegion returns the pricing of the region and whether the region has its
+// own pricing. The default pricing is returned when it hasn't.
+func (pricing PricingValues) ForRegion(region string) (_ PricingValues, ok bool) {
+	regional, ok := pricing.Regions[strings.ToLower(region)]
+	if !ok {
+		return pricing, false
+	}
+	return regional, true
+}
+
+// RegionalPricing is a configuration value that contains the pricing
+// displayed to the clients of regions, keyed by lowercase region name.
+// Format should be region:storage-tb-price/egress-tb-price/object-price,...
+//
+// Can be used as a flag.
+type RegionalPricing map[string]PricingValues
+
+// Type implements pflag.Value.
+func (RegionalPricing) Type() string { return "paymentsconfig.RegionalPricing" }
+
+// String is required for pflag.Value.
+func (rp RegionalPricing) String() string {
+	regions := make([]string, 0, len(rp))
+	for region := range rp {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	values := make([]string, 0, len(regions))
+	for _, region := range regions {
+		pricing := rp[region]
+		values = append(values, region+":"+pricing.StorageTBPrice+"/"+pricing.EgressTBPrice+"/"+pricing.ObjectPrice)
+	}
+	return strings.Join(values, ",")
+}
+
+// Set parses and validates the pricing of the regions.
+func (rp *RegionalPricing) Set(s string) error {
+	regional := RegionalPricing{}
+	for _, value := range strings.Split(s, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		info := strings.SplitN(value, ":", 2)
+		region := strings.ToLower(strings.TrimSpace(info[0]))
+		if len(info) != 2 || region == "" {
+			return errs.New("Invalid regional pricing (expect format region:storage/egress/object, got %q)", value)
+		}
+		if _, ok := regional[region]; ok {
+			return errs.New("Invalid regional pricing, region %q is specified more than once", region)
+		}
+
+		prices := strings.Split(info[1], "/")
+		if len(prices) != 3 {
+			return errs.New("Invalid regional pricing (expect format region:storage/egress/object, got %q)", value)
+		}
+		for _, price := range prices {
+			if _, err := decimal.NewFromString(price); err != nil {
+				return errs.New("Invalid regional pricing of region %q: %v", region, err)
+			}
+		}
+
+		regional[region] = PricingValues{
+			StorageTBPrice: prices[0],
+			EgressTBPrice:  prices[1],
+			ObjectPrice:    prices[2],
+		}
+	}
+
+	*rp = regional
+	return nil
 }
 
 // Type implements pflag.Value.
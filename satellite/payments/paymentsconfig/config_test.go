@@ -0,0 +1,48 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package paymentsconfig_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/satellite/payments/paymentsconfig"
+)
+
+func TestRegionalPricing(t *testing.T) {
+	var pricing paymentsconfig.RegionalPricing
+	require.NoError(t, pricing.Set(""))
+	require.Empty(t, pricing)
+
+	require.NoError(t, pricing.Set("EU:12/50/0.0000025, ap:15/60/0"))
+	require.Equal(t, paymentsconfig.RegionalPricing{
+		"eu": {StorageTBPrice: "12", EgressTBPrice: "50", ObjectPrice: "0.0000025"},
+		"ap": {StorageTBPrice: "15", EgressTBPrice: "60", ObjectPrice: "0"},
+	}, pricing)
+	require.Equal(t, "ap:15/60/0,eu:12/50/0.0000025", pricing.String())
+
+	require.Error(t, pricing.Set("eu"))
+	require.Error(t, pricing.Set(":1/2/3"))
+	require.Error(t, pricing.Set("eu:1/2"))
+	require.Error(t, pricing.Set("eu:1/2/x"))
+	require.Error(t, pricing.Set("eu:1/2/3,EU:4/5/6"))
+}
+
+func TestPricingValuesForRegion(t *testing.T) {
+	pricing := paymentsconfig.PricingValues{
+		StorageTBPrice: "10",
+		EgressTBPrice:  "45",
+		ObjectPrice:    "0.0000022",
+	}
+	require.NoError(t, pricing.Regions.Set("eu:12/50/0.0000025"))
+
+	regional, ok := pricing.ForRegion("EU")
+	require.True(t, ok)
+	require.Equal(t, "12", regional.StorageTBPrice)
+
+	regional, ok = pricing.ForRegion("us")
+	require.False(t, ok)
+	require.Equal(t, pricing, regional)
+}
@@ -5,6 +5,8 @@ package stripecoinpayments
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/stripe/stripe-go/v72"
@@ -39,6 +41,9 @@ type CouponsDB interface {
 	AddUsage(ctx context.Context, usage CouponUsage) error
 	// TotalUsage gets sum of all usage records for specified coupon.
 	TotalUsage(ctx context.Context, couponID uuid.UUID) (int64, error)
+	// UsageSummary returns the value of coupons created and the coupon usage
+	// charged for periods in [since, before), grouped by coupon type.
+	UsageSummary(ctx context.Context, since, before time.Time) ([]CouponTypeUsage, error)
 	// GetLatest return period_end of latest coupon charge.
 	GetLatest(ctx context.Context, couponID uuid.UUID) (time.Time, error)
 	// ListUnapplied returns coupon usage page with unapplied coupon usages.
@@ -49,6 +54,9 @@ type CouponsDB interface {
 	// PopulatePromotionalCoupons is used to populate promotional coupons through all active users who already have a project
 	// and do not have a promotional coupon yet. And updates project limits to selected size.
 	PopulatePromotionalCoupons(ctx context.Context, users []uuid.UUID, duration *int, amount int64, projectLimit memory.Size) error
+	// PreviewPopulatePromotionalCoupons returns the users of the given ones which would get a promotional coupon
+	// by PopulatePromotionalCoupons, without changing anything.
+	PreviewPopulatePromotionalCoupons(ctx context.Context, users []uuid.UUID) ([]uuid.UUID, error)
 }
 
 // CouponUsage stores amount of money that should be charged from coupon for billing period.
@@ -59,6 +67,14 @@ type CouponUsage struct {
 	Period   time.Time
 }
 
+// CouponTypeUsage is the value of coupons of a type which were granted and
+// used within a time window. Amounts are in cents.
+type CouponTypeUsage struct {
+	Type    payments.CouponType
+	Granted int64
+	Used    int64
+}
+
 // CouponUsageStatus indicates the state of the coupon usage.
 type CouponUsageStatus int
 
@@ -97,6 +113,30 @@ func (coupons *coupons) Create(ctx context.Context, coupon payments.CouponOld) (
 	return coup, Error.Wrap(err)
 }
 
+// Revoke expires the coupon of the user, so that it's no longer applied to the
+// invoices of the user. Revoking an expired coupon does nothing.
+func (coupons *coupons) Revoke(ctx context.Context, userID, couponID uuid.UUID) (err error) {
+	defer mon.Task()(&ctx, userID, couponID)(&err)
+
+	coupon, err := coupons.service.db.Coupons().Get(ctx, couponID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return payments.ErrCouponNotFound.New("%s", couponID)
+		}
+		return Error.Wrap(err)
+	}
+	if coupon.UserID != userID {
+		return payments.ErrCouponNotFound.New("%s", couponID)
+	}
+
+	if coupon.Status == payments.CouponExpired {
+		return nil
+	}
+
+	_, err = coupons.service.db.Coupons().Update(ctx, couponID, payments.CouponExpired)
+	return Error.Wrap(err)
+}
+
 // ListByUserID return list of all coupons of specified payment account.
 func (coupons *coupons) ListByUserID(ctx context.Context, userID uuid.UUID) (_ []payments.CouponOld, err error) {
 	defer mon.Task()(&ctx, userID)(&err)
@@ -121,53 +161,47 @@ func (coupons *coupons) TotalUsage(ctx context.Context, couponID uuid.UUID) (_ i
 func (coupons *coupons) PopulatePromotionalCoupons(ctx context.Context, duration *int, amount int64, projectLimit memory.Size) (err error) {
 	defer mon.Task()(&ctx, duration, amount, projectLimit)(&err)
 
-	const limit = 50
-	before := time.Now()
+	return coupons.forEachUserWithPaymentMethod(ctx, func(usersIDs []uuid.UUID) error {
+		return Error.Wrap(coupons.service.db.Coupons().PopulatePromotionalCoupons(ctx, usersIDs, duration, amount, projectLimit))
+	})
+}
 
-	cusPage, err := coupons.service.db.Customers().List(ctx, 0, limit, before)
+// PreviewPopulatePromotionalCoupons returns the users which would get a promotional coupon
+// by PopulatePromotionalCoupons, without changing anything.
+func (coupons *coupons) PreviewPopulatePromotionalCoupons(ctx context.Context) (_ []uuid.UUID, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var eligible []uuid.UUID
+	err = coupons.forEachUserWithPaymentMethod(ctx, func(usersIDs []uuid.UUID) error {
+		ids, err := coupons.service.db.Coupons().PreviewPopulatePromotionalCoupons(ctx, usersIDs)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		eligible = append(eligible, ids...)
+		return nil
+	})
 	if err != nil {
-		return Error.Wrap(err)
+		return nil, err
 	}
 
-	// taking only users that attached a payment method.
-	var usersIDs []uuid.UUID
-	for _, cus := range cusPage.Customers {
-		params := &stripe.PaymentMethodListParams{
-			Type:     stripe.String(string(stripe.PaymentMethodTypeCard)),
-			Customer: stripe.String(cus.ID),
-		}
+	return eligible, nil
+}
 
-		paymentMethodsIterator := coupons.service.stripeClient.PaymentMethods().List(params)
-		for paymentMethodsIterator.Next() {
-			// if user has at least 1 payment method - break a loop.
-			usersIDs = append(usersIDs, cus.UserID)
-			break
-		}
+// forEachUserWithPaymentMethod calls fn with each page of the users which have a
+// customer that attached a payment method.
+func (coupons *coupons) forEachUserWithPaymentMethod(ctx context.Context, fn func(usersIDs []uuid.UUID) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
 
-		if err = paymentMethodsIterator.Err(); err != nil {
-			return Error.Wrap(err)
-		}
-	}
+	const limit = 50
+	before := time.Now()
 
-	err = coupons.service.db.Coupons().PopulatePromotionalCoupons(ctx, usersIDs, duration, amount, projectLimit)
+	cusPage, err := coupons.service.db.Customers().List(ctx, 0, limit, before)
 	if err != nil {
 		return Error.Wrap(err)
 	}
 
-	for cusPage.Next {
-		if err = ctx.Err(); err != nil {
-			return Error.Wrap(err)
-		}
-
-		cusPage, err = coupons.service.db.Customers().List(ctx, cusPage.NextOffset, limit, before)
-		if err != nil {
-			return Error.Wrap(err)
-		}
-
-		// we have to wait before each iteration because
-		// Stripe has rate limits - 100 read and 100 write operations per second per secret key.
-		time.Sleep(time.Second)
-
+	for {
+		// taking only users that attached a payment method.
 		var usersIDs []uuid.UUID
 		for _, cus := range cusPage.Customers {
 			params := &stripe.PaymentMethodListParams{
@@ -177,6 +211,7 @@ func (coupons *coupons) PopulatePromotionalCoupons(ctx context.Context, duration
 
 			paymentMethodsIterator := coupons.service.stripeClient.PaymentMethods().List(params)
 			for paymentMethodsIterator.Next() {
+				// if user has at least 1 payment method - break a loop.
 				usersIDs = append(usersIDs, cus.UserID)
 				break
 			}
@@ -186,13 +221,27 @@ func (coupons *coupons) PopulatePromotionalCoupons(ctx context.Context, duration
 			}
 		}
 
-		err = coupons.service.db.Coupons().PopulatePromotionalCoupons(ctx, usersIDs, duration, amount, projectLimit)
+		if err = fn(usersIDs); err != nil {
+			return err
+		}
+
+		if !cusPage.Next {
+			return nil
+		}
+
+		if err = ctx.Err(); err != nil {
+			return Error.Wrap(err)
+		}
+
+		cusPage, err = coupons.service.db.Customers().List(ctx, cusPage.NextOffset, limit, before)
 		if err != nil {
 			return Error.Wrap(err)
 		}
-	}
 
-	return nil
+		// we have to wait before each iteration because
+		// Stripe has rate limits - 100 read and 100 write operations per second per secret key.
+		time.Sleep(time.Second)
+	}
 }
 
 // AddPromotionalCoupon is used to add a promotional coupon for specified users who already have
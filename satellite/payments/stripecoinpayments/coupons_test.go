@@ -13,6 +13,7 @@ import (
 	"storj.io/common/testcontext"
 	"storj.io/common/testrand"
 	"storj.io/common/uuid"
+	"storj.io/storj/private/testplanet"
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/payments"
@@ -335,12 +336,21 @@ func TestPopulatePromotionalCoupons(t *testing.T) {
 				user6.ID,
 				user7.ID,
 			}
-			duration := 2
-			err := couponsRepo.PopulatePromotionalCoupons(ctx, usersIds, &duration, 5500, memory.TB)
+			preview, err := couponsRepo.PreviewPopulatePromotionalCoupons(ctx, usersIds)
 			require.NoError(t, err)
+			require.ElementsMatch(t, []uuid.UUID{user1.ID, user2.ID, user6.ID, user7.ID}, preview)
 
+			// preview must not change anything.
 			user1Coupons, err := couponsRepo.ListByUserID(ctx, user1.ID)
 			require.NoError(t, err)
+			require.Equal(t, 0, len(user1Coupons))
+
+			duration := 2
+			err = couponsRepo.PopulatePromotionalCoupons(ctx, usersIds, &duration, 5500, memory.TB)
+			require.NoError(t, err)
+
+			user1Coupons, err = couponsRepo.ListByUserID(ctx, user1.ID)
+			require.NoError(t, err)
 			require.Equal(t, 1, len(user1Coupons))
 
 			proj1Usage, err := usageRepo.GetProjectStorageLimit(ctx, proj1.ID)
@@ -395,8 +405,12 @@ func TestPopulatePromotionalCoupons(t *testing.T) {
 				user7.ID,
 				user8.ID,
 			}
+			preview, err := couponsRepo.PreviewPopulatePromotionalCoupons(ctx, usersIds)
+			require.NoError(t, err)
+			require.ElementsMatch(t, []uuid.UUID{user8.ID}, preview)
+
 			duration := 2
-			err := couponsRepo.PopulatePromotionalCoupons(ctx, usersIds, &duration, 5500, memory.TB)
+			err = couponsRepo.PopulatePromotionalCoupons(ctx, usersIds, &duration, 5500, memory.TB)
 			require.NoError(t, err)
 
 			user1Coupons, err := couponsRepo.ListByUserID(ctx, user1.ID)
@@ -450,3 +464,73 @@ func TestPopulatePromotionalCoupons(t *testing.T) {
 		})
 	})
 }
+
+func TestRevokeCoupon(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		couponsRepo := satellite.DB.StripeCoinPayments().Coupons()
+		service := satellite.API.Payments.Accounts.Coupons()
+
+		owner, err := satellite.AddUser(ctx, console.CreateUser{
+			FullName: "Coupon Owner",
+			Email:    "owner@mail.test",
+		}, 1)
+		require.NoError(t, err)
+
+		other, err := satellite.AddUser(ctx, console.CreateUser{
+			FullName: "Someone Else",
+			Email:    "other@mail.test",
+		}, 1)
+		require.NoError(t, err)
+
+		duration := 2
+		coupon, err := couponsRepo.Insert(ctx, payments.CouponOld{
+			UserID:      owner.ID,
+			Amount:      1000,
+			Duration:    &duration,
+			Description: "granted in error",
+			Type:        payments.CouponTypePromotional,
+			Status:      payments.CouponActive,
+		})
+		require.NoError(t, err)
+
+		t.Run("wrong user", func(t *testing.T) {
+			err := service.Revoke(ctx, other.ID, coupon.ID)
+			require.Error(t, err)
+			require.True(t, payments.ErrCouponNotFound.Has(err), err)
+
+			err = service.Revoke(ctx, owner.ID, testrand.UUID())
+			require.Error(t, err)
+			require.True(t, payments.ErrCouponNotFound.Has(err), err)
+
+			revoked, err := couponsRepo.Get(ctx, coupon.ID)
+			require.NoError(t, err)
+			require.Equal(t, payments.CouponActive, revoked.Status)
+		})
+
+		t.Run("revoke", func(t *testing.T) {
+			require.NoError(t, service.Revoke(ctx, owner.ID, coupon.ID))
+
+			revoked, err := couponsRepo.Get(ctx, coupon.ID)
+			require.NoError(t, err)
+			require.Equal(t, payments.CouponExpired, revoked.Status)
+
+			// only active coupons are applied to invoices
+			active, err := couponsRepo.ListByUserIDAndStatus(ctx, owner.ID, payments.CouponActive)
+			require.NoError(t, err)
+			for _, activeCoupon := range active {
+				require.NotEqual(t, coupon.ID, activeCoupon.ID)
+			}
+		})
+
+		t.Run("double revoke", func(t *testing.T) {
+			require.NoError(t, service.Revoke(ctx, owner.ID, coupon.ID))
+
+			revoked, err := couponsRepo.Get(ctx, coupon.ID)
+			require.NoError(t, err)
+			require.Equal(t, payments.CouponExpired, revoked.Status)
+		})
+	})
+}
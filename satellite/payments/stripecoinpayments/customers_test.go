@@ -4,6 +4,7 @@
 package stripecoinpayments_test
 
 import (
+	"errors"
 	"strconv"
 	"testing"
 	"time"
@@ -41,6 +42,15 @@ func TestCustomersRepository(t *testing.T) {
 			assert.NoError(t, err)
 			assert.Equal(t, id, customerID)
 		})
+
+		t.Run("GetUserID", func(t *testing.T) {
+			id, err := customers.GetUserID(ctx, customerID)
+			assert.NoError(t, err)
+			assert.Equal(t, id, userID)
+
+			_, err = customers.GetUserID(ctx, "unknown")
+			assert.True(t, errors.Is(err, stripecoinpayments.ErrNoCustomer))
+		})
 	})
 }
 
@@ -43,6 +43,7 @@ const hoursPerMonth = 24 * 30
 type Config struct {
 	StripeSecretKey              string        `help:"stripe API secret key" default:""`
 	StripePublicKey              string        `help:"stripe API public key" default:""`
+	StripeWebhookSecret          string        `help:"stripe webhook endpoint signing secret" default:""`
 	StripeFreeTierCouponID       string        `help:"stripe free tier coupon ID" default:""`
 	CoinpaymentsPublicKey        string        `help:"coinpayments API public key" default:""`
 	CoinpaymentsPrivateKey       string        `help:"coinpayments API private key key" default:""`
@@ -84,6 +85,8 @@ type Service struct {
 	rates    coinpayments.CurrencyRateInfos
 	ratesErr error
 
+	webhookSecret string
+
 	listingLimit int
 	nowFn        func() time.Time
 }
@@ -133,6 +136,7 @@ func NewService(log *zap.Logger, stripeClient StripeClient, config Config, db DB
 		CouponProjectLimit:       couponProjectLimit,
 		MinCoinPayment:           minCoinPayment,
 		AutoAdvance:              config.AutoAdvance,
+		webhookSecret:            config.StripeWebhookSecret,
 		listingLimit:             config.ListingLimit,
 		nowFn:                    time.Now,
 	}, nil
@@ -143,6 +147,12 @@ func (service *Service) Accounts() payments.Accounts {
 	return &accounts{service: service}
 }
 
+// Webhooks exposes the handling of events sent by stripe,
+// handler is notified about the events that change the state of a user.
+func (service *Service) Webhooks(handler payments.WebhookHandler) payments.Webhooks {
+	return &webhooks{service: service, handler: handler}
+}
+
 // updateTransactionsLoop updates all pending transactions in a loop.
 func (service *Service) updateTransactionsLoop(ctx context.Context) (err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -200,6 +210,7 @@ func (service *Service) updateTransactions(ctx context.Context, ids TransactionA
 				TransactionID: id,
 				Status:        info.Status,
 				Received:      info.Received,
+				Confirmations: info.ConfirmsReceived,
 			},
 		)
 
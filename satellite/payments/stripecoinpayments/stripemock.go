@@ -87,7 +87,7 @@ func NewStripeMock(id storj.NodeID, customersDB CustomersDB, usersDB console.Use
 			customers:                   &mockCustomersState{},
 			paymentMethods:              newMockPaymentMethods(),
 			invoices:                    &mockInvoices{},
-			invoiceItems:                &mockInvoiceItems{},
+			invoiceItems:                newMockInvoiceItems(),
 			customerBalanceTransactions: newMockCustomerBalanceTransactions(),
 			charges:                     &mockCharges{},
 			promoCodes: &mockPromoCodes{
@@ -402,14 +402,64 @@ func (m *mockInvoices) FinalizeInvoice(id string, params *stripe.InvoiceFinalize
 }
 
 type mockInvoiceItems struct {
+	items map[string][]*stripe.InvoiceItem
+}
+
+func newMockInvoiceItems() *mockInvoiceItems {
+	return &mockInvoiceItems{
+		items: make(map[string][]*stripe.InvoiceItem),
+	}
 }
 
 func (m *mockInvoiceItems) New(params *stripe.InvoiceItemParams) (*stripe.InvoiceItem, error) {
-	return nil, nil
+	item := &stripe.InvoiceItem{
+		ID:       fmt.Sprintf("ii_%s", testrand.BucketName()),
+		Customer: &stripe.Customer{ID: *params.Customer},
+	}
+	if params.Amount != nil {
+		item.Amount = *params.Amount
+	}
+	if params.Currency != nil {
+		item.Currency = stripe.Currency(*params.Currency)
+	}
+	if params.Description != nil {
+		item.Description = *params.Description
+	}
+
+	mocks.Lock()
+	defer mocks.Unlock()
+
+	m.items[*params.Customer] = append(m.items[*params.Customer], item)
+
+	return item, nil
 }
 
 func (m *mockInvoiceItems) List(listParams *stripe.InvoiceItemListParams) *invoiceitem.Iter {
-	return &invoiceitem.Iter{Iter: stripe.GetIter(listParams, mockEmptyQuery)}
+	mocks.Lock()
+	defer mocks.Unlock()
+
+	query := stripe.Query(func(p *stripe.Params, b *form.Values) ([]interface{}, stripe.ListContainer, error) {
+		// the items aren't added to invoices by the mock, so they are all pending.
+		var items []*stripe.InvoiceItem
+		if listParams.Customer != nil {
+			items = m.items[*listParams.Customer]
+		}
+		ret := make([]interface{}, len(items))
+
+		for i, v := range items {
+			ret[i] = v
+		}
+
+		listMeta := &stripe.ListMeta{
+			TotalCount: uint32(len(items)),
+		}
+
+		lc := newListContainer(listMeta)
+
+		return ret, lc, nil
+	})
+
+	return &invoiceitem.Iter{Iter: stripe.GetIter(listParams, query)}
 }
 
 type mockCustomerBalanceTransactions struct {
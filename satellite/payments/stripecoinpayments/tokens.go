@@ -126,43 +126,78 @@ func (tokens *storjTokens) ListTransactionInfos(ctx context.Context, userID uuid
 
 	var infos []payments.TransactionInfo
 	for _, tx := range txs {
-		link := coinpayments.GetCheckoutURL(tx.Key, tx.ID)
-
-		var status payments.TransactionStatus
-		switch tx.Status {
-		case coinpayments.StatusPending:
-			status = payments.TransactionStatusPending
-		case coinpayments.StatusReceived:
-			status = payments.TransactionStatusPaid
-		case coinpayments.StatusCancelled:
-			status = payments.TransactionStatusCancelled
-		default:
-			// unknown
-			status = payments.TransactionStatus(tx.Status.String())
+		info, err := tokens.transactionInfo(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// GetTransactionInfo fetches the transaction with the given id of the specified user from the database.
+func (tokens *storjTokens) GetTransactionInfo(ctx context.Context, userID uuid.UUID, id payments.TransactionID) (_ *payments.TransactionInfo, err error) {
+	defer mon.Task()(&ctx, userID)(&err)
+
+	txs, err := tokens.service.db.Transactions().ListAccount(ctx, userID)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	for _, tx := range txs {
+		if tx.ID.String() != id.String() {
+			continue
 		}
 
-		rate, err := tokens.service.db.Transactions().GetLockedRate(ctx, tx.ID)
+		info, err := tokens.transactionInfo(ctx, tx)
 		if err != nil {
 			return nil, err
 		}
+		return &info, nil
+	}
 
-		infos = append(infos,
-			payments.TransactionInfo{
-				ID:            []byte(tx.ID),
-				Amount:        *payments.TokenAmountFromBigFloat(&tx.Amount),
-				Received:      *payments.TokenAmountFromBigFloat(&tx.Received),
-				AmountCents:   convertToCents(rate, &tx.Amount),
-				ReceivedCents: convertToCents(rate, &tx.Received),
-				Address:       tx.Address,
-				Status:        status,
-				Link:          link,
-				ExpiresAt:     tx.CreatedAt.Add(tx.Timeout),
-				CreatedAt:     tx.CreatedAt,
-			},
-		)
+	return nil, payments.ErrTransactionNotFound.New("%s", id)
+}
+
+// transactionInfo converts a coinpayments transaction stored in the database to a transaction info.
+func (tokens *storjTokens) transactionInfo(ctx context.Context, tx Transaction) (_ payments.TransactionInfo, err error) {
+	link := coinpayments.GetCheckoutURL(tx.Key, tx.ID)
+
+	var status payments.TransactionStatus
+	switch tx.Status {
+	case coinpayments.StatusPending:
+		status = payments.TransactionStatusPending
+	case coinpayments.StatusReceived:
+		status = payments.TransactionStatusPaid
+	case coinpayments.StatusCancelled:
+		status = payments.TransactionStatusCancelled
+	case coinpayments.StatusCompleted:
+		status = payments.TransactionStatusCompleted
+	default:
+		// unknown
+		status = payments.TransactionStatus(tx.Status.String())
 	}
 
-	return infos, nil
+	rate, err := tokens.service.db.Transactions().GetLockedRate(ctx, tx.ID)
+	if err != nil {
+		return payments.TransactionInfo{}, err
+	}
+
+	return payments.TransactionInfo{
+		ID:            []byte(tx.ID),
+		Amount:        *payments.TokenAmountFromBigFloat(&tx.Amount),
+		Received:      *payments.TokenAmountFromBigFloat(&tx.Received),
+		AmountCents:   convertToCents(rate, &tx.Amount),
+		ReceivedCents: convertToCents(rate, &tx.Received),
+		Address:       tx.Address,
+		Status:        status,
+		Confirmations: tx.Confirmations,
+		Link:          link,
+		ExpiresAt:     tx.CreatedAt.Add(tx.Timeout),
+		CreatedAt:     tx.CreatedAt,
+	}, nil
 }
 
 // ListDepositBonuses returns all deposit bonuses from Stripe associated with user.
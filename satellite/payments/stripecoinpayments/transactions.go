@@ -42,15 +42,16 @@ type TransactionsDB interface {
 
 // Transaction defines coinpayments transaction info that is stored in the DB.
 type Transaction struct {
-	ID        coinpayments.TransactionID
-	AccountID uuid.UUID
-	Address   string
-	Amount    big.Float
-	Received  big.Float
-	Status    coinpayments.Status
-	Key       string
-	Timeout   time.Duration
-	CreatedAt time.Time
+	ID            coinpayments.TransactionID
+	AccountID     uuid.UUID
+	Address       string
+	Amount        big.Float
+	Received      big.Float
+	Status        coinpayments.Status
+	Confirmations int
+	Key           string
+	Timeout       time.Duration
+	CreatedAt     time.Time
 }
 
 // TransactionUpdate holds transaction update info.
@@ -58,6 +59,7 @@ type TransactionUpdate struct {
 	TransactionID coinpayments.TransactionID
 	Status        coinpayments.Status
 	Received      big.Float
+	Confirmations int
 }
 
 // TransactionsPage holds set of transaction and indicates if
@@ -0,0 +1,163 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package stripecoinpayments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/stripe/stripe-go/v72"
+	"github.com/stripe/stripe-go/v72/webhook"
+	"go.uber.org/zap"
+
+	"storj.io/storj/satellite/payments"
+)
+
+// ensures that webhooks implements payments.Webhooks.
+var _ payments.Webhooks = (*webhooks)(nil)
+
+// webhooks is an implementation of payments.Webhooks.
+//
+// architecture: Service
+type webhooks struct {
+	service *Service
+	handler payments.WebhookHandler
+}
+
+// HandleEvent verifies that payload is signed with the stripe webhook secret
+// and dispatches the event it contains.
+//
+// invoice.paid events are passed to the webhook handler, payment_method.detached
+// events replace the default payment method of the customer when it's detached.
+// All the other event types are ignored.
+func (webhooks *webhooks) HandleEvent(ctx context.Context, payload []byte, signature string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if webhooks.service.webhookSecret == "" {
+		return payments.ErrInvalidWebhook.New("webhook secret is not configured")
+	}
+
+	err = webhook.ValidatePayload(payload, signature, webhooks.service.webhookSecret)
+	if err != nil {
+		return payments.ErrInvalidWebhook.Wrap(err)
+	}
+
+	var event stripe.Event
+	if err = json.Unmarshal(payload, &event); err != nil {
+		return payments.ErrInvalidWebhook.Wrap(err)
+	}
+	if event.Data == nil {
+		return payments.ErrInvalidWebhook.New("event %q has no data", event.ID)
+	}
+
+	switch event.Type {
+	case "invoice.paid":
+		return webhooks.invoicePaid(ctx, event)
+	case "payment_method.detached":
+		return webhooks.paymentMethodDetached(ctx, event)
+	default:
+		webhooks.service.log.Debug("unsupported webhook event type", zap.String("ID", event.ID), zap.String("Type", event.Type))
+		return nil
+	}
+}
+
+// invoicePaid notifies the webhook handler about the user whose invoice has been paid.
+func (webhooks *webhooks) invoicePaid(ctx context.Context, event stripe.Event) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var invoice stripe.Invoice
+	if err = json.Unmarshal(event.Data.Raw, &invoice); err != nil {
+		return payments.ErrInvalidWebhook.Wrap(err)
+	}
+	if invoice.Customer == nil || invoice.Customer.ID == "" {
+		return payments.ErrInvalidWebhook.New("invoice %q has no customer", invoice.ID)
+	}
+
+	if invoice.AmountPaid == 0 {
+		webhooks.service.log.Debug("ignoring paid invoice without charge", zap.String("Invoice ID", invoice.ID))
+		return nil
+	}
+
+	userID, err := webhooks.service.db.Customers().GetUserID(ctx, invoice.Customer.ID)
+	if err != nil {
+		if errors.Is(err, ErrNoCustomer) {
+			webhooks.service.log.Debug("ignoring paid invoice of unknown customer",
+				zap.String("Invoice ID", invoice.ID), zap.String("Customer ID", invoice.Customer.ID))
+			return nil
+		}
+		return Error.Wrap(err)
+	}
+
+	return webhooks.handler.InvoicePaid(ctx, userID)
+}
+
+// paymentMethodDetached makes the most recently added card the default payment
+// method of the customer when the default one has been detached.
+func (webhooks *webhooks) paymentMethodDetached(ctx context.Context, event stripe.Event) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var paymentMethod stripe.PaymentMethod
+	if err = json.Unmarshal(event.Data.Raw, &paymentMethod); err != nil {
+		return payments.ErrInvalidWebhook.Wrap(err)
+	}
+
+	// the detached payment method doesn't reference the customer anymore.
+	customerID, _ := event.Data.PreviousAttributes["customer"].(string)
+	if customerID == "" {
+		webhooks.service.log.Debug("ignoring detached payment method without customer", zap.String("Payment Method ID", paymentMethod.ID))
+		return nil
+	}
+
+	_, err = webhooks.service.db.Customers().GetUserID(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, ErrNoCustomer) {
+			webhooks.service.log.Debug("ignoring detached payment method of unknown customer",
+				zap.String("Payment Method ID", paymentMethod.ID), zap.String("Customer ID", customerID))
+			return nil
+		}
+		return Error.Wrap(err)
+	}
+
+	customer, err := webhooks.service.stripeClient.Customers().Get(customerID, nil)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if customer.InvoiceSettings != nil &&
+		customer.InvoiceSettings.DefaultPaymentMethod != nil &&
+		customer.InvoiceSettings.DefaultPaymentMethod.ID != paymentMethod.ID {
+		return nil
+	}
+
+	params := &stripe.PaymentMethodListParams{
+		Customer: &customerID,
+		Type:     stripe.String(string(stripe.PaymentMethodTypeCard)),
+	}
+
+	var latest *stripe.PaymentMethod
+	paymentMethodsIterator := webhooks.service.stripeClient.PaymentMethods().List(params)
+	for paymentMethodsIterator.Next() {
+		card := paymentMethodsIterator.PaymentMethod()
+		if card.ID == paymentMethod.ID {
+			continue
+		}
+		if latest == nil || card.Created > latest.Created {
+			latest = card
+		}
+	}
+	if err = paymentMethodsIterator.Err(); err != nil {
+		return Error.Wrap(err)
+	}
+
+	if latest == nil {
+		return nil
+	}
+
+	_, err = webhooks.service.stripeClient.Customers().Update(customerID, &stripe.CustomerParams{
+		InvoiceSettings: &stripe.CustomerInvoiceSettingsParams{
+			DefaultPaymentMethod: stripe.String(latest.ID),
+		},
+	})
+	return Error.Wrap(err)
+}
@@ -8,9 +8,14 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/zeebo/errs"
+
 	"storj.io/common/uuid"
 )
 
+// ErrTransactionNotFound is returned when a deposit transaction doesn't exist or doesn't belong to the user.
+var ErrTransactionNotFound = errs.Class("transaction not found")
+
 // StorjTokens defines all payments STORJ token related functionality.
 //
 // architecture: Service
@@ -19,6 +24,8 @@ type StorjTokens interface {
 	Deposit(ctx context.Context, userID uuid.UUID, amount int64) (*Transaction, error)
 	// ListTransactionInfos returns all transactions associated with user.
 	ListTransactionInfos(ctx context.Context, userID uuid.UUID) ([]TransactionInfo, error)
+	// GetTransactionInfo returns the transaction of the user with the given id.
+	GetTransactionInfo(ctx context.Context, userID uuid.UUID, id TransactionID) (*TransactionInfo, error)
 	// ListDepositBonuses returns all deposit bonuses associated with user.
 	ListDepositBonuses(ctx context.Context, userID uuid.UUID) ([]DepositBonus, error)
 }
@@ -39,6 +46,8 @@ const (
 	TransactionStatusPending TransactionStatus = "pending"
 	// TransactionStatusCancelled is a transaction that is cancelled and no longer accepting new funds.
 	TransactionStatusCancelled TransactionStatus = "cancelled"
+	// TransactionStatusCompleted is a transaction whose funds were received and fully confirmed.
+	TransactionStatusCompleted TransactionStatus = "completed"
 )
 
 // TransactionID is a transaction ID type.
@@ -72,6 +81,7 @@ type TransactionInfo struct {
 	ReceivedCents int64
 	Address       string
 	Status        TransactionStatus
+	Confirmations int
 	Link          string
 	ExpiresAt     time.Time
 	CreatedAt     time.Time
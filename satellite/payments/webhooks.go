@@ -0,0 +1,31 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package payments
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/uuid"
+)
+
+// ErrInvalidWebhook is returned when a webhook event isn't signed with the configured secret or can't be parsed.
+var ErrInvalidWebhook = errs.Class("invalid webhook event")
+
+// Webhooks handles the events the payment provider sends to the satellite.
+//
+// architecture: Service
+type Webhooks interface {
+	// HandleEvent verifies that payload is signed by the payment provider and processes the event it contains.
+	HandleEvent(ctx context.Context, payload []byte, signature string) error
+}
+
+// WebhookHandler updates the satellite state on behalf of the webhook events.
+//
+// architecture: Service
+type WebhookHandler interface {
+	// InvoicePaid is called when an invoice of the user has been paid.
+	InvoicePaid(ctx context.Context, userID uuid.UUID) error
+}
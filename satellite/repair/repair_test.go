@@ -8,15 +8,19 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"net"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/spacemonkeygo/monkit/v3"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
 
+	"storj.io/common/identity/testidentity"
 	"storj.io/common/memory"
 	"storj.io/common/pb"
 	"storj.io/common/rpc"
@@ -1572,8 +1576,7 @@ func ecRepairerWithMockConnector(t testing.TB, sat *testplanet.Satellite, mock *
 		zaptest.NewLogger(t).Named("a-special-repairer"),
 		newDialer,
 		signing.SigneeFromPeerIdentity(sat.Identity.PeerIdentity()),
-		sat.Config.Repairer.DownloadTimeout,
-		sat.Config.Repairer.InMemoryRepair,
+		sat.Config.Repairer,
 	)
 	return ec
 }
@@ -1733,3 +1736,293 @@ func TestECRepairerGetPrefersCachedIPPort(t *testing.T) {
 		require.NotContains(t, mock.addressesDialed, realAddresses)
 	})
 }
+
+func TestECRepairerGetRemovesTempFiles(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Repairer.InMemoryRepair = false
+				config.Repairer.InMemoryThreshold = 0
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		testSatellite := planet.Satellites[0]
+		audits := testSatellite.Audit
+
+		audits.Worker.Loop.Pause()
+		audits.Chore.Loop.Pause()
+
+		ul := planet.Uplinks[0]
+		testData := testrand.Bytes(8 * memory.KiB)
+
+		err := ul.Upload(ctx, testSatellite, "test.bucket", "some//path", testData)
+		require.NoError(t, err)
+
+		segments, err := testSatellite.Metainfo.Metabase.TestingAllSegments(ctx)
+		require.NoError(t, err)
+		require.Len(t, segments, 1)
+		segment := segments[0]
+
+		limits, privateKey, cachedIPsAndPorts, err := testSatellite.Orders.Service.CreateGetRepairOrderLimits(ctx, metabase.BucketLocation{}, segment, segment.Pieces)
+		require.NoError(t, err)
+
+		ec := ecRepairerWithMockConnector(t, testSatellite, &mockConnector{})
+
+		redundancy, err := eestream.NewRedundancyStrategyFromStorj(segment.Redundancy)
+		require.NoError(t, err)
+
+		repairerScope := monkit.Default.ScopeNamed("storj.io/storj/satellite/repair/repairer")
+		tempFiles := repairerScope.Counter("repair_temp_files")
+		tempBytes := repairerScope.Counter("repair_temp_bytes")
+
+		readCloser, failed, err := ec.Get(ctx, limits, cachedIPsAndPorts, privateKey, redundancy, int64(segment.EncryptedSize))
+		require.NoError(t, err)
+		require.Len(t, failed, 0)
+		require.Equal(t, int64(redundancy.RequiredCount()), tempFiles.Current())
+		require.NotZero(t, tempBytes.Current())
+
+		_, err = io.Copy(ioutil.Discard, readCloser)
+		require.NoError(t, err)
+		require.NoError(t, readCloser.Close())
+
+		require.Zero(t, tempFiles.Current())
+		require.Zero(t, tempBytes.Current())
+	})
+}
+
+func TestECRepairerGetLenientVerification(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		testSatellite := planet.Satellites[0]
+		audits := testSatellite.Audit
+
+		audits.Worker.Loop.Pause()
+		audits.Chore.Loop.Pause()
+
+		ul := planet.Uplinks[0]
+		testData := testrand.Bytes(8 * memory.KiB)
+
+		err := ul.Upload(ctx, testSatellite, "test.bucket", "some//path", testData)
+		require.NoError(t, err)
+
+		segments, err := testSatellite.Metainfo.Metabase.TestingAllSegments(ctx)
+		require.NoError(t, err)
+		require.Len(t, segments, 1)
+		segment := segments[0]
+
+		redundancy, err := eestream.NewRedundancyStrategyFromStorj(segment.Redundancy)
+		require.NoError(t, err)
+
+		// the order limits returned by the storage nodes are signed by the
+		// satellite, so they fail verification against another identity.
+		otherIdentity, err := testidentity.NewTestIdentity(ctx)
+		require.NoError(t, err)
+
+		newECRepairer := func(lenient bool) *repairer.ECRepairer {
+			return repairer.NewECRepairer(
+				zaptest.NewLogger(t),
+				testSatellite.Dialer,
+				signing.SigneeFromPeerIdentity(otherIdentity.PeerIdentity()),
+				repairer.Config{
+					DownloadTimeout:     testSatellite.Config.Repairer.DownloadTimeout,
+					InMemoryRepair:      true,
+					LenientVerification: lenient,
+				},
+			)
+		}
+
+		t.Run("strict", func(t *testing.T) {
+			limits, privateKey, cachedIPsAndPorts, err := testSatellite.Orders.Service.CreateGetRepairOrderLimits(ctx, metabase.BucketLocation{}, segment, segment.Pieces)
+			require.NoError(t, err)
+
+			_, failed, err := newECRepairer(false).Get(ctx, limits, cachedIPsAndPorts, privateKey, redundancy, int64(segment.EncryptedSize))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "available pieces")
+			require.Len(t, failed, 0)
+		})
+
+		t.Run("lenient", func(t *testing.T) {
+			limits, privateKey, cachedIPsAndPorts, err := testSatellite.Orders.Service.CreateGetRepairOrderLimits(ctx, metabase.BucketLocation{}, segment, segment.Pieces)
+			require.NoError(t, err)
+
+			readCloser, failed, err := newECRepairer(true).Get(ctx, limits, cachedIPsAndPorts, privateKey, redundancy, int64(segment.EncryptedSize))
+			require.NoError(t, err)
+			require.Len(t, failed, 0)
+
+			_, err = io.Copy(ioutil.Discard, readCloser)
+			require.NoError(t, err)
+			require.NoError(t, readCloser.Close())
+		})
+	})
+}
+
+func TestECRepairerGetDownloadJitter(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		testSatellite := planet.Satellites[0]
+		audits := testSatellite.Audit
+
+		audits.Worker.Loop.Pause()
+		audits.Chore.Loop.Pause()
+
+		ul := planet.Uplinks[0]
+		testData := testrand.Bytes(8 * memory.KiB)
+
+		err := ul.Upload(ctx, testSatellite, "test.bucket", "some//path", testData)
+		require.NoError(t, err)
+
+		segments, err := testSatellite.Metainfo.Metabase.TestingAllSegments(ctx)
+		require.NoError(t, err)
+		require.Len(t, segments, 1)
+		segment := segments[0]
+
+		redundancy, err := eestream.NewRedundancyStrategyFromStorj(segment.Redundancy)
+		require.NoError(t, err)
+
+		jitterCount := func() (count float64) {
+			repairerScope := monkit.Default.ScopeNamed("storj.io/storj/satellite/repair/repairer")
+			repairerScope.DurationVal("repair_download_jitter").Stats(func(key monkit.SeriesKey, field string, val float64) {
+				if field == "count" {
+					count = val
+				}
+			})
+			return count
+		}
+
+		get := func(jitter time.Duration) []byte {
+			ec := repairer.NewECRepairer(
+				zaptest.NewLogger(t),
+				testSatellite.Dialer,
+				signing.SigneeFromPeerIdentity(testSatellite.Identity.PeerIdentity()),
+				repairer.Config{
+					DownloadTimeout: testSatellite.Config.Repairer.DownloadTimeout,
+					InMemoryRepair:  true,
+					DownloadJitter:  jitter,
+				},
+			)
+
+			limits, privateKey, cachedIPsAndPorts, err := testSatellite.Orders.Service.CreateGetRepairOrderLimits(ctx, metabase.BucketLocation{}, segment, segment.Pieces)
+			require.NoError(t, err)
+
+			readCloser, failed, err := ec.Get(ctx, limits, cachedIPsAndPorts, privateKey, redundancy, int64(segment.EncryptedSize))
+			require.NoError(t, err)
+			require.Len(t, failed, 0)
+			defer func() { require.NoError(t, readCloser.Close()) }()
+
+			data, err := ioutil.ReadAll(readCloser)
+			require.NoError(t, err)
+			return data
+		}
+
+		before := jitterCount()
+		withoutJitter := get(0)
+		require.Equal(t, before, jitterCount())
+
+		withJitter := get(50 * time.Millisecond)
+		require.GreaterOrEqual(t, jitterCount()-before, float64(redundancy.RequiredCount()))
+		require.Equal(t, withoutJitter, withJitter)
+	})
+}
+
+// slowConnector blocks dials to the slow addresses until they are canceled.
+type slowConnector struct {
+	realConnector rpc.Connector
+	slow          map[string]bool
+
+	mu       sync.Mutex
+	dialed   int
+	canceled int
+}
+
+func (m *slowConnector) DialContext(ctx context.Context, tlsConfig *tls.Config, address string) (rpc.ConnectorConn, error) {
+	if m.slow[address] {
+		m.mu.Lock()
+		m.dialed++
+		m.mu.Unlock()
+
+		<-ctx.Done()
+
+		m.mu.Lock()
+		m.canceled++
+		m.mu.Unlock()
+		return nil, ctx.Err()
+	}
+	return m.realConnector.DialContext(ctx, tlsConfig, address)
+}
+
+func TestECRepairerGetOverfetch(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		testSatellite := planet.Satellites[0]
+		audits := testSatellite.Audit
+
+		audits.Worker.Loop.Pause()
+		audits.Chore.Loop.Pause()
+
+		ul := planet.Uplinks[0]
+		testData := testrand.Bytes(8 * memory.KiB)
+
+		err := ul.Upload(ctx, testSatellite, "test.bucket", "some//path", testData)
+		require.NoError(t, err)
+
+		segments, err := testSatellite.Metainfo.Metabase.TestingAllSegments(ctx)
+		require.NoError(t, err)
+		require.Len(t, segments, 1)
+		segment := segments[0]
+
+		redundancy, err := eestream.NewRedundancyStrategyFromStorj(segment.Redundancy)
+		require.NoError(t, err)
+
+		limits, privateKey, _, err := testSatellite.Orders.Service.CreateGetRepairOrderLimits(ctx, metabase.BucketLocation{}, segment, segment.Pieces)
+		require.NoError(t, err)
+
+		// make the first node never respond, so that the repair can only
+		// complete with the other pieces.
+		var nonNilLimits int
+		slow := make(map[string]bool)
+		for _, limit := range limits {
+			if limit == nil {
+				continue
+			}
+			if nonNilLimits == 0 {
+				slow[limit.GetStorageNodeAddress().GetAddress()] = true
+			}
+			nonNilLimits++
+		}
+		overfetch := nonNilLimits - redundancy.RequiredCount()
+		require.True(t, overfetch > 0)
+
+		newDialer := rpc.NewDefaultDialer(testSatellite.Dialer.TLSOptions)
+		connector := &slowConnector{realConnector: newDialer.Connector, slow: slow}
+		newDialer.Connector = connector
+
+		ec := repairer.NewECRepairer(
+			zaptest.NewLogger(t),
+			newDialer,
+			signing.SigneeFromPeerIdentity(testSatellite.Identity.PeerIdentity()),
+			repairer.Config{
+				DownloadTimeout:   time.Hour,
+				InMemoryRepair:    true,
+				DownloadOverfetch: overfetch,
+			},
+		)
+
+		readCloser, failed, err := ec.Get(ctx, limits, map[storj.NodeID]string{}, privateKey, redundancy, int64(segment.EncryptedSize))
+		require.NoError(t, err)
+		require.Len(t, failed, 0)
+
+		_, err = io.Copy(ioutil.Discard, readCloser)
+		require.NoError(t, err)
+		require.NoError(t, readCloser.Close())
+
+		// the download from the slow node, if it was started at all, was
+		// canceled once the other pieces were downloaded.
+		connector.mu.Lock()
+		defer connector.mu.Unlock()
+		require.Equal(t, connector.dialed, connector.canceled)
+	})
+}
@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	mathrand "math/rand"
+	"os"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -41,17 +43,73 @@ type ECRepairer struct {
 	satelliteSignee signing.Signee
 	downloadTimeout time.Duration
 	inmemory        bool
+	// inmemoryThreshold is the largest piece size that is downloaded in
+	// memory when inmemory is false.
+	inmemoryThreshold int64
+	// lenientVerification uses pieces that fail verification instead of
+	// discarding them.
+	lenientVerification bool
+	// downloadJitter is the maximum random delay before each piece download
+	// is started.
+	downloadJitter time.Duration
+	// downloadOverfetch is the number of pieces downloaded concurrently in
+	// addition to the required count.
+	downloadOverfetch int
+
+	rngMu sync.Mutex
+	rng   *mathrand.Rand
 }
 
-// NewECRepairer creates a new repairer for interfacing with storagenodes.
-func NewECRepairer(log *zap.Logger, dialer rpc.Dialer, satelliteSignee signing.Signee, downloadTimeout time.Duration, inmemory bool) *ECRepairer {
+// NewECRepairer creates a new repairer for interfacing with storagenodes. It
+// uses the download settings of config.
+//
+// When InMemoryRepair is false, pieces are downloaded to temporary files, except
+// those whose size is at most InMemoryThreshold, which are kept in memory.
+//
+// When LenientVerification is true, pieces with a missing or invalid order
+// limit signature or a mismatching piece hash are logged and used for the
+// repair anyway. This risks repairing a segment from corrupted data and
+// storing that corruption on new nodes, so it must only be used to recover
+// legacy data that can't be repaired otherwise. Such pieces aren't reported
+// as failed audits.
+//
+// When DownloadJitter is positive, each piece download is delayed by a random
+// duration up to DownloadJitter, so that the downloads of a segment don't all
+// hit the storage nodes at the same instant.
+//
+// When DownloadOverfetch is positive, up to that many pieces are downloaded
+// in addition to the required count, and the slowest downloads are canceled
+// once enough pieces have been downloaded.
+func NewECRepairer(log *zap.Logger, dialer rpc.Dialer, satelliteSignee signing.Signee, config Config) *ECRepairer {
+	downloadOverfetch := config.DownloadOverfetch
+	if downloadOverfetch < 0 {
+		downloadOverfetch = 0
+	}
+
 	return &ECRepairer{
-		log:             log,
-		dialer:          dialer,
-		satelliteSignee: satelliteSignee,
-		downloadTimeout: downloadTimeout,
-		inmemory:        inmemory,
+		log:                 log,
+		dialer:              dialer,
+		satelliteSignee:     satelliteSignee,
+		downloadTimeout:     config.DownloadTimeout,
+		inmemory:            config.InMemoryRepair,
+		inmemoryThreshold:   config.InMemoryThreshold.Int64(),
+		lenientVerification: config.LenientVerification,
+		downloadJitter:      config.DownloadJitter,
+		downloadOverfetch:   downloadOverfetch,
+		rng:                 mathrand.New(mathrand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// jitter returns a random delay in [0, downloadJitter) to wait before
+// starting a piece download.
+func (ec *ECRepairer) jitter() time.Duration {
+	if ec.downloadJitter <= 0 {
+		return 0
 	}
+
+	ec.rngMu.Lock()
+	defer ec.rngMu.Unlock()
+	return time.Duration(ec.rng.Int63n(int64(ec.downloadJitter)))
 }
 
 func (ec *ECRepairer) dialPiecestore(ctx context.Context, n storj.NodeURL) (*piecestore.Client, error) {
@@ -62,6 +120,8 @@ func (ec *ECRepairer) dialPiecestore(ctx context.Context, n storj.NodeURL) (*pie
 // It attempts to download from the minimum required number based on the redundancy scheme.
 // After downloading a piece, the ECRepairer will verify the hash and original order limit for that piece.
 // If verification fails, another piece will be downloaded until we reach the minimum required or run out of order limits.
+// When overfetching, additional pieces are downloaded concurrently and the downloads still in progress are canceled
+// once the minimum required have been downloaded.
 // If piece hash verification fails, it will return all failed node IDs.
 func (ec *ECRepairer) Get(ctx context.Context, limits []*pb.AddressedOrderLimit, cachedIPsAndPorts map[storj.NodeID]string, privateKey storj.PiecePrivateKey, es eestream.ErasureScheme, dataSize int64) (_ io.ReadCloser, failedPieces []*pb.RemotePiece, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -82,7 +142,14 @@ func (ec *ECRepairer) Get(ctx context.Context, limits []*pb.AddressedOrderLimit,
 	unusedLimits := nonNilLimits
 	pieceReaders := make(map[int]io.ReadCloser)
 
-	limiter := sync2.NewLimiter(es.RequiredCount())
+	maxInProgress := es.RequiredCount() + ec.downloadOverfetch
+
+	// downloadCtx is canceled once enough pieces have been downloaded, to stop
+	// the overfetched downloads.
+	downloadCtx, cancelDownloads := context.WithCancel(ctx)
+	defer cancelDownloads()
+
+	limiter := sync2.NewLimiter(maxInProgress)
 	cond := sync.NewCond(&sync.Mutex{})
 
 	var errlist errs.Group
@@ -111,7 +178,7 @@ func (ec *ECRepairer) Get(ctx context.Context, limits []*pb.AddressedOrderLimit,
 					return
 				}
 
-				if successfulPieces+inProgress >= es.RequiredCount() {
+				if successfulPieces+inProgress >= maxInProgress {
 					cond.Wait()
 					continue
 				}
@@ -120,22 +187,22 @@ func (ec *ECRepairer) Get(ctx context.Context, limits []*pb.AddressedOrderLimit,
 				inProgress++
 				cond.L.Unlock()
 
-				lastIPPort := cachedIPsAndPorts[limit.GetLimit().StorageNodeId]
-				address := limit.GetStorageNodeAddress().GetAddress()
-				var triedLastIPPort bool
-				if lastIPPort != "" && lastIPPort != address {
-					address = lastIPPort
-					triedLastIPPort = true
+				if delay := ec.jitter(); delay > 0 {
+					mon.DurationVal("repair_download_jitter").Observe(delay)
+					// the download fails right away when ctx is canceled
+					_ = sync2.Sleep(downloadCtx, delay)
 				}
 
-				pieceReadCloser, err := ec.downloadAndVerifyPiece(ctx, limit, address, privateKey, pieceSize)
-
-				// if piecestore dial with last ip:port failed try again with node address
-				if triedLastIPPort && piecestore.Error.Has(err) {
-					pieceReadCloser, err = ec.downloadAndVerifyPiece(ctx, limit, limit.GetStorageNodeAddress().GetAddress(), privateKey, pieceSize)
-				}
+				pieceReadCloser, err := ec.downloadPiece(downloadCtx, limit, cachedIPsAndPorts, privateKey, pieceSize, ec.lenientVerification)
 				cond.L.Lock()
 				inProgress--
+				if successfulPieces >= es.RequiredCount() {
+					// an overfetched download that is no longer needed
+					if err == nil {
+						_ = pieceReadCloser.Close()
+					}
+					return
+				}
 				if err != nil {
 					// gather nodes where the calculated piece hash doesn't match the uplink signed piece hash
 					if ErrPieceHashVerifyFailed.Has(err) {
@@ -157,6 +224,9 @@ func (ec *ECRepairer) Get(ctx context.Context, limits []*pb.AddressedOrderLimit,
 
 				pieceReaders[currentLimitIndex] = pieceReadCloser
 				successfulPieces++
+				if successfulPieces >= es.RequiredCount() {
+					cancelDownloads()
+				}
 
 				return
 			}
@@ -167,6 +237,7 @@ func (ec *ECRepairer) Get(ctx context.Context, limits []*pb.AddressedOrderLimit,
 
 	if successfulPieces < es.RequiredCount() {
 		mon.Meter("download_failed_not_enough_pieces_repair").Mark(1) //mon:locked
+		closePieceReaders(pieceReaders)
 		return nil, failedPieces, &irreparableError{
 			piecesAvailable: int32(successfulPieces),
 			piecesRequired:  int32(es.RequiredCount()),
@@ -176,6 +247,7 @@ func (ec *ECRepairer) Get(ctx context.Context, limits []*pb.AddressedOrderLimit,
 
 	fec, err := infectious.NewFEC(es.RequiredCount(), es.TotalCount())
 	if err != nil {
+		closePieceReaders(pieceReaders)
 		return nil, failedPieces, Error.Wrap(err)
 	}
 
@@ -188,10 +260,113 @@ func (ec *ECRepairer) Get(ctx context.Context, limits []*pb.AddressedOrderLimit,
 	return decodeReader, failedPieces, nil
 }
 
+// PieceVerification is the outcome of verifying a piece of a segment.
+type PieceVerification struct {
+	Number int
+	NodeID storj.NodeID
+	// Err is nil when the piece was downloaded and its hash verified.
+	Err error
+}
+
+// Verify downloads pieces of a segment until the required number of them has
+// been verified, or there are no pieces left to try, and returns the outcome
+// for each piece it tried together with whether the segment can be
+// reconstructed from the verified pieces. Pieces failing hash verification are
+// never considered verified, regardless of lenient verification.
+func (ec *ECRepairer) Verify(ctx context.Context, limits []*pb.AddressedOrderLimit, cachedIPsAndPorts map[storj.NodeID]string, privateKey storj.PiecePrivateKey, es eestream.ErasureScheme, dataSize int64) (verifications []PieceVerification, reconstructable bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(limits) != es.TotalCount() {
+		return nil, false, Error.New("number of limits slice (%d) does not match total count (%d) of erasure scheme", len(limits), es.TotalCount())
+	}
+
+	pieceSize := eestream.CalcPieceSize(dataSize, es)
+
+	var untried []int
+	for pieceNum, limit := range limits {
+		if limit != nil {
+			untried = append(untried, pieceNum)
+		}
+	}
+
+	// the pieces are tried in batches of as many as are still needed, so
+	// that no more pieces than necessary are downloaded.
+	var verified int
+	for verified < es.RequiredCount() && len(untried) > 0 {
+		batchSize := es.RequiredCount() - verified
+		if batchSize > len(untried) {
+			batchSize = len(untried)
+		}
+		batch := untried[:batchSize]
+		untried = untried[batchSize:]
+
+		results := make([]PieceVerification, len(batch))
+
+		var group errs2.Group
+		for i, pieceNum := range batch {
+			i, limit := i, limits[pieceNum]
+			results[i] = PieceVerification{
+				Number: pieceNum,
+				NodeID: limit.GetLimit().StorageNodeId,
+			}
+			group.Go(func() error {
+				pieceReadCloser, err := ec.downloadPiece(ctx, limit, cachedIPsAndPorts, privateKey, pieceSize, false)
+				if err == nil {
+					// only the outcome of the verification is needed.
+					_ = pieceReadCloser.Close()
+				}
+				results[i].Err = err
+				return nil
+			})
+		}
+		group.Wait()
+
+		for _, result := range results {
+			if result.Err == nil {
+				verified++
+			}
+		}
+		verifications = append(verifications, results...)
+	}
+
+	return verifications, verified >= es.RequiredCount(), nil
+}
+
+// downloadPiece downloads and verifies a piece from the storage node of limit,
+// first trying its last known ip:port and falling back to its address.
+func (ec *ECRepairer) downloadPiece(ctx context.Context, limit *pb.AddressedOrderLimit, cachedIPsAndPorts map[storj.NodeID]string, privateKey storj.PiecePrivateKey, pieceSize int64, lenientVerification bool) (io.ReadCloser, error) {
+	lastIPPort := cachedIPsAndPorts[limit.GetLimit().StorageNodeId]
+	address := limit.GetStorageNodeAddress().GetAddress()
+	var triedLastIPPort bool
+	if lastIPPort != "" && lastIPPort != address {
+		address = lastIPPort
+		triedLastIPPort = true
+	}
+
+	pieceReadCloser, err := ec.downloadAndVerifyPiece(ctx, limit, address, privateKey, pieceSize, lenientVerification)
+
+	// if piecestore dial with last ip:port failed try again with node address
+	if triedLastIPPort && piecestore.Error.Has(err) {
+		pieceReadCloser, err = ec.downloadAndVerifyPiece(ctx, limit, limit.GetStorageNodeAddress().GetAddress(), privateKey, pieceSize, lenientVerification)
+	}
+	return pieceReadCloser, err
+}
+
+// closePieceReaders closes the downloaded pieces when they won't be decoded, so
+// that their temporary files are removed.
+func closePieceReaders(pieceReaders map[int]io.ReadCloser) {
+	for _, pieceReader := range pieceReaders {
+		// the piece is only read from memory or a local file at this point,
+		// so closing it can't fail in a way that affects the repair.
+		_ = pieceReader.Close()
+	}
+}
+
 // downloadAndVerifyPiece downloads a piece from a storagenode,
 // expects the original order limit to have the correct piece public key,
-// and expects the hash of the data to match the signed hash provided by the storagenode.
-func (ec *ECRepairer) downloadAndVerifyPiece(ctx context.Context, limit *pb.AddressedOrderLimit, address string, privateKey storj.PiecePrivateKey, pieceSize int64) (pieceReadCloser io.ReadCloser, err error) {
+// and expects the hash of the data to match the signed hash provided by the storagenode,
+// unless lenientVerification is enabled.
+func (ec *ECRepairer) downloadAndVerifyPiece(ctx context.Context, limit *pb.AddressedOrderLimit, address string, privateKey storj.PiecePrivateKey, pieceSize int64, lenientVerification bool) (pieceReadCloser io.ReadCloser, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	// contact node
@@ -215,39 +390,17 @@ func (ec *ECRepairer) downloadAndVerifyPiece(ctx context.Context, limit *pb.Addr
 
 	hashWriter := pkcrypto.NewHash()
 	downloadReader := io.TeeReader(downloader, hashWriter)
-	var downloadedPieceSize int64
 
-	if ec.inmemory {
-		pieceBytes, err := ioutil.ReadAll(downloadReader)
-		if err != nil {
-			return nil, err
-		}
-		downloadedPieceSize = int64(len(pieceBytes))
-		pieceReadCloser = ioutil.NopCloser(bytes.NewReader(pieceBytes))
-	} else {
-		tempfile, err := tmpfile.New("", "satellite-repair-*")
-		if err != nil {
-			return nil, err
-		}
-		defer func() {
-			// close and remove file if there is some error
-			if err != nil {
-				err = errs.Combine(err, tempfile.Close())
-			}
-		}()
-
-		downloadedPieceSize, err = io.Copy(tempfile, downloadReader)
-		if err != nil {
-			return nil, err
-		}
-
-		// seek to beginning of file so the repair job starts at the beginning of the piece
-		_, err = tempfile.Seek(0, io.SeekStart)
+	buffered, downloadedPieceSize, err := ec.bufferPiece(downloadReader, pieceSize)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// close and remove the buffered piece if there is some error
 		if err != nil {
-			return nil, err
+			err = errs.Combine(err, buffered.Close())
 		}
-		pieceReadCloser = tempfile
-	}
+	}()
 
 	mon.Meter("repair_bytes_downloaded").Mark64(downloadedPieceSize) //mon:locked
 
@@ -257,26 +410,127 @@ func (ec *ECRepairer) downloadAndVerifyPiece(ctx context.Context, limit *pb.Addr
 
 	// get signed piece hash and original order limit
 	hash, originalLimit := downloader.GetHashAndLimit()
+	if err := ec.verifyPiece(ctx, hash, originalLimit, hashWriter.Sum(nil)); err != nil {
+		if !lenientVerification {
+			return nil, err
+		}
+
+		mon.Meter("repair_unverified_pieces_used").Mark(1)
+		ec.log.Warn("using piece that failed verification for repair",
+			zap.Stringer("Node ID", limit.GetLimit().StorageNodeId),
+			zap.Stringer("Piece ID", limit.GetLimit().PieceId),
+			zap.Error(err))
+	}
+
+	return buffered, nil
+}
+
+// verifyPiece verifies that the original order limit sent by the storage node
+// is signed by the satellite and that the piece hash matches the downloaded data.
+func (ec *ECRepairer) verifyPiece(ctx context.Context, hash *pb.PieceHash, originalLimit *pb.OrderLimit, calculatedHash []byte) error {
 	if hash == nil {
-		return nil, Error.New("hash was not sent from storagenode")
+		return Error.New("hash was not sent from storagenode")
 	}
 	if originalLimit == nil {
-		return nil, Error.New("original order limit was not sent from storagenode")
+		return Error.New("original order limit was not sent from storagenode")
 	}
 
 	// verify order limit from storage node is signed by the satellite
 	if err := verifyOrderLimitSignature(ctx, ec.satelliteSignee, originalLimit); err != nil {
-		return nil, err
+		return err
 	}
 
 	// verify the hashes from storage node
-	calculatedHash := hashWriter.Sum(nil)
 	if err := verifyPieceHash(ctx, originalLimit, hash, calculatedHash); err != nil {
+		return ErrPieceHashVerifyFailed.Wrap(err)
+	}
 
-		return nil, ErrPieceHashVerifyFailed.Wrap(err)
+	return nil
+}
+
+// bufferPiece reads a piece of the expected pieceSize from reader, either into
+// memory or into a temporary file, and returns a reader positioned at the
+// beginning of the piece together with the number of bytes read.
+func (ec *ECRepairer) bufferPiece(reader io.Reader, pieceSize int64) (_ io.ReadCloser, _ int64, err error) {
+	if ec.inmemory || pieceSize <= ec.inmemoryThreshold {
+		pieceBytes, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, 0, err
+		}
+		return ioutil.NopCloser(bytes.NewReader(pieceBytes)), int64(len(pieceBytes)), nil
 	}
 
-	return pieceReadCloser, nil
+	tempfile, err := newRepairTempFile()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		// close and remove file if there is some error
+		if err != nil {
+			err = errs.Combine(err, tempfile.Close())
+		}
+	}()
+
+	size, err := io.Copy(tempfile, reader)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// seek to beginning of file so the repair job starts at the beginning of the piece
+	_, err = tempfile.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, 0, err
+	}
+	return tempfile, size, nil
+}
+
+// repairTempFile is a temporary file holding a downloaded piece. It's counted
+// by the repair_temp_files and repair_temp_bytes counters until it's closed.
+type repairTempFile struct {
+	file *os.File
+	size int64
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// newRepairTempFile creates a new temporary file, which is removed when closed.
+func newRepairTempFile() (*repairTempFile, error) {
+	file, err := tmpfile.New("", "satellite-repair-*")
+	if err != nil {
+		return nil, err
+	}
+	mon.Counter("repair_temp_files").Inc(1)
+	return &repairTempFile{file: file}, nil
+}
+
+// Read reads from the temporary file.
+func (tempfile *repairTempFile) Read(p []byte) (int, error) {
+	return tempfile.file.Read(p)
+}
+
+// Write writes to the temporary file and counts the written bytes.
+func (tempfile *repairTempFile) Write(p []byte) (int, error) {
+	n, err := tempfile.file.Write(p)
+	tempfile.size += int64(n)
+	mon.Counter("repair_temp_bytes").Inc(int64(n))
+	return n, err
+}
+
+// Seek sets the offset for the next read or write.
+func (tempfile *repairTempFile) Seek(offset int64, whence int) (int64, error) {
+	return tempfile.file.Seek(offset, whence)
+}
+
+// Close closes and removes the temporary file. It's safe to call it more than
+// once, the counters are only decremented the first time.
+func (tempfile *repairTempFile) Close() error {
+	tempfile.closeOnce.Do(func() {
+		tempfile.closeErr = tempfile.file.Close()
+		mon.Counter("repair_temp_files").Dec(1)
+		mon.Counter("repair_temp_bytes").Dec(tempfile.size)
+	})
+	return tempfile.closeErr
 }
 
 func verifyPieceHash(ctx context.Context, limit *pb.OrderLimit, hash *pb.PieceHash, expectedHash []byte) (err error) {
@@ -307,23 +561,37 @@ func verifyOrderLimitSignature(ctx context.Context, satellite signing.Signee, li
 	return nil
 }
 
+// RepairSummary summarizes the uploads of a repair.
+type RepairSummary struct {
+	// Attempted is the number of pieces for which an upload was started.
+	Attempted  int
+	Successful int
+	Failed     int
+	Canceled   int
+	// Nodes are the nodes which the pieces were successfully uploaded to.
+	Nodes storj.NodeIDList
+}
+
 // Repair takes a provided segment, encodes it with the provided redundancy strategy,
 // and uploads the pieces in need of repair to new nodes provided by order limits.
-func (ec *ECRepairer) Repair(ctx context.Context, limits []*pb.AddressedOrderLimit, privateKey storj.PiecePrivateKey, rs eestream.RedundancyStrategy, data io.Reader, timeout time.Duration, successfulNeeded int) (successfulNodes []*pb.Node, successfulHashes []*pb.PieceHash, err error) {
+//
+// The summary is returned also when the repair fails once the uploads have
+// been started.
+func (ec *ECRepairer) Repair(ctx context.Context, limits []*pb.AddressedOrderLimit, privateKey storj.PiecePrivateKey, rs eestream.RedundancyStrategy, data io.Reader, timeout time.Duration, successfulNeeded int) (successfulNodes []*pb.Node, successfulHashes []*pb.PieceHash, summary RepairSummary, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	pieceCount := len(limits)
 	if pieceCount != rs.TotalCount() {
-		return nil, nil, Error.New("size of limits slice (%d) does not match total count (%d) of erasure scheme", pieceCount, rs.TotalCount())
+		return nil, nil, RepairSummary{}, Error.New("size of limits slice (%d) does not match total count (%d) of erasure scheme", pieceCount, rs.TotalCount())
 	}
 
 	if !unique(limits) {
-		return nil, nil, Error.New("duplicated nodes are not allowed")
+		return nil, nil, RepairSummary{}, Error.New("duplicated nodes are not allowed")
 	}
 
 	readers, err := eestream.EncodeReader2(ctx, ioutil.NopCloser(data), rs)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, RepairSummary{}, err
 	}
 
 	// info contains data about a single piece transfer
@@ -405,6 +673,18 @@ func (ec *ECRepairer) Repair(ctx context.Context, limits []*pb.AddressedOrderLim
 	// Ensure timer is stopped
 	_ = timer.Stop()
 
+	summary = RepairSummary{
+		Attempted:  nonNilCount(limits),
+		Successful: int(successfulCount),
+		Failed:     int(failureCount),
+		Canceled:   int(cancellationCount),
+	}
+	for _, node := range successfulNodes {
+		if node != nil {
+			summary.Nodes = append(summary.Nodes, node.Id)
+		}
+	}
+
 	// TODO: clean up the partially uploaded segment's pieces
 	defer func() {
 		select {
@@ -415,7 +695,7 @@ func (ec *ECRepairer) Repair(ctx context.Context, limits []*pb.AddressedOrderLim
 	}()
 
 	if successfulCount == 0 {
-		return nil, nil, Error.New("repair to all nodes failed")
+		return nil, nil, summary, Error.New("repair to all nodes failed")
 	}
 
 	ec.log.Debug("Successfully repaired",
@@ -427,7 +707,7 @@ func (ec *ECRepairer) Repair(ctx context.Context, limits []*pb.AddressedOrderLim
 	mon.IntVal("repair_segment_pieces_failed").Observe(int64(failureCount))        //mon:locked
 	mon.IntVal("repair_segment_pieces_canceled").Observe(int64(cancellationCount)) //mon:locked
 
-	return successfulNodes, successfulHashes, nil
+	return successfulNodes, successfulHashes, summary, nil
 }
 
 func (ec *ECRepairer) putPiece(ctx, parent context.Context, limit *pb.AddressedOrderLimit, privateKey storj.PiecePrivateKey, data io.ReadCloser) (hash *pb.PieceHash, err error) {
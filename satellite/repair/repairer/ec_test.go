@@ -0,0 +1,142 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package repairer
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/identity/testidentity"
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/peertls/tlsopts"
+	"storj.io/common/rpc"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/uplink/private/eestream"
+)
+
+func TestECRepairerBufferPiece(t *testing.T) {
+	const threshold = 4 * memory.KiB
+
+	tempFiles := mon.Counter("repair_temp_files")
+	tempBytes := mon.Counter("repair_temp_bytes")
+
+	for _, tt := range []struct {
+		name     string
+		inmemory bool
+		size     memory.Size
+		onDisk   bool
+	}{
+		{name: "in memory", inmemory: true, size: 2 * threshold, onDisk: false},
+		{name: "below threshold", inmemory: false, size: threshold / 2, onDisk: false},
+		{name: "at threshold", inmemory: false, size: threshold, onDisk: false},
+		{name: "above threshold", inmemory: false, size: threshold + 1, onDisk: true},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ec := NewECRepairer(zaptest.NewLogger(t), rpc.Dialer{}, nil, Config{InMemoryRepair: tt.inmemory, InMemoryThreshold: threshold})
+
+			data := testrand.BytesInt(tt.size.Int())
+			piece, size, err := ec.bufferPiece(bytes.NewReader(data), int64(len(data)))
+			require.NoError(t, err)
+			require.Equal(t, int64(len(data)), size)
+
+			_, isFile := piece.(*repairTempFile)
+			require.Equal(t, tt.onDisk, isFile)
+			if tt.onDisk {
+				require.Equal(t, int64(1), tempFiles.Current())
+				require.Equal(t, int64(len(data)), tempBytes.Current())
+			}
+
+			read, err := ioutil.ReadAll(piece)
+			require.NoError(t, err)
+			require.Equal(t, data, read)
+			require.NoError(t, piece.Close())
+
+			require.Zero(t, tempFiles.Current())
+			require.Zero(t, tempBytes.Current())
+		})
+	}
+}
+
+func TestECRepairerJitter(t *testing.T) {
+	ec := NewECRepairer(zaptest.NewLogger(t), rpc.Dialer{}, nil, Config{InMemoryRepair: true})
+	require.Zero(t, ec.jitter())
+
+	const maxJitter = 10 * time.Millisecond
+	ec = NewECRepairer(zaptest.NewLogger(t), rpc.Dialer{}, nil, Config{InMemoryRepair: true, DownloadJitter: maxJitter})
+	for i := 0; i < 100; i++ {
+		delay := ec.jitter()
+		require.GreaterOrEqual(t, int64(delay), int64(0))
+		require.Less(t, int64(delay), int64(maxJitter))
+	}
+}
+
+func TestECRepairerRepairSummary(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	identity, err := testidentity.NewTestIdentity(ctx)
+	require.NoError(t, err)
+	tlsOptions, err := tlsopts.NewOptions(identity, tlsopts.Config{PeerIDVersions: "*"}, nil)
+	require.NoError(t, err)
+	ec := NewECRepairer(zaptest.NewLogger(t), rpc.NewDefaultDialer(tlsOptions), nil, Config{InMemoryRepair: true})
+
+	rs, err := eestream.NewRedundancyStrategyFromStorj(storj.RedundancyScheme{
+		Algorithm:      storj.ReedSolomon,
+		ShareSize:      256,
+		RequiredShares: 1,
+		RepairShares:   2,
+		OptimalShares:  3,
+		TotalShares:    4,
+	})
+	require.NoError(t, err)
+
+	// the uploads go to a port which is closed again
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	// one of the pieces isn't uploaded at all
+	limits := make([]*pb.AddressedOrderLimit, rs.TotalCount())
+	for i := range limits[1:] {
+		limits[i+1] = &pb.AddressedOrderLimit{
+			Limit: &pb.OrderLimit{
+				StorageNodeId: testrand.NodeID(),
+				PieceId:       testrand.PieceID(),
+			},
+			StorageNodeAddress: &pb.NodeAddress{Address: address},
+		}
+	}
+
+	data := testrand.Bytes(memory.KiB)
+
+	_, _, summary, err := ec.Repair(ctx, limits, storj.PiecePrivateKey{}, rs, bytes.NewReader(data), time.Minute, rs.OptimalThreshold())
+	require.Error(t, err)
+	require.Equal(t, 3, summary.Attempted)
+	require.Zero(t, summary.Successful)
+	require.Equal(t, 3, summary.Failed)
+	require.Zero(t, summary.Canceled)
+	require.Empty(t, summary.Nodes)
+
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_, _, summary, err = ec.Repair(canceledCtx, limits, storj.PiecePrivateKey{}, rs, bytes.NewReader(data), time.Minute, rs.OptimalThreshold())
+	require.Error(t, err)
+	require.Equal(t, 3, summary.Attempted)
+	require.Zero(t, summary.Successful)
+	require.Zero(t, summary.Failed)
+	require.Equal(t, 3, summary.Canceled)
+}
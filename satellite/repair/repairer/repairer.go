@@ -34,6 +34,10 @@ type Config struct {
 	MaxBufferMem                  memory.Size   `help:"maximum buffer memory (in bytes) to be allocated for read buffers" default:"4.0 MiB"`
 	MaxExcessRateOptimalThreshold float64       `help:"ratio applied to the optimal threshold to calculate the excess of the maximum number of repaired pieces to upload" default:"0.05"`
 	InMemoryRepair                bool          `help:"whether to download pieces for repair in memory (true) or download to disk (false)" default:"false"`
+	InMemoryThreshold             memory.Size   `help:"when downloading pieces for repair to disk, pieces up to this size are downloaded in memory instead (0 B means disabled)" default:"0 B"`
+	LenientVerification           bool          `help:"DANGEROUS: use pieces that fail hash or order limit signature verification for repair instead of discarding them, only for recovering legacy data" default:"false" hidden:"true"`
+	DownloadJitter                time.Duration `help:"maximum random delay before starting each piece download for repair, to avoid all downloads of a segment starting at once (0 means disabled)" default:"0s"`
+	DownloadOverfetch             int           `help:"number of pieces to download for repair in addition to the required count, canceling the slowest downloads once enough have finished" default:"0"`
 }
 
 // Service contains the information needed to run the repair service.
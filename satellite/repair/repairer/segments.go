@@ -73,17 +73,17 @@ type SegmentRepairer struct {
 
 // NewSegmentRepairer creates a new instance of SegmentRepairer.
 //
-// excessPercentageOptimalThreshold is the percentage to apply over the optimal
-// threshould to determine the maximum limit of nodes to upload repaired pieces,
-// when negative, 0 is applied.
+// config.MaxExcessRateOptimalThreshold is the percentage to apply over the
+// optimal threshould to determine the maximum limit of nodes to upload
+// repaired pieces, when negative, 0 is applied.
 func NewSegmentRepairer(
 	log *zap.Logger, metabase *metabase.DB, orders *orders.Service,
 	overlay *overlay.Service, reputation *reputation.Service, dialer rpc.Dialer,
-	timeout time.Duration, excessOptimalThreshold float64,
-	repairOverrides checker.RepairOverrides, downloadTimeout time.Duration,
-	inMemoryRepair bool, satelliteSignee signing.Signee,
+	repairOverrides checker.RepairOverrides, satelliteSignee signing.Signee,
+	config Config,
 ) *SegmentRepairer {
 
+	excessOptimalThreshold := config.MaxExcessRateOptimalThreshold
 	if excessOptimalThreshold < 0 {
 		excessOptimalThreshold = 0
 	}
@@ -95,8 +95,8 @@ func NewSegmentRepairer(
 		orders:                     orders,
 		overlay:                    overlay,
 		reputation:                 reputation,
-		ec:                         NewECRepairer(log.Named("ec repairer"), dialer, satelliteSignee, downloadTimeout, inMemoryRepair),
-		timeout:                    timeout,
+		ec:                         NewECRepairer(log.Named("ec repairer"), dialer, satelliteSignee, config),
+		timeout:                    config.Timeout,
 		multiplierOptimalThreshold: 1 + excessOptimalThreshold,
 		repairOverrides:            repairOverrides.GetMap(),
 
@@ -110,6 +110,8 @@ func NewSegmentRepairer(
 func (repairer *SegmentRepairer) Repair(ctx context.Context, queueSegment *queue.InjuredSegment) (shouldDelete bool, err error) {
 	defer mon.Task()(&ctx, queueSegment.StreamID.String(), queueSegment.Position.Encode())(&err)
 
+	start := time.Now()
+
 	segment, err := repairer.metabase.GetSegmentByPosition(ctx, metabase.GetSegmentByPosition{
 		StreamID: queueSegment.StreamID,
 		Position: queueSegment.Position,
@@ -314,8 +316,9 @@ func (repairer *SegmentRepairer) Repair(ctx context.Context, queueSegment *queue
 	defer func() { err = errs.Combine(err, segmentReader.Close()) }()
 
 	// Upload the repaired pieces
-	successfulNodes, _, err := repairer.ec.Repair(ctx, putLimits, putPrivateKey, redundancy, segmentReader, repairer.timeout, minSuccessfulNeeded)
+	successfulNodes, _, summary, err := repairer.ec.Repair(ctx, putLimits, putPrivateKey, redundancy, segmentReader, repairer.timeout, minSuccessfulNeeded)
 	if err != nil {
+		repairer.logRepairSummary(queueSegment, len(healthyPieces), len(healthyPieces), summary, time.Since(start), err)
 		return false, repairPutError.Wrap(err)
 	}
 
@@ -341,6 +344,8 @@ func (repairer *SegmentRepairer) Repair(ctx context.Context, queueSegment *queue
 	mon.Meter("repair_bytes_uploaded").Mark64(bytesRepaired) //mon:locked
 
 	healthyAfterRepair := len(healthyPieces) + len(repairedPieces)
+	repairer.logRepairSummary(queueSegment, len(healthyPieces), healthyAfterRepair, summary, time.Since(start), nil)
+
 	switch {
 	case healthyAfterRepair <= int(segment.Redundancy.RepairShares):
 		// Important: this indicates a failure to PUT enough pieces to the network to pass
@@ -427,6 +432,29 @@ func (repairer *SegmentRepairer) Repair(ctx context.Context, queueSegment *queue
 	return true, nil
 }
 
+// logRepairSummary logs the outcome of uploading the repaired pieces of a
+// segment as a single debug event. duration is how long the whole repair of
+// the segment took.
+func (repairer *SegmentRepairer) logRepairSummary(queueSegment *queue.InjuredSegment, healthyBefore, healthyAfter int, summary RepairSummary, duration time.Duration, err error) {
+	fields := []zap.Field{
+		zap.Stringer("Stream ID", queueSegment.StreamID),
+		zap.Uint64("Position", queueSegment.Position.Encode()),
+		zap.Int("Healthy Before", healthyBefore),
+		zap.Int("Healthy After", healthyAfter),
+		zap.Int("Attempted", summary.Attempted),
+		zap.Int("Successful", summary.Successful),
+		zap.Int("Failed", summary.Failed),
+		zap.Int("Canceled", summary.Canceled),
+		zap.Strings("Nodes", summary.Nodes.Strings()),
+		zap.Duration("Duration", duration),
+	}
+	if err != nil {
+		repairer.log.Debug("segment repair failed", append(fields, zap.Error(err))...)
+		return
+	}
+	repairer.log.Debug("segment repaired", fields...)
+}
+
 func (repairer *SegmentRepairer) getStatsByRS(redundancy *pb.RedundancyScheme) *stats {
 	rsString := getRSString(repairer.loadRedundancy(redundancy))
 	return repairer.statsCollector.getStatsByRS(rsString)
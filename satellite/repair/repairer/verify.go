@@ -0,0 +1,137 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package repairer
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/satellite/metabase"
+	"storj.io/storj/satellite/orders"
+	"storj.io/uplink/private/eestream"
+)
+
+// ObjectVerifier verifies that objects can be reconstructed from pieces which
+// hash-verify, in the same way the repairer downloads them.
+//
+// architecture: Service
+type ObjectVerifier struct {
+	log      *zap.Logger
+	metabase *metabase.DB
+	orders   *orders.Service
+	ec       *ECRepairer
+}
+
+// NewObjectVerifier creates a new object verifier.
+func NewObjectVerifier(log *zap.Logger, metabase *metabase.DB, orders *orders.Service, ec *ECRepairer) *ObjectVerifier {
+	return &ObjectVerifier{
+		log:      log,
+		metabase: metabase,
+		orders:   orders,
+		ec:       ec,
+	}
+}
+
+// ObjectVerification is the outcome of verifying an object.
+type ObjectVerification struct {
+	Segments        []SegmentVerification
+	Reconstructable bool
+}
+
+// SegmentVerification is the outcome of verifying a segment. Pieces contains
+// the outcome for the pieces that were tried, which are only as many as needed
+// to reconstruct the segment unless some of them fail. Inline segments are
+// always reconstructable.
+type SegmentVerification struct {
+	Position        metabase.SegmentPosition
+	Inline          bool
+	Pieces          []PieceVerification
+	Reconstructable bool
+	// Err is set when the pieces couldn't be tried at all, e.g. because not
+	// enough of their nodes are online.
+	Err error
+}
+
+// VerifyObject verifies every segment of the latest version of the object.
+func (verifier *ObjectVerifier) VerifyObject(ctx context.Context, location metabase.ObjectLocation) (_ ObjectVerification, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	object, err := verifier.metabase.GetObjectLatestVersion(ctx, metabase.GetObjectLatestVersion{
+		ObjectLocation: location,
+	})
+	if err != nil {
+		return ObjectVerification{}, err
+	}
+
+	verification := ObjectVerification{Reconstructable: true}
+
+	cursor := metabase.SegmentPosition{}
+	for {
+		result, err := verifier.metabase.ListSegments(ctx, metabase.ListSegments{
+			StreamID: object.StreamID,
+			Cursor:   cursor,
+		})
+		if err != nil {
+			return ObjectVerification{}, err
+		}
+
+		for _, segment := range result.Segments {
+			segmentVerification, err := verifier.verifySegment(ctx, segment)
+			if err != nil {
+				return ObjectVerification{}, err
+			}
+
+			verification.Segments = append(verification.Segments, segmentVerification)
+			if !segmentVerification.Reconstructable {
+				verification.Reconstructable = false
+			}
+		}
+
+		if !result.More || len(result.Segments) == 0 {
+			break
+		}
+		cursor = result.Segments[len(result.Segments)-1].Position
+	}
+
+	return verification, nil
+}
+
+// verifySegment downloads and verifies the pieces of a remote segment.
+func (verifier *ObjectVerifier) verifySegment(ctx context.Context, segment metabase.Segment) (_ SegmentVerification, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	verification := SegmentVerification{
+		Position: segment.Position,
+		Inline:   segment.Inline(),
+	}
+	if segment.Inline() {
+		verification.Reconstructable = true
+		return verification, nil
+	}
+
+	redundancy, err := eestream.NewRedundancyStrategyFromStorj(segment.Redundancy)
+	if err != nil {
+		return SegmentVerification{}, invalidRepairError.New("invalid redundancy strategy: %w", err)
+	}
+
+	// the pieces are downloaded as by the repairer, but without allocating the
+	// bandwidth to a bucket, since verification isn't billed.
+	limits, privateKey, cachedIPsAndPorts, err := verifier.orders.CreateGetVerifyOrderLimits(ctx, segment, segment.Pieces)
+	if err != nil {
+		verifier.log.Debug("could not create order limits for verification",
+			zap.Stringer("Stream ID", segment.StreamID),
+			zap.Uint64("Position", segment.Position.Encode()),
+			zap.Error(err))
+		verification.Err = orderLimitFailureError.New("could not create GET_REPAIR order limits: %w", err)
+		return verification, nil
+	}
+
+	verification.Pieces, verification.Reconstructable, err = verifier.ec.Verify(ctx, limits, cachedIPsAndPorts, privateKey, redundancy, int64(segment.EncryptedSize))
+	if err != nil {
+		return SegmentVerification{}, err
+	}
+
+	return verification, nil
+}
@@ -189,12 +189,9 @@ func NewRepairer(log *zap.Logger, full *identity.FullIdentity,
 			peer.Overlay,
 			peer.Reputation,
 			peer.Dialer,
-			config.Repairer.Timeout,
-			config.Repairer.MaxExcessRateOptimalThreshold,
 			config.Checker.RepairOverrides,
-			config.Repairer.DownloadTimeout,
-			config.Repairer.InMemoryRepair,
 			signing.SigneeFromPeerIdentity(peer.Identity.PeerIdentity()),
+			config.Repairer,
 		)
 		peer.Repairer = repairer.NewService(log.Named("repairer"), repairQueue, &config.Repairer, peer.SegmentRepairer)
 
@@ -4,13 +4,18 @@
 package satellitedb
 
 import (
+	"bytes"
 	"context"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/jackc/pgtype"
 	"github.com/zeebo/errs"
 
 	"storj.io/common/lrucache"
 	"storj.io/common/uuid"
+	"storj.io/private/dbutil/pgutil"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/satellitedb/dbx"
 )
@@ -70,7 +75,7 @@ func (keys *apikeys) GetPagedByProjectID(ctx context.Context, projectID uuid.UUI
 	}
 
 	repoundQuery := keys.db.Rebind(`
-		SELECT ak.id, ak.project_id, ak.name, ak.partner_id, ak.created_at
+		SELECT ak.id, ak.project_id, ak.name, ak.partner_id, ak.created_at, ak.last_used_at
 		FROM api_keys ak
 		WHERE ak.project_id = ?
 		AND lower(ak.name) LIKE ?
@@ -95,7 +100,7 @@ func (keys *apikeys) GetPagedByProjectID(ctx context.Context, projectID uuid.UUI
 		ak := console.APIKeyInfo{}
 		var partnerID uuid.NullUUID
 
-		err = rows.Scan(&ak.ID, &ak.ProjectID, &ak.Name, &partnerID, &ak.CreatedAt)
+		err = rows.Scan(&ak.ID, &ak.ProjectID, &ak.Name, &partnerID, &ak.CreatedAt, &ak.LastUsedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -205,6 +210,134 @@ func (keys *apikeys) Update(ctx context.Context, key console.APIKeyInfo) (err er
 	)
 }
 
+// UpdateSecret implements satellite.APIKeys.
+func (keys *apikeys) UpdateSecret(ctx context.Context, id uuid.UUID, head, secret []byte) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	dbKey, err := keys.methods.Get_ApiKey_By_Id(ctx, dbx.ApiKey_Id(id[:]))
+	if err != nil {
+		return err
+	}
+
+	_, err = keys.db.ExecContext(ctx, keys.db.Rebind(`
+		UPDATE api_keys SET head = ?, secret = ? WHERE id = ?
+	`), head, secret, id[:])
+	if err != nil {
+		return err
+	}
+
+	// the previous head must not authorize requests from the cache.
+	keys.lru.Delete(string(dbKey.Head))
+
+	return nil
+}
+
+// UpdateUsage implements satellite.APIKeys.
+func (keys *apikeys) UpdateUsage(ctx context.Context, updates []console.APIKeyUsageUpdate) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	// sorting the updates avoids deadlocks with concurrent batches.
+	sort.Slice(updates, func(i, k int) bool {
+		if updates[i].APIKeyID != updates[k].APIKeyID {
+			return updates[i].APIKeyID.Less(updates[k].APIKeyID)
+		}
+		return updates[i].Day.Before(updates[k].Day)
+	})
+
+	ids := make([][]byte, len(updates))
+	days := make([]time.Time, len(updates))
+	requests := make([]int64, len(updates))
+	// the last use is updated once per key, with the latest use of all days.
+	var usedIDs [][]byte
+	var lastUsed []time.Time
+	for i, update := range updates {
+		day := update.Day.UTC()
+		ids[i] = updates[i].APIKeyID[:]
+		days[i] = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+		requests[i] = update.Requests
+
+		if update.LastUsedAt.IsZero() {
+			continue
+		}
+		last := len(usedIDs) - 1
+		if last >= 0 && bytes.Equal(usedIDs[last], ids[i]) {
+			if update.LastUsedAt.After(lastUsed[last]) {
+				lastUsed[last] = update.LastUsedAt
+			}
+			continue
+		}
+		usedIDs = append(usedIDs, ids[i])
+		lastUsed = append(lastUsed, update.LastUsedAt)
+	}
+
+	var dayArray pgtype.DateArray
+	if err := dayArray.Set(days); err != nil {
+		return Error.Wrap(err)
+	}
+
+	// the keys may have been deleted since their usage was recorded, joining
+	// against api_keys skips their usage instead of failing the whole batch.
+	_, err = keys.db.ExecContext(ctx, `
+		INSERT INTO api_key_usages (api_key_id, interval_day, request_count)
+		SELECT u.api_key_id, u.interval_day, u.request_count
+		FROM unnest($1::bytea[], $2::date[], $3::int8[]) AS u(api_key_id, interval_day, request_count)
+		JOIN api_keys ON api_keys.id = u.api_key_id
+		ON CONFLICT(api_key_id, interval_day)
+		DO UPDATE SET request_count = api_key_usages.request_count + EXCLUDED.request_count
+	`, pgutil.ByteaArray(ids), &dayArray, pgutil.Int8Array(requests))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	if len(usedIDs) == 0 {
+		return nil
+	}
+
+	_, err = keys.db.ExecContext(ctx, `
+		UPDATE api_keys SET last_used_at = GREATEST(api_keys.last_used_at, u.last_used_at)
+		FROM (
+			SELECT unnest($1::bytea[]) AS id, unnest($2::timestamptz[]) AS last_used_at
+		) AS u
+		WHERE api_keys.id = u.id
+	`, pgutil.ByteaArray(usedIDs), pgutil.TimestampTZArray(lastUsed))
+	return Error.Wrap(err)
+}
+
+// GetUsage implements satellite.APIKeys.
+func (keys *apikeys) GetUsage(ctx context.Context, id uuid.UUID, since time.Time) (_ []console.APIKeyDailyUsage, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	since = since.UTC()
+	sinceDay := time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, time.UTC)
+
+	rows, err := keys.db.QueryContext(ctx, keys.db.Rebind(`
+		SELECT interval_day, request_count
+		FROM api_key_usages
+		WHERE api_key_id = ? AND interval_day >= ?
+		ORDER BY interval_day
+	`), id[:], sinceDay)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var usage []console.APIKeyDailyUsage
+	for rows.Next() {
+		var day console.APIKeyDailyUsage
+		if err := rows.Scan(&day.Day, &day.Requests); err != nil {
+			return nil, err
+		}
+		day.Day = day.Day.UTC()
+		usage = append(usage, day)
+	}
+
+	return usage, rows.Err()
+}
+
 // Delete implements satellite.APIKeys.
 func (keys *apikeys) Delete(ctx context.Context, id uuid.UUID) (err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -226,11 +359,12 @@ func fromDBXAPIKey(ctx context.Context, key *dbx.ApiKey) (_ *console.APIKeyInfo,
 	}
 
 	result := &console.APIKeyInfo{
-		ID:        id,
-		ProjectID: projectID,
-		Name:      key.Name,
-		CreatedAt: key.CreatedAt,
-		Secret:    key.Secret,
+		ID:         id,
+		ProjectID:  projectID,
+		Name:       key.Name,
+		CreatedAt:  key.CreatedAt,
+		Secret:     key.Secret,
+		LastUsedAt: key.LastUsedAt,
 	}
 
 	if key.PartnerId != nil {
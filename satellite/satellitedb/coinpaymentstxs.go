@@ -63,6 +63,7 @@ func (db *coinPaymentsTransactions) Insert(ctx context.Context, tx stripecoinpay
 		dbx.CoinpaymentsTransaction_Status(tx.Status.Int()),
 		dbx.CoinpaymentsTransaction_Key(tx.Key),
 		dbx.CoinpaymentsTransaction_Timeout(int(tx.Timeout.Seconds())),
+		dbx.CoinpaymentsTransaction_Create_Fields{},
 	)
 	if err != nil {
 		return nil, err
@@ -89,8 +90,9 @@ func (db *coinPaymentsTransactions) Update(ctx context.Context, updates []stripe
 			_, err = tx.Update_CoinpaymentsTransaction_By_Id(ctx,
 				dbx.CoinpaymentsTransaction_Id(update.TransactionID.String()),
 				dbx.CoinpaymentsTransaction_Update_Fields{
-					Received: dbx.CoinpaymentsTransaction_Received(received),
-					Status:   dbx.CoinpaymentsTransaction_Status(update.Status.Int()),
+					Received:      dbx.CoinpaymentsTransaction_Received(received),
+					Status:        dbx.CoinpaymentsTransaction_Status(update.Status.Int()),
+					Confirmations: dbx.CoinpaymentsTransaction_Confirmations(update.Confirmations),
 				},
 			)
 			if err != nil {
@@ -376,15 +378,21 @@ func fromDBXCoinpaymentsTransaction(dbxCPTX *dbx.CoinpaymentsTransaction) (*stri
 		return nil, errs.Wrap(err)
 	}
 
+	var confirmations int
+	if dbxCPTX.Confirmations != nil {
+		confirmations = *dbxCPTX.Confirmations
+	}
+
 	return &stripecoinpayments.Transaction{
-		ID:        coinpayments.TransactionID(dbxCPTX.Id),
-		AccountID: userID,
-		Address:   dbxCPTX.Address,
-		Amount:    amount,
-		Received:  received,
-		Status:    coinpayments.Status(dbxCPTX.Status),
-		Key:       dbxCPTX.Key,
-		Timeout:   time.Second * time.Duration(dbxCPTX.Timeout),
-		CreatedAt: dbxCPTX.CreatedAt,
+		ID:            coinpayments.TransactionID(dbxCPTX.Id),
+		AccountID:     userID,
+		Address:       dbxCPTX.Address,
+		Amount:        amount,
+		Received:      received,
+		Status:        coinpayments.Status(dbxCPTX.Status),
+		Confirmations: confirmations,
+		Key:           dbxCPTX.Key,
+		Timeout:       time.Second * time.Duration(dbxCPTX.Timeout),
+		CreatedAt:     dbxCPTX.CreatedAt,
 	}, nil
 }
@@ -257,6 +257,83 @@ func (coupons *coupons) TotalUsage(ctx context.Context, couponID uuid.UUID) (_ i
 	return amount, err
 }
 
+// UsageSummary returns the value of coupons created and the coupon usage
+// charged for periods in [since, before), grouped by coupon type.
+func (coupons *coupons) UsageSummary(ctx context.Context, since, before time.Time) (_ []stripecoinpayments.CouponTypeUsage, err error) {
+	defer mon.Task()(&ctx, since, before)(&err)
+
+	usages := make(map[payments.CouponType]*stripecoinpayments.CouponTypeUsage)
+	usageOf := func(couponType payments.CouponType) *stripecoinpayments.CouponTypeUsage {
+		usage, ok := usages[couponType]
+		if !ok {
+			usage = &stripecoinpayments.CouponTypeUsage{Type: couponType}
+			usages[couponType] = usage
+		}
+		return usage
+	}
+
+	granted, err := coupons.sumByType(ctx, `
+		SELECT type, COALESCE(SUM(amount), 0)
+		FROM coupons
+		WHERE created_at >= ? AND created_at < ?
+		GROUP BY type
+	`, since, before)
+	if err != nil {
+		return nil, err
+	}
+	for couponType, amount := range granted {
+		usageOf(couponType).Granted = amount
+	}
+
+	used, err := coupons.sumByType(ctx, `
+		SELECT coupons.type, COALESCE(SUM(coupon_usages.amount), 0)
+		FROM coupon_usages
+		INNER JOIN coupons ON coupons.id = coupon_usages.coupon_id
+		WHERE coupon_usages.period >= ? AND coupon_usages.period < ?
+		GROUP BY coupons.type
+	`, since, before)
+	if err != nil {
+		return nil, err
+	}
+	for couponType, amount := range used {
+		usageOf(couponType).Used = amount
+	}
+
+	summary := make([]stripecoinpayments.CouponTypeUsage, 0, len(usages))
+	for _, usage := range usages {
+		summary = append(summary, *usage)
+	}
+	sort.Slice(summary, func(i, k int) bool {
+		return summary[i].Type < summary[k].Type
+	})
+
+	return summary, nil
+}
+
+// sumByType runs a query selecting a coupon type and an amount per row within
+// a time window and returns the amounts by type.
+func (coupons *coupons) sumByType(ctx context.Context, query string, since, before time.Time) (_ map[payments.CouponType]int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := coupons.db.QueryContext(ctx, coupons.db.Rebind(query), since.UTC(), before.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	amounts := make(map[payments.CouponType]int64)
+	for rows.Next() {
+		var couponType payments.CouponType
+		var amount int64
+		if err := rows.Scan(&couponType, &amount); err != nil {
+			return nil, err
+		}
+		amounts[couponType] = amount
+	}
+
+	return amounts, rows.Err()
+}
+
 // TotalUsage gets sum of all usage records for specified coupon.
 func (coupons *coupons) TotalUsageForPeriod(ctx context.Context, couponID uuid.UUID, period time.Time) (_ int64, err error) {
 	defer mon.Task()(&ctx, couponID)(&err)
@@ -425,6 +502,23 @@ func (coupons *coupons) PopulatePromotionalCoupons(ctx context.Context, users []
 	})
 }
 
+// PreviewPopulatePromotionalCoupons returns the users of the given ones which would get a promotional coupon
+// by PopulatePromotionalCoupons, without changing anything.
+func (coupons *coupons) PreviewPopulatePromotionalCoupons(ctx context.Context, users []uuid.UUID) (_ []uuid.UUID, err error) {
+	defer mon.Task()(&ctx, users)(&err)
+
+	ids, err := coupons.activeUserWithProjectAndWithoutCoupon(ctx, users)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		userIDs = append(userIDs, id.UserID)
+	}
+	return userIDs, nil
+}
+
 type userAndProject struct {
 	UserID    uuid.UUID
 	ProjectID uuid.UUID
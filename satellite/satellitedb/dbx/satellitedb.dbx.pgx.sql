@@ -62,6 +62,7 @@ CREATE TABLE coinpayments_transactions (
 	key text NOT NULL,
 	timeout integer NOT NULL,
 	created_at timestamp with time zone NOT NULL,
+	confirmations integer,
 	PRIMARY KEY ( id )
 );
 CREATE TABLE coupons (
@@ -406,6 +407,8 @@ CREATE TABLE users (
 	mfa_enabled boolean NOT NULL DEFAULT false,
 	mfa_secret_key text,
 	mfa_recovery_codes text,
+	deletion_requested_at timestamp with time zone,
+	token_epoch bigint NOT NULL DEFAULT 0,
 	PRIMARY KEY ( id )
 );
 CREATE TABLE value_attributions (
@@ -423,6 +426,7 @@ CREATE TABLE api_keys (
 	secret bytea NOT NULL,
 	partner_id bytea,
 	created_at timestamp with time zone NOT NULL,
+	last_used_at timestamp with time zone,
 	PRIMARY KEY ( id ),
 	UNIQUE ( head ),
 	UNIQUE ( name, project_id )
@@ -471,6 +475,12 @@ CREATE TABLE user_credits (
 	PRIMARY KEY ( id ),
 	UNIQUE ( id, offer_id )
 );
+CREATE TABLE api_key_usages (
+	api_key_id bytea NOT NULL REFERENCES api_keys( id ) ON DELETE CASCADE,
+	interval_day date NOT NULL,
+	request_count bigint NOT NULL,
+	PRIMARY KEY ( api_key_id, interval_day )
+);
 CREATE INDEX accounting_rollups_start_time_index ON accounting_rollups ( start_time ) ;
 CREATE INDEX bucket_bandwidth_rollups_project_id_action_interval_index ON bucket_bandwidth_rollups ( project_id, action, interval_start ) ;
 CREATE INDEX bucket_bandwidth_rollups_action_interval_project_id_index ON bucket_bandwidth_rollups ( action, interval_start, project_id ) ;
@@ -1613,6 +1613,51 @@ func (db *satelliteDB) PostgresMigration() *migrate.Migration {
 					`DROP TABLE audit_histories`,
 				},
 			},
+			{
+				DB:          &db.migrationDB,
+				Description: "add deletion_requested_at to users",
+				Version:     171,
+				Action: migrate.SQL{
+					`ALTER TABLE users ADD COLUMN deletion_requested_at timestamp with time zone;`,
+				},
+			},
+			{
+				DB:          &db.migrationDB,
+				Description: "add last_used_at to api_keys",
+				Version:     172,
+				Action: migrate.SQL{
+					`ALTER TABLE api_keys ADD COLUMN last_used_at timestamp with time zone;`,
+				},
+			},
+			{
+				DB:          &db.migrationDB,
+				Description: "add confirmations to coinpayments_transactions",
+				Version:     173,
+				Action: migrate.SQL{
+					`ALTER TABLE coinpayments_transactions ADD COLUMN confirmations integer;`,
+				},
+			},
+			{
+				DB:          &db.migrationDB,
+				Description: "add api_key_usages table",
+				Version:     174,
+				Action: migrate.SQL{
+					`CREATE TABLE api_key_usages (
+						api_key_id bytea NOT NULL REFERENCES api_keys( id ) ON DELETE CASCADE,
+						interval_day date NOT NULL,
+						request_count bigint NOT NULL,
+						PRIMARY KEY ( api_key_id, interval_day )
+					);`,
+				},
+			},
+			{
+				DB:          &db.migrationDB,
+				Description: "add token_epoch to users",
+				Version:     175,
+				Action: migrate.SQL{
+					`ALTER TABLE users ADD COLUMN token_epoch bigint NOT NULL DEFAULT 0;`,
+				},
+			},
 			// NB: after updating testdata in `testdata`, run
 			//     `go generate` to update `migratez.go`.
 		},
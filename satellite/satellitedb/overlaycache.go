@@ -233,6 +233,62 @@ func (cache *overlaycache) Get(ctx context.Context, id storj.NodeID) (dossier *o
 	return convertDBNode(ctx, node)
 }
 
+// GetNodes looks up the nodes by nodeIDs, only setting their address, disqualification,
+// suspension, graceful exit and last contact fields. Unknown nodes are left out.
+func (cache *overlaycache) GetNodes(ctx context.Context, nodeIDs []storj.NodeID) (nodes map[storj.NodeID]*overlay.NodeDossier, err error) {
+	for {
+		nodes, err = cache.getNodes(ctx, nodeIDs)
+		if err != nil {
+			if cockroachutil.NeedsRetry(err) {
+				continue
+			}
+			return nodes, err
+		}
+		break
+	}
+
+	return nodes, err
+}
+
+func (cache *overlaycache) getNodes(ctx context.Context, nodeIDs []storj.NodeID) (_ map[storj.NodeID]*overlay.NodeDossier, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	nodes := make(map[storj.NodeID]*overlay.NodeDossier, len(nodeIDs))
+	if len(nodeIDs) == 0 {
+		return nodes, nil
+	}
+
+	rows, err := cache.db.Query(ctx, cache.db.Rebind(`
+		SELECT id, last_net, last_ip_port, address, protocol,
+			disqualified, unknown_audit_suspended, offline_suspended,
+			exit_initiated_at, exit_loop_completed_at, exit_finished_at, exit_success,
+			last_contact_success, last_contact_failure
+		FROM nodes
+		WHERE id = any($1::bytea[])
+	`), pgutil.NodeIDArray(nodeIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		row := &dbx.Node{}
+		err = rows.Scan(&row.Id, &row.LastNet, &row.LastIpPort, &row.Address, &row.Protocol,
+			&row.Disqualified, &row.UnknownAuditSuspended, &row.OfflineSuspended,
+			&row.ExitInitiatedAt, &row.ExitLoopCompletedAt, &row.ExitFinishedAt, &row.ExitSuccess,
+			&row.LastContactSuccess, &row.LastContactFailure)
+		if err != nil {
+			return nil, err
+		}
+		node, err := convertDBNode(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		nodes[node.Id] = node
+	}
+	return nodes, Error.Wrap(rows.Err())
+}
+
 // GetOnlineNodesForGetDelete returns a map of nodes for the supplied nodeIDs.
 func (cache *overlaycache) GetOnlineNodesForGetDelete(ctx context.Context, nodeIDs []storj.NodeID, onlineWindow time.Duration) (nodes map[storj.NodeID]*overlay.SelectedNode, err error) {
 	for {
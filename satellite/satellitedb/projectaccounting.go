@@ -346,6 +346,129 @@ func (db *ProjectAccounting) GetProjectTotal(ctx context.Context, projectID uuid
 	return usage, nil
 }
 
+// GetProjectUsageSeries returns project storage and egress for every interval of the specified period of time.
+// The period is aligned to the interval, so the first point starts at or before since.
+func (db *ProjectAccounting) GetProjectUsageSeries(ctx context.Context, projectID uuid.UUID, since, before time.Time, interval time.Duration) (_ *accounting.ProjectUsageSeries, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if interval <= 0 {
+		return nil, Error.New("invalid interval: %s", interval)
+	}
+
+	since = since.UTC().Truncate(interval)
+	before = before.UTC()
+
+	count := int((before.Sub(since) + interval - 1) / interval)
+	if count <= 0 {
+		return nil, Error.New("before must be after since")
+	}
+
+	series := &accounting.ProjectUsageSeries{
+		Storage: make([]accounting.UsagePoint, count),
+		Egress:  make([]accounting.UsagePoint, count),
+		Since:   since,
+		Before:  before,
+	}
+	for i := range series.Storage {
+		timestamp := since.Add(time.Duration(i) * interval)
+		series.Storage[i].Timestamp = timestamp
+		series.Egress[i].Timestamp = timestamp
+	}
+
+	pointIndex := func(intervalStart time.Time) int {
+		return int(intervalStart.Sub(since) / interval)
+	}
+
+	storageQuery := db.db.Rebind(`
+		SELECT
+			bucket_name,
+			interval_start,
+			total_bytes,
+			inline,
+			remote
+		FROM
+			bucket_storage_tallies
+		WHERE
+			project_id = ? AND
+			interval_start >= ? AND
+			interval_start < ?
+		ORDER BY interval_start ASC
+	`)
+
+	// only the last tally of every bucket during an interval counts towards its storage.
+	bucketStorage := make([]map[string]int64, count)
+
+	storageRows, err := db.db.QueryContext(ctx, storageQuery, projectID[:], since, before)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	for storageRows.Next() {
+		var bucketName []byte
+		var intervalStart time.Time
+		var totalBytes, inline, remote int64
+
+		err = storageRows.Scan(&bucketName, &intervalStart, &totalBytes, &inline, &remote)
+		if err != nil {
+			return nil, Error.Wrap(errs.Combine(err, storageRows.Close()))
+		}
+		if totalBytes == 0 {
+			totalBytes = inline + remote
+		}
+
+		index := pointIndex(intervalStart)
+		if bucketStorage[index] == nil {
+			bucketStorage[index] = make(map[string]int64)
+		}
+		bucketStorage[index][string(bucketName)] = totalBytes
+	}
+	err = errs.Combine(storageRows.Err(), storageRows.Close())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	for i, buckets := range bucketStorage {
+		for _, bytes := range buckets {
+			series.Storage[i].Value += bytes
+		}
+	}
+
+	egressQuery := db.db.Rebind(`
+		SELECT
+			interval_start,
+			COALESCE(SUM(settled) + SUM(inline), 0)
+		FROM
+			bucket_bandwidth_rollups
+		WHERE
+			project_id = ? AND
+			interval_start >= ? AND
+			interval_start < ? AND
+			action = ?
+		GROUP BY interval_start
+	`)
+
+	egressRows, err := db.db.QueryContext(ctx, egressQuery, projectID[:], since, before, pb.PieceAction_GET)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	for egressRows.Next() {
+		var intervalStart time.Time
+		var egress int64
+
+		err = egressRows.Scan(&intervalStart, &egress)
+		if err != nil {
+			return nil, Error.Wrap(errs.Combine(err, egressRows.Close()))
+		}
+
+		series.Egress[pointIndex(intervalStart)].Value += egress
+	}
+	err = errs.Combine(egressRows.Err(), egressRows.Close())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return series, nil
+}
+
 // getTotalEgress returns total egress (settled + inline) of each bucket_bandwidth_rollup
 // in selected time period, project id.
 // only process PieceAction_GET.
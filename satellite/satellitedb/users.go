@@ -20,7 +20,8 @@ var _ console.Users = (*users)(nil)
 
 // implementation of Users interface repository using spacemonkeygo/dbx orm.
 type users struct {
-	db dbx.Methods
+	db  dbx.Methods
+	sdb *satelliteDB
 }
 
 // Get is a method for querying user from the database by id.
@@ -129,6 +130,18 @@ func (users *users) UpdatePaidTier(ctx context.Context, id uuid.UUID, paidTier b
 	return err
 }
 
+// BumpTokenEpoch increments the token epoch of the user and returns the new epoch.
+func (users *users) BumpTokenEpoch(ctx context.Context, id uuid.UUID) (epoch int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = users.sdb.QueryRowContext(ctx, users.sdb.Rebind(`
+		UPDATE users SET token_epoch = token_epoch + 1
+		WHERE id = ?
+		RETURNING token_epoch
+	`), id[:]).Scan(&epoch)
+	return epoch, err
+}
+
 // GetProjectLimit is a method to get the users project limit.
 func (users *users) GetProjectLimit(ctx context.Context, id uuid.UUID) (limit int, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -159,6 +172,21 @@ func toUpdateUser(user *console.User) (*dbx.User_Update_Fields, error) {
 	}
 	update.MfaRecoveryCodes = dbx.User_MfaRecoveryCodes(string(recoveryBytes))
 	update.MfaSecretKey = dbx.User_MfaSecretKey(user.MFASecretKey)
+	update.DeletionRequestedAt = dbx.User_DeletionRequestedAt_Raw(user.DeletionRequestedAt)
+
+	update.IsProfessional = dbx.User_IsProfessional(user.IsProfessional)
+	update.HaveSalesContact = dbx.User_HaveSalesContact(user.HaveSalesContact)
+	if user.IsProfessional {
+		update.Position = dbx.User_Position(user.Position)
+		update.CompanyName = dbx.User_CompanyName(user.CompanyName)
+		update.WorkingOn = dbx.User_WorkingOn(user.WorkingOn)
+		update.EmployeeCount = dbx.User_EmployeeCount(user.EmployeeCount)
+	} else {
+		update.Position = dbx.User_Position_Null()
+		update.CompanyName = dbx.User_CompanyName_Null()
+		update.WorkingOn = dbx.User_WorkingOn_Null()
+		update.EmployeeCount = dbx.User_EmployeeCount_Null()
+	}
 
 	// extra password check to update only calculated hash from service
 	if len(user.PasswordHash) != 0 {
@@ -200,6 +228,7 @@ func userFromDBX(ctx context.Context, user *dbx.User) (_ *console.User, err erro
 		IsProfessional:   user.IsProfessional,
 		HaveSalesContact: user.HaveSalesContact,
 		MFAEnabled:       user.MfaEnabled,
+		TokenEpoch:       user.TokenEpoch,
 	}
 
 	if user.PartnerId != nil {
@@ -237,6 +266,8 @@ func userFromDBX(ctx context.Context, user *dbx.User) (_ *console.User, err erro
 		result.MFARecoveryCodes = recoveryCodes
 	}
 
+	result.DeletionRequestedAt = user.DeletionRequestedAt
+
 	return &result, nil
 }
 
@@ -0,0 +1,103 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package preflight
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/common/rpc"
+	"storj.io/common/storj"
+	"storj.io/private/version"
+	"storj.io/storj/storagenode/trust"
+)
+
+// SelfTest reports the running version and whether all trusted satellites
+// can be reached.
+type SelfTest struct {
+	log         *zap.Logger
+	versionInfo version.Info
+	trust       *trust.Pool
+	dialer      rpc.Dialer
+}
+
+// SelfTestReport is the result of a self test.
+type SelfTestReport struct {
+	Version    version.Info
+	Satellites []SatelliteStatus
+}
+
+// SatelliteStatus is the reachability of a trusted satellite.
+type SatelliteStatus struct {
+	ID      storj.NodeID
+	Address string
+	// Err is the reason why the satellite is unreachable, nil when it's reachable.
+	Err error
+}
+
+// Reachable returns whether the node could connect to the satellite.
+func (status SatelliteStatus) Reachable() bool {
+	return status.Err == nil
+}
+
+// NewSelfTest creates a new self test instance.
+func NewSelfTest(log *zap.Logger, versionInfo version.Info, trust *trust.Pool, dialer rpc.Dialer) *SelfTest {
+	return &SelfTest{
+		log:         log,
+		versionInfo: versionInfo,
+		trust:       trust,
+		dialer:      dialer,
+	}
+}
+
+// Run connects to every trusted satellite. A satellite is reachable when the
+// TLS handshake succeeds and the satellite presents the expected node ID.
+func (selfTest *SelfTest) Run(ctx context.Context) (_ SelfTestReport, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	satellites := selfTest.trust.GetSatellites(ctx)
+	statuses := make([]SatelliteStatus, len(satellites))
+
+	var group errgroup.Group
+	for i, satellite := range satellites {
+		i, satellite := i, satellite
+		group.Go(func() error {
+			statuses[i] = selfTest.checkSatellite(ctx, satellite)
+			if statuses[i].Err != nil {
+				selfTest.log.Debug("satellite is unreachable", zap.Stringer("Satellite ID", satellite), zap.Error(statuses[i].Err))
+			}
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	return SelfTestReport{
+		Version:    selfTest.versionInfo,
+		Satellites: statuses,
+	}, nil
+}
+
+func (selfTest *SelfTest) checkSatellite(ctx context.Context, satelliteID storj.NodeID) (status SatelliteStatus) {
+	defer mon.Task()(&ctx)(&status.Err)
+
+	status.ID = satelliteID
+
+	nodeurl, err := selfTest.trust.GetNodeURL(ctx, satelliteID)
+	if err != nil {
+		status.Err = err
+		return status
+	}
+	status.Address = nodeurl.Address
+
+	conn, err := selfTest.dialer.DialNodeURL(ctx, nodeurl)
+	if err != nil {
+		status.Err = err
+		return status
+	}
+	status.Err = errs.Wrap(conn.Close())
+	return status
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2021 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package preflight_test
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/common/identity/testidentity"
+	"storj.io/common/pb"
+	"storj.io/common/peertls/extensions"
+	"storj.io/common/peertls/tlsopts"
+	"storj.io/common/rpc"
+	"storj.io/common/testcontext"
+	"storj.io/private/version"
+	"storj.io/storj/private/server"
+	"storj.io/storj/storagenode/preflight"
+	"storj.io/storj/storagenode/trust"
+)
+
+func TestSelfTest(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	log := zaptest.NewLogger(t)
+
+	config := server.Config{
+		Address:        "127.0.0.1:0",
+		PrivateAddress: "127.0.0.1:0",
+
+		Config: tlsopts.Config{
+			PeerIDVersions: "*",
+			Extensions: extensions.Config{
+				Revocation:          false,
+				WhitelistSignedLeaf: false,
+			},
+		},
+	}
+
+	// set up a reachable mock satellite
+	reachableID, err := testidentity.NewTestIdentity(ctx)
+	require.NoError(t, err)
+	reachableTLSOptions, err := tlsopts.NewOptions(reachableID, config.Config, nil)
+	require.NoError(t, err)
+
+	var group errgroup.Group
+	defer ctx.Check(group.Wait)
+
+	contactServer, err := server.New(log, reachableTLSOptions, config)
+	require.NoError(t, err)
+	defer ctx.Check(contactServer.Close)
+
+	err = pb.DRPCRegisterNode(contactServer.DRPC(), &mockServer{localTime: time.Now()})
+	require.NoError(t, err)
+
+	group.Go(func() error {
+		return contactServer.Run(ctx)
+	})
+
+	_, portStr, err := net.SplitHostPort(contactServer.Addr().String())
+	require.NoError(t, err)
+	reachablePort, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	// an unreachable mock satellite listens on a port which is closed again
+	unreachableID, err := testidentity.NewTestIdentity(ctx)
+	require.NoError(t, err)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	unreachablePort := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	var sources trust.Sources
+	for _, url := range []trust.SatelliteURL{
+		{ID: reachableID.ID, Host: "127.0.0.1", Port: reachablePort},
+		{ID: unreachableID.ID, Host: "127.0.0.1", Port: unreachablePort},
+	} {
+		source, err := trust.NewStaticURLSource(url.String())
+		require.NoError(t, err)
+		sources = append(sources, source)
+	}
+
+	// set up storagenode client
+	identity, err := testidentity.NewTestIdentity(ctx)
+	require.NoError(t, err)
+	tlsOptions, err := tlsopts.NewOptions(identity, config.Config, nil)
+	require.NoError(t, err)
+	dialer := rpc.NewDefaultDialer(tlsOptions)
+	pool, err := trust.NewPool(log, trust.Dialer(dialer), trust.Config{
+		Sources:   sources,
+		CachePath: ctx.File("trust-cache.json"),
+	}, nil)
+	require.NoError(t, err)
+	require.NoError(t, pool.Refresh(ctx))
+
+	semVer, err := version.NewSemVer("v1.2.3")
+	require.NoError(t, err)
+	versionInfo := version.Info{
+		CommitHash: "abcdef",
+		Version:    semVer,
+		Release:    true,
+	}
+
+	report, err := preflight.NewSelfTest(log, versionInfo, pool, dialer).Run(ctx)
+	require.NoError(t, err)
+	require.Equal(t, versionInfo, report.Version)
+	require.Len(t, report.Satellites, 2)
+
+	statuses := make(map[string]preflight.SatelliteStatus)
+	for _, status := range report.Satellites {
+		statuses[status.ID.String()] = status
+	}
+
+	reachable := statuses[reachableID.ID.String()]
+	require.True(t, reachable.Reachable(), reachable.Err)
+	require.Equal(t, "127.0.0.1:"+strconv.Itoa(reachablePort), reachable.Address)
+
+	unreachable := statuses[unreachableID.ID.String()]
+	require.False(t, unreachable.Reachable())
+	require.Error(t, unreachable.Err)
+	require.Equal(t, "127.0.0.1:"+strconv.Itoa(unreachablePort), unreachable.Address)
+}